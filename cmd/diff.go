@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/snapshot"
+)
+
+// diffSnapshot is the newer snapshot when diff compares two files
+var diffSnapshot *snapshot.Snapshot
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Show what changed in the library since a snapshot",
+	Long: `Compare two library snapshots, or a snapshot with the live library, and
+report movies added and removed, newly watched and newly requested movies,
+files that changed quality or custom formats, and the movies each filter
+newly matches or no longer matches.
+
+Without arguments the live library is compared with the one saved by the
+previous run of diff, which is then replaced, so a weekly cron job reports
+only what changed that week. Nothing is printed when nothing changed.`,
+	Args:    cobra.MaximumNArgs(2),
+	PreRunE: initializeDiff,
+	RunE:    runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// initializeDiff connects to the services unless both sides of the diff are files
+func initializeDiff(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return initializeApp(cmd, args)
+	}
+
+	var err error
+	diffSnapshot, err = snapshot.Load(args[1])
+	if err != nil {
+		return err
+	}
+	return initializeOffline(cmd, args, diffSnapshot)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	var (
+		previous, current *snapshot.Snapshot
+		err               error
+	)
+	switch len(args) {
+	case 2:
+		if previous, err = snapshot.Load(args[0]); err != nil {
+			return err
+		}
+		current = diffSnapshot
+	case 1:
+		if previous, err = snapshot.Load(args[0]); err != nil {
+			return err
+		}
+		if current, err = takeSnapshot(ctx); err != nil {
+			return err
+		}
+	default:
+		return diffLastRun(ctx)
+	}
+
+	return printLibraryDiff(ctx, previous, current)
+}
+
+// diffLastRun compares the live library with the one saved by the previous
+// run and saves the live library for the next one
+func diffLastRun(ctx context.Context) error {
+	if cfg.DataDir == "" {
+		return fmt.Errorf("data_dir is required to compare with the last run")
+	}
+	path := filepath.Join(cfg.DataDir, "last-snapshot.json")
+
+	current, err := takeSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous, err := snapshot.Load(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logger.Info().Str("file", path).Msg("No previous run to compare with, saving the library for the next one")
+	case err != nil:
+		return err
+	default:
+		if err := printLibraryDiff(ctx, previous, current); err != nil {
+			return err
+		}
+	}
+
+	return snapshot.Save(path, *current)
+}
+
+// printLibraryDiff prints what changed between two snapshots, nothing when nothing did
+func printLibraryDiff(ctx context.Context, previous, current *snapshot.Snapshot) error {
+	diff := snapshot.Compare(previous.Movies, current.Movies)
+
+	previousMatches, err := filterMatches(ctx, previous.Movies)
+	if err != nil {
+		return err
+	}
+	currentMatches, err := filterMatches(ctx, current.Movies)
+	if err != nil {
+		return err
+	}
+	diff.Filters = snapshot.CompareMatches(previousMatches, currentMatches)
+
+	if diff.Empty() {
+		logger.Info().Time("since", previous.CreatedAt).Msg("Nothing changed")
+		return nil
+	}
+
+	fmt.Printf("Changes since %s\n", previous.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	printMovieSection("Added", "+", diff.Added)
+	printMovieSection("Removed", "-", diff.Removed)
+
+	if len(diff.NewlyWatched) > 0 {
+		fmt.Printf("\nNewly watched (%d)\n", len(diff.NewlyWatched))
+		for _, change := range diff.NewlyWatched {
+			line := movieLine(change.Movie)
+			if len(change.Users) > 0 {
+				line += " by " + strings.Join(change.Users, ", ")
+			}
+			fmt.Printf("  * %s\n", line)
+		}
+	}
+
+	if len(diff.NewlyRequested) > 0 {
+		fmt.Printf("\nNewly requested (%d)\n", len(diff.NewlyRequested))
+		for _, movie := range diff.NewlyRequested {
+			line := movieLine(movie)
+			if movie.RequestedBy != "" {
+				line += " by " + movie.RequestedBy
+			}
+			fmt.Printf("  * %s\n", line)
+		}
+	}
+
+	if len(diff.FileChanged) > 0 {
+		fmt.Printf("\nFile changed (%d)\n", len(diff.FileChanged))
+		for _, change := range diff.FileChanged {
+			fmt.Printf("  * %s: %s\n", movieLine(change.Movie), describeFileChange(change))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(diff.Filters)) {
+		change := diff.Filters[name]
+		fmt.Printf("\nFilter %s\n", name)
+		for _, movie := range change.Matching {
+			fmt.Printf("  + %s\n", movieLine(movie))
+		}
+		for _, movie := range change.NotMatching {
+			fmt.Printf("  - %s\n", movieLine(movie))
+		}
+	}
+
+	return nil
+}
+
+// filterMatches returns the movies each enabled filter matches
+func filterMatches(ctx context.Context, movies []radarr.MovieInfo) (map[string][]radarr.MovieInfo, error) {
+	filters := cfg.Filter.Enabled()
+
+	manager := filter.NewManager()
+	defer manager.Close(ctx)
+
+	expressions := make(map[string]string, len(filters))
+	for filterName, def := range filters {
+		expressions[filterName] = def.Expression
+	}
+	if err := manager.RegisterFilters(expressions); err != nil {
+		return nil, err
+	}
+	for filterName, def := range filters {
+		if err := manager.SetMinWatchPercent(filterName, def.MinWatchPercent); err != nil {
+			return nil, err
+		}
+	}
+
+	matches, err := manager.EvaluateAll(ctx, movies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate filters: %w", err)
+	}
+	restrictToFilterInstances(filters, matches)
+	return matches, nil
+}
+
+func printMovieSection(title, marker string, movies []radarr.MovieInfo) {
+	if len(movies) == 0 {
+		return
+	}
+	fmt.Printf("\n%s (%d)\n", title, len(movies))
+	for _, movie := range movies {
+		fmt.Printf("  %s %s\n", marker, movieLine(movie))
+	}
+}
+
+func movieLine(movie radarr.MovieInfo) string {
+	return fmt.Sprintf("%s (%d)%s", movie.Title, movie.Year, instanceLabel(movie))
+}
+
+// describeFileChange summarises how a movie's file changed
+func describeFileChange(change snapshot.FileChange) string {
+	var parts []string
+	if change.OldQuality != change.NewQuality {
+		parts = append(parts, fmt.Sprintf("%s → %s", qualityName(change.OldQuality), qualityName(change.NewQuality)))
+	}
+
+	var formats []string
+	for _, name := range change.AddedFormats {
+		formats = append(formats, "+"+name)
+	}
+	for _, name := range change.RemovedFormats {
+		formats = append(formats, "-"+name)
+	}
+	if len(formats) > 0 {
+		parts = append(parts, "custom formats "+strings.Join(formats, ", "))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func qualityName(quality string) string {
+	if quality == "" {
+		return "no file"
+	}
+	return quality
+}
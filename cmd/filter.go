@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// filterCmd groups commands for inspecting configured filters
+var filterCmd = &cobra.Command{
+	Use:   "filter",
+	Short: "Inspect configured filters",
+}
+
+// filterExplainCmd represents the filter explain command
+var filterExplainCmd = &cobra.Command{
+	Use:   "explain <filter> <movie>",
+	Short: "Show why a movie matches a filter",
+	Long: `Evaluate a configured filter against a single movie and print every
+sub-expression with the value it produced, so you can see exactly which
+clause made the filter match (or not).
+
+The movie can be given as a TMDB ID or as a title.`,
+	Args:    cobra.ExactArgs(2),
+	PreRunE: initializeWithSnapshot,
+	RunE:    runFilterExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(filterCmd)
+	filterCmd.AddCommand(filterExplainCmd)
+
+	filterExplainCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "explain against a saved snapshot instead of the live services")
+}
+
+func runFilterExplain(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	filterName, movieQuery := args[0], args[1]
+
+	def, ok := cfg.Filter[filterName]
+	if !ok {
+		return fmt.Errorf("filter '%s' not found in configuration", filterName)
+	}
+
+	ctx := context.Background()
+	allMovies, err := loadMovies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get movies: %w", err)
+	}
+
+	filter.AnnotateCollections(allMovies)
+	filter.AnnotateInstances(allMovies)
+
+	// A filter that targets one instance is explained against that instance's copy
+	candidates := slices.DeleteFunc(slices.Clone(allMovies), func(movie radarr.MovieInfo) bool {
+		return !inFilterInstance(def, movie)
+	})
+
+	movie, err := findMovie(candidates, movieQuery)
+	if err != nil {
+		return err
+	}
+
+	compiled, err := filter.CompileFilter(def.Expression)
+	if err != nil {
+		return fmt.Errorf("failed to explain filter '%s': %w", filterName, err)
+	}
+	explainer, ok := filter.WithMinWatchPercent(compiled, def.MinWatchPercent).(filter.Explainer)
+	if !ok {
+		return fmt.Errorf("filter '%s' does not support explain", filterName)
+	}
+	steps, err := explainer.Explain(movie)
+	if err != nil {
+		return fmt.Errorf("failed to explain filter '%s': %w", filterName, err)
+	}
+
+	fmt.Printf("\nFilter: %s\n", filterName)
+	fmt.Printf("Movie:  %s (%d) [TMDB %d]%s\n\n", movie.Title, movie.Year, movie.TMDBID, instanceLabel(movie))
+
+	for _, step := range steps {
+		indent := strings.Repeat("    ", step.Depth)
+		if step.Err != nil {
+			fmt.Printf("%s%s = error: %v\n", indent, step.Expression, step.Err)
+			continue
+		}
+		fmt.Printf("%s%s = %s\n", indent, step.Expression, formatExplainValue(step.Value))
+	}
+
+	matched := len(steps) > 0 && steps[0].Err == nil && steps[0].Value == true
+	if matched {
+		fmt.Println("\nResult: matched")
+	} else {
+		fmt.Println("\nResult: not matched")
+	}
+
+	return nil
+}
+
+// findMovie looks up a single movie by TMDB ID or title
+func findMovie(movies []radarr.MovieInfo, query string) (radarr.MovieInfo, error) {
+	if tmdbID, err := strconv.ParseInt(query, 10, 64); err == nil {
+		for _, movie := range movies {
+			if movie.TMDBID == tmdbID {
+				return movie, nil
+			}
+		}
+	}
+
+	var partial []radarr.MovieInfo
+	for _, movie := range movies {
+		if strings.EqualFold(movie.Title, query) {
+			return movie, nil
+		}
+		if strings.Contains(strings.ToLower(movie.Title), strings.ToLower(query)) {
+			partial = append(partial, movie)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return radarr.MovieInfo{}, fmt.Errorf("no movie found matching '%s'", query)
+	case 1:
+		return partial[0], nil
+	}
+
+	var candidates []string
+	for _, movie := range partial {
+		candidates = append(candidates, fmt.Sprintf("  - %s (%d) [TMDB %d]", movie.Title, movie.Year, movie.TMDBID))
+	}
+	return radarr.MovieInfo{}, fmt.Errorf("'%s' matches %d movies, use the TMDB ID instead:\n%s",
+		query, len(partial), strings.Join(candidates, "\n"))
+}
+
+// formatExplainValue renders an evaluated value for explain output
+func formatExplainValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case time.Time:
+		if v.IsZero() {
+			return "never"
+		}
+		return v.Format("2006-01-02")
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	if reflect.TypeOf(value).Kind() == reflect.Struct {
+		return fmt.Sprintf("%T{...}", value)
+	}
+	return fmt.Sprintf("%v", value)
+}
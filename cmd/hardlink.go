@@ -49,45 +49,42 @@ func runHardlink(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to scan for non-hardlinked movies: %w", err)
 	}
 
+	if err := printFormatted(formatter.FormatHardlinkResults(nonHardlinkedMovies)); err != nil {
+		return err
+	}
 	if len(nonHardlinkedMovies) == 0 {
-		fmt.Println("✓ All movies are properly hardlinked!")
 		return nil
 	}
-
-	fmt.Printf("Found %d non-hardlinked movie", len(nonHardlinkedMovies))
-	if len(nonHardlinkedMovies) != 1 {
-		fmt.Printf("s")
-	}
-	fmt.Println(".")
+	fmt.Fprintln(console())
 
 	// Process each movie interactively
 	var processedCount, reimportedCount, deletedCount, skippedCount int
 
 	for i, movie := range nonHardlinkedMovies {
 		// Display movie information
-		fmt.Printf("[%d/%d] %s (%d)\n", i+1, len(nonHardlinkedMovies), movie.Title, movie.Year)
-		fmt.Println(strings.Repeat("━", 50))
+		fmt.Fprintf(console(), "[%d/%d] %s (%d)\n", i+1, len(nonHardlinkedMovies), movie.Title, movie.Year)
+		fmt.Fprintln(console(), strings.Repeat("━", 50))
 
 		if movie.MovieFile != nil && movie.MovieFile.Path != "" {
-			fmt.Printf("Path: %s\n", movie.MovieFile.Path)
+			fmt.Fprintf(console(), "Path: %s\n", movie.MovieFile.Path)
 			if movie.MovieFile.Size > 0 {
-				fmt.Printf("Size: %s\n", formatSize(movie.MovieFile.Size))
+				fmt.Fprintf(console(), "Size: %s\n", formatSize(movie.MovieFile.Size))
 			}
 		}
 
-		fmt.Printf("Hardlinks: %d (not hardlinked)\n", movie.HardlinkCount)
+		fmt.Fprintf(console(), "Hardlinks: %d (not hardlinked)\n", movie.HardlinkCount)
 
 		// Show qBittorrent status
 		statusHandled := false
 
 		if movie.IsSeeding {
-			fmt.Printf("Status: ✓ Found in qBittorrent (actively seeding)\n\n")
+			fmt.Fprintf(console(), "Status: ✓ Found in qBittorrent (actively seeding)\n\n")
 			statusHandled = true
 
 			if !dryRun {
 				response := "n"
 				if !noConfirmHardlink {
-					fmt.Printf("→ Re-import from qBittorrent to create hardlink? [y/n/q]: ")
+					fmt.Fprintf(console(), "→ Re-import from qBittorrent to create hardlink? [y/n/q]: ")
 					fmt.Scanln(&response)
 				} else {
 					response = "y"
@@ -96,27 +93,27 @@ func runHardlink(cmd *cobra.Command, args []string) error {
 				response = strings.ToLower(strings.TrimSpace(response))
 
 				if response == "q" || response == "quit" {
-					fmt.Printf("\nProcessing stopped by user.\n")
+					fmt.Fprintf(console(), "\nProcessing stopped by user.\n")
 					break
 				} else if response == "y" || response == "yes" {
 					if err := operations.ReimportMovieFromQBittorrent(ctx, movie); err != nil {
 						logger.Error().Err(err).Str("movie", movie.Title).Msg("Failed to re-import movie")
-						fmt.Printf("✗ Failed to re-import: %v\n", err)
+						fmt.Fprintf(console(), "✗ Failed to re-import: %v\n", err)
 					} else {
-						fmt.Printf("✓ Re-imported successfully\n")
+						fmt.Fprintf(console(), "✓ Re-imported successfully\n")
 						reimportedCount++
 					}
 				} else {
-					fmt.Printf("⊘ Skipped\n")
+					fmt.Fprintf(console(), "⊘ Skipped\n")
 					skippedCount++
 				}
 			} else {
-				fmt.Printf("[DRY RUN] Would re-import from qBittorrent\n")
+				fmt.Fprintf(console(), "[DRY RUN] Would re-import from qBittorrent\n")
 			}
 		}
 
 		if !statusHandled && len(movie.AlternateTorrents) > 0 {
-			fmt.Printf("Status: △ Alternate torrents available in qBittorrent\n")
+			fmt.Fprintf(console(), "Status: △ Alternate torrents available in qBittorrent\n")
 			statusHandled = true
 
 			for idx, match := range movie.AlternateTorrents {
@@ -155,15 +152,15 @@ func runHardlink(cmd *cobra.Command, args []string) error {
 					statusParts = append(statusParts, "Year match")
 				}
 
-				fmt.Printf("  [%d] %s\n", idx+1, torrent.Name)
-				fmt.Printf("      %s | Size %s%s\n", strings.Join(statusParts, " | "), sizeLabel, diffLabel)
+				fmt.Fprintf(console(), "  [%d] %s\n", idx+1, torrent.Name)
+				fmt.Fprintf(console(), "      %s | Size %s%s\n", strings.Join(statusParts, " | "), sizeLabel, diffLabel)
 			}
-			fmt.Println()
+			fmt.Fprintln(console())
 
 			if !dryRun {
 				response := "n"
 				if !noConfirmHardlink {
-					fmt.Printf("→ Choose alternate torrent to re-import [1-%d/n/q]: ", len(movie.AlternateTorrents))
+					fmt.Fprintf(console(), "→ Choose alternate torrent to re-import [1-%d/n/q]: ", len(movie.AlternateTorrents))
 					fmt.Scanln(&response)
 				} else {
 					response = "1"
@@ -172,43 +169,43 @@ func runHardlink(cmd *cobra.Command, args []string) error {
 				response = strings.ToLower(strings.TrimSpace(response))
 
 				if response == "q" || response == "quit" {
-					fmt.Printf("\nProcessing stopped by user.\n")
+					fmt.Fprintf(console(), "\nProcessing stopped by user.\n")
 					break
 				} else if response == "n" || response == "" || response == "no" {
-					fmt.Printf("⊘ Skipped\n")
+					fmt.Fprintf(console(), "⊘ Skipped\n")
 					skippedCount++
 				} else {
 					index, err := strconv.Atoi(response)
 					if err != nil || index < 1 || index > len(movie.AlternateTorrents) {
-						fmt.Printf("⊘ Invalid selection (skipped)\n")
+						fmt.Fprintf(console(), "⊘ Invalid selection (skipped)\n")
 						skippedCount++
 					} else {
 						match := movie.AlternateTorrents[index-1]
 						if err := operations.ReimportMovieFromTorrentMatch(ctx, movie, match); err != nil {
 							logger.Error().Err(err).Str("movie", movie.Title).Msg("Failed to re-import movie from alternate torrent")
-							fmt.Printf("✗ Failed to re-import: %v\n", err)
+							fmt.Fprintf(console(), "✗ Failed to re-import: %v\n", err)
 						} else {
-							fmt.Printf("✓ Re-imported using \"%s\"\n", match.Torrent.Name)
+							fmt.Fprintf(console(), "✓ Re-imported using \"%s\"\n", match.Torrent.Name)
 							reimportedCount++
 						}
 					}
 				}
 			} else {
 				if len(movie.AlternateTorrents) > 0 && movie.AlternateTorrents[0] != nil && movie.AlternateTorrents[0].Torrent != nil {
-					fmt.Printf("[DRY RUN] Would re-import using alternate torrent \"%s\"\n", movie.AlternateTorrents[0].Torrent.Name)
+					fmt.Fprintf(console(), "[DRY RUN] Would re-import using alternate torrent \"%s\"\n", movie.AlternateTorrents[0].Torrent.Name)
 				} else {
-					fmt.Printf("[DRY RUN] Would re-import using highest ranked alternate torrent\n")
+					fmt.Fprintf(console(), "[DRY RUN] Would re-import using highest ranked alternate torrent\n")
 				}
 			}
 		}
 
 		if !statusHandled {
-			fmt.Printf("Status: ✗ Not found in qBittorrent\n\n")
+			fmt.Fprintf(console(), "Status: ✗ Not found in qBittorrent\n\n")
 
 			if !dryRun {
 				response := "n"
 				if !noConfirmHardlink {
-					fmt.Printf("→ Delete file and search for new version? [y/n/q]: ")
+					fmt.Fprintf(console(), "→ Delete file and search for new version? [y/n/q]: ")
 					fmt.Scanln(&response)
 				} else {
 					response = "y"
@@ -217,56 +214,56 @@ func runHardlink(cmd *cobra.Command, args []string) error {
 				response = strings.ToLower(strings.TrimSpace(response))
 
 				if response == "q" || response == "quit" {
-					fmt.Printf("\nProcessing stopped by user.\n")
+					fmt.Fprintf(console(), "\nProcessing stopped by user.\n")
 					break
 				} else if response == "y" || response == "yes" {
 					if err := operations.DeleteAndResearchMovie(ctx, movie); err != nil {
 						logger.Error().Err(err).Str("movie", movie.Title).Msg("Failed to delete and re-search movie")
-						fmt.Printf("✗ Failed to delete and re-search: %v\n", err)
+						fmt.Fprintf(console(), "✗ Failed to delete and re-search: %v\n", err)
 					} else {
-						fmt.Printf("✓ Deleted and searching for new version\n")
+						fmt.Fprintf(console(), "✓ Deleted and searching for new version\n")
 						deletedCount++
 					}
 				} else {
-					fmt.Printf("⊘ Skipped\n")
+					fmt.Fprintf(console(), "⊘ Skipped\n")
 					skippedCount++
 				}
 			} else {
-				fmt.Printf("[DRY RUN] Would delete and re-search\n")
+				fmt.Fprintf(console(), "[DRY RUN] Would delete and re-search\n")
 			}
 		}
 
 		processedCount++
-		fmt.Println() // Empty line between movies
+		fmt.Fprintln(console()) // Empty line between movies
 	}
 
 	// Show summary
-	fmt.Println("\nSummary:")
-	fmt.Println(strings.Repeat("━", 50))
-	fmt.Printf("Processed: %d movie", processedCount)
+	fmt.Fprintln(console(), "\nSummary:")
+	fmt.Fprintln(console(), strings.Repeat("━", 50))
+	fmt.Fprintf(console(), "Processed: %d movie", processedCount)
 	if processedCount != 1 {
-		fmt.Printf("s")
+		fmt.Fprintf(console(), "s")
 	}
-	fmt.Println()
+	fmt.Fprintln(console())
 
 	if !dryRun {
 		if reimportedCount > 0 {
-			fmt.Printf("- Re-imported: %d\n", reimportedCount)
+			fmt.Fprintf(console(), "- Re-imported: %d\n", reimportedCount)
 		}
 		if deletedCount > 0 {
-			fmt.Printf("- Deleted: %d\n", deletedCount)
+			fmt.Fprintf(console(), "- Deleted: %d\n", deletedCount)
 		}
 		if skippedCount > 0 {
-			fmt.Printf("- Skipped: %d\n", skippedCount)
+			fmt.Fprintf(console(), "- Skipped: %d\n", skippedCount)
 		}
 
 		remaining := len(nonHardlinkedMovies) - processedCount
 		if remaining > 0 {
-			fmt.Printf("Remaining: %d movie", remaining)
+			fmt.Fprintf(console(), "Remaining: %d movie", remaining)
 			if remaining != 1 {
-				fmt.Printf("s")
+				fmt.Fprintf(console(), "s")
 			}
-			fmt.Println()
+			fmt.Fprintln(console())
 		}
 	}
 
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/journal"
+)
+
+var (
+	historySince  string
+	historyDays   int
+	historyFilter string
+	historyTitle  string
+	historyAction string
+	historyLimit  int
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show what arrbiter has done to your library",
+	Long: `Show the journal of actions arrbiter has taken: deletions, quarantines,
+purges, upgrades, re-imports and re-searches, along with the filter that matched and
+the watch and request data each movie had at the time.`,
+	PreRunE: loadConfig,
+	RunE:    runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historySince, "since", "", "only show actions on or after this date (YYYY-MM-DD)")
+	historyCmd.Flags().IntVar(&historyDays, "days", 0, "only show actions from the last N days")
+	historyCmd.Flags().StringVar(&historyFilter, "filter", "", "only show actions taken by this filter")
+	historyCmd.Flags().StringVar(&historyTitle, "title", "", "only show movies whose title contains this text")
+	historyCmd.Flags().StringVar(&historyAction, "action", "", "only show this action (delete, quarantine, purge, upgrade, reimport, delete_and_research)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 50, "show at most this many of the most recent entries (0 for all)")
+}
+
+// openJournal returns the journal in the configured data directory
+func openJournal() *journal.Journal {
+	return journal.New(filepath.Join(cfg.DataDir, "history.jsonl"))
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	query := journal.Query{
+		Action: historyAction,
+		Filter: historyFilter,
+		Title:  historyTitle,
+		Limit:  historyLimit,
+	}
+
+	if historySince != "" {
+		since, err := time.ParseInLocation("2006-01-02", historySince, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since date %q (expected YYYY-MM-DD)", historySince)
+		}
+		query.Since = since
+	}
+	if historyDays > 0 {
+		query.Since = time.Now().AddDate(0, 0, -historyDays)
+	}
+
+	records, err := openJournal().Read(query)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No matching history.")
+		return nil
+	}
+
+	for _, record := range records {
+		status := "✓"
+		if record.Outcome == journal.OutcomeFailed {
+			status = "✗"
+		}
+
+		fmt.Printf("%s  %-19s %s %s (%d)", record.Time.Local().Format("2006-01-02 15:04"), record.Action, status, record.Title, record.Year)
+		if record.SizeBytes > 0 {
+			fmt.Printf("  %s", formatSize(record.SizeBytes))
+		}
+		if record.Filter != "" {
+			fmt.Printf("  [%s]", record.Filter)
+		}
+		fmt.Println()
+
+		indent := "                                     "
+		if record.Details != "" {
+			fmt.Printf("%s%s\n", indent, record.Details)
+		}
+		if record.Error != "" {
+			fmt.Printf("%serror: %s\n", indent, record.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/identity"
+)
+
+// userAccounts are the Overseerr and Tautulli users fetched at startup
+var userAccounts []identity.Account
+
+// initIdentities links the accounts users have in Overseerr and Tautulli so
+// requester-aware filters recognise them under either name, along with the
+// aliases and groups from the users section of the config.
+func initIdentities(accounts []identity.Account) {
+	resolver := identity.NewResolver()
+	for name, aliases := range cfg.Users.Aliases {
+		resolver.Link(name, aliases...)
+	}
+	for name, members := range cfg.Users.Groups {
+		resolver.AddGroup(name, members...)
+	}
+	for _, account := range accounts {
+		resolver.Add(account)
+	}
+
+	filter.SetIdentities(resolver)
+}
+
+// fetchAccounts returns the users of Overseerr and Tautulli, when configured
+func fetchAccounts() []identity.Account {
+	var accounts []identity.Account
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if overseerrClient != nil {
+		users, err := overseerrClient.GetUsers(ctx)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Failed to fetch Overseerr users, requesters are matched by name only")
+		}
+		for _, user := range users {
+			accounts = append(accounts, identity.Account{
+				Names:  []string{user.GetDisplayName(), user.Username, user.PlexUsername},
+				Email:  user.Email,
+				PlexID: user.PlexID,
+			})
+		}
+	}
+
+	if tautulliClient != nil {
+		users, err := tautulliClient.GetUsers(ctx)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Failed to fetch Tautulli users, watchers are matched by name only")
+		}
+		for _, user := range users {
+			accounts = append(accounts, identity.Account{
+				Names:  []string{user.FriendlyName, user.Username},
+				Email:  user.Email,
+				PlexID: user.UserID,
+			})
+		}
+	}
+
+	return accounts
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// radarrInstance is a configured Radarr instance with its client and operations
+type radarrInstance struct {
+	Name       string
+	Client     *radarr.Client
+	Operations *radarr.Operations
+}
+
+// radarrInstances holds every configured Radarr instance, the primary one first.
+// radarrClient and operations point at the primary instance for commands that
+// only work on one instance.
+var radarrInstances []radarrInstance
+
+// initRadarr connects to every configured Radarr instance
+func initRadarr() error {
+	radarrInstances = nil
+	for _, instanceCfg := range cfg.Radarr {
+		client, err := radarr.NewClient(instanceCfg.URL, instanceCfg.APIKey, logger)
+		if err != nil {
+			return fmt.Errorf("failed to create Radarr client for instance %s: %w", instanceCfg.Name, err)
+		}
+
+		ops := radarr.NewOperations(client, logger)
+		ops.SetInstance(instanceCfg.Name)
+		radarrInstances = append(radarrInstances, radarrInstance{
+			Name:       instanceCfg.Name,
+			Client:     client,
+			Operations: ops,
+		})
+	}
+
+	radarrClient = radarrInstances[0].Client
+	operations = radarrInstances[0].Operations
+
+	if multipleInstances() {
+		logger.Info().Strs("instances", cfg.Radarr.Names()).Msg("Radarr integration enabled")
+	} else {
+		logger.Info().Msg("Radarr integration enabled")
+	}
+	return nil
+}
+
+// forEachInstance applies fn to the operations of every Radarr instance
+func forEachInstance(fn func(ops *radarr.Operations)) {
+	for _, instance := range radarrInstances {
+		fn(instance.Operations)
+	}
+}
+
+// multipleInstances reports whether more than one Radarr instance is configured
+func multipleInstances() bool {
+	return len(radarrInstances) > 1
+}
+
+// instanceByName returns the named Radarr instance. An empty name is the
+// primary instance, which is what records from before instances existed mean.
+func instanceByName(name string) (radarrInstance, error) {
+	if name == "" {
+		return radarrInstances[0], nil
+	}
+	for _, instance := range radarrInstances {
+		if instance.Name == name {
+			return instance, nil
+		}
+	}
+	return radarrInstance{}, fmt.Errorf("unknown Radarr instance: %s", name)
+}
+
+// getAllMovies fetches the movies of every Radarr instance concurrently,
+// keeping the instances in config order
+func getAllMovies(ctx context.Context) ([]radarr.MovieInfo, error) {
+	results := make([][]radarr.MovieInfo, len(radarrInstances))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, instance := range radarrInstances {
+		g.Go(func() error {
+			movies, err := instance.Operations.GetAllMovies(gctx)
+			if err != nil {
+				if multipleInstances() {
+					return fmt.Errorf("radarr instance %s: %w", instance.Name, err)
+				}
+				return err
+			}
+			results[i] = movies
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(results...), nil
+}
+
+// forEachInstanceMovies splits movies by the Radarr instance they are in and
+// calls fn once per instance with that instance's operations
+func forEachInstanceMovies(movies []radarr.MovieInfo, fn func(ops *radarr.Operations, movies []radarr.MovieInfo) error) error {
+	var errs []error
+	for _, instance := range radarrInstances {
+		var instanceMovies []radarr.MovieInfo
+		for _, movie := range movies {
+			if movie.Instance == instance.Name {
+				instanceMovies = append(instanceMovies, movie)
+			}
+		}
+		if len(instanceMovies) == 0 {
+			continue
+		}
+		if err := fn(instance.Operations, instanceMovies); err != nil {
+			if multipleInstances() {
+				err = fmt.Errorf("radarr instance %s: %w", instance.Name, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// inFilterInstance reports whether a movie is in the instance a filter targets
+func inFilterInstance(def config.FilterDefinition, movie radarr.MovieInfo) bool {
+	return def.Instance == "" || def.Instance == movie.Instance
+}
+
+// restrictToFilterInstances drops the matches of filters that target one
+// instance but come from another
+func restrictToFilterInstances(filters config.FilterConfig, matchesByFilter map[string][]radarr.MovieInfo) {
+	for filterName, movies := range matchesByFilter {
+		def := filters[filterName]
+		if def.Instance == "" {
+			continue
+		}
+		matchesByFilter[filterName] = slices.DeleteFunc(movies, func(movie radarr.MovieInfo) bool {
+			return !inFilterInstance(def, movie)
+		})
+	}
+}
+
+// instanceLabel returns " [instance]" for display when several Radarr
+// instances are configured, and nothing otherwise
+func instanceLabel(movie radarr.MovieInfo) string {
+	if !multipleInstances() {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", movie.Instance)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+var (
+	outputFormat string
+	formatter    radarr.MovieFormatter = radarr.NewConsoleFormatter()
+)
+
+// initOutput picks the formatter for the --output flag
+func initOutput() error {
+	var err error
+	formatter, err = radarr.NewFormatter(outputFormat)
+	return err
+}
+
+// machineOutput reports whether stdout carries machine-readable records
+func machineOutput() bool {
+	_, console := formatter.(*radarr.ConsoleFormatter)
+	return !console
+}
+
+// textOutputOnly rejects machine-readable output for commands that only print text
+func textOutputOnly(cmd *cobra.Command) error {
+	if machineOutput() {
+		return fmt.Errorf("%s only supports text output", cmd.CommandPath())
+	}
+	return nil
+}
+
+// console returns where messages and prompts meant for people go: stdout,
+// or stderr when stdout carries machine-readable records
+func console() io.Writer {
+	if machineOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// formatOptions returns the console formatting options from the config
+func formatOptions() radarr.FormatOptions {
+	return radarr.FormatOptions{
+		ShowDetails:   cfg.Safety.ShowDetails,
+		ShowWatchInfo: cfg.Safety.ShowDetails,
+		ShowRequests:  cfg.Safety.ShowDetails,
+		ShowInstance:  multipleInstances(),
+	}
+}
+
+// printFormatted prints what a formatter returned
+func printFormatted(output string, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/quarantine"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+var (
+	purgeOlderThan int
+	noConfirmPurge bool
+)
+
+// purgeCmd represents the purge command
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove quarantined movies past their retention period",
+	Long: `Permanently remove movies that have been in quarantine for longer than
+quarantine.retention_days. Each movie is removed from Radarr and its quarantined
+folder is deleted from disk.`,
+	PreRunE: initializeApp,
+	RunE:    runPurge,
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <movie>",
+	Short: "Restore a quarantined movie",
+	Long: `Move a quarantined movie's folder back to its original location,
+re-monitor it in Radarr and trigger a rescan.
+
+The movie can be given as a TMDB ID or as a title.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: initializeApp,
+	RunE:    runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	purgeCmd.Flags().IntVar(&purgeOlderThan, "older-than", -1, "purge items quarantined at least this many days ago (default quarantine.retention_days)")
+	purgeCmd.Flags().BoolVar(&noConfirmPurge, "no-confirm", false, "skip confirmation prompt")
+}
+
+// quarantinesDeletes reports whether a delete filter should quarantine instead of deleting
+func quarantinesDeletes(def config.FilterDefinition) bool {
+	return cfg.Quarantine.Enabled && def.DeleteFiles
+}
+
+// openQuarantine opens the configured quarantine store
+func openQuarantine() (*quarantine.Store, error) {
+	if cfg.Quarantine.Path == "" {
+		return nil, fmt.Errorf("quarantine.path is not configured")
+	}
+	return quarantine.NewStore(cfg.Quarantine.Path)
+}
+
+// withoutQuarantined drops movies that are already in the quarantine store
+func withoutQuarantined(movies []radarr.MovieInfo) ([]radarr.MovieInfo, error) {
+	store, err := openQuarantine()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return movies, nil
+	}
+
+	quarantined := make(map[radarr.MovieKey]bool, len(entries))
+	for _, entry := range entries {
+		quarantined[radarr.MovieKey{Instance: entry.Instance, ID: entry.MovieID}] = true
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(movies), func(movie radarr.MovieInfo) bool {
+		return quarantined[movie.Key()]
+	})
+	logger.Debug().Int("quarantined", len(movies)-len(kept)).Msg("Skipping movies already in quarantine")
+	return kept, nil
+}
+
+func runPurge(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	store, err := openQuarantine()
+	if err != nil {
+		return err
+	}
+
+	days := cfg.Quarantine.RetentionDays
+	if purgeOlderThan >= 0 {
+		days = purgeOlderThan
+	}
+
+	now := time.Now()
+	expired, err := store.Expired(time.Duration(days)*24*time.Hour, now)
+	if err != nil {
+		return err
+	}
+
+	if len(expired) == 0 {
+		fmt.Printf("Nothing in quarantine older than %d days.\n", days)
+		return nil
+	}
+
+	fmt.Printf("╭─ Quarantined for %d+ days (%d movie", days, len(expired))
+	if len(expired) != 1 {
+		fmt.Printf("s")
+	}
+	fmt.Println(")")
+	for i, entry := range expired {
+		prefix := "├"
+		if i == len(expired)-1 {
+			prefix = "╰"
+		}
+		fmt.Printf("%s── %s (%d) - quarantined %s, %d days ago\n", prefix, entry.Title, entry.Year,
+			entry.QuarantinedAt.Format("2006-01-02"), int(entry.Age(now).Hours()/24))
+	}
+	fmt.Println()
+
+	if cfg.Safety.DryRun {
+		logger.Info().Msg("DRY RUN MODE - Nothing will be purged")
+		return nil
+	}
+
+	if cfg.Safety.ConfirmDelete && !noConfirmPurge {
+		fmt.Printf("Permanently delete %d movie(s)? [y/N]: ", len(expired))
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			logger.Info().Msg("Purge cancelled by user")
+			return nil
+		}
+	}
+
+	// Each movie is removed from the Radarr instance it was quarantined from
+	byInstance := make(map[string][]quarantine.Entry)
+	for _, entry := range expired {
+		name := cmp.Or(entry.Instance, radarrInstances[0].Name)
+		byInstance[name] = append(byInstance[name], entry)
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(byInstance)) {
+		instance, err := instanceByName(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't purge %d movie(s): %w", len(byInstance[name]), err))
+			continue
+		}
+		if err := instance.Operations.PurgeQuarantinedMovies(context.Background(), byInstance[name], store); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	store, err := openQuarantine()
+	if err != nil {
+		return err
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		return err
+	}
+
+	entry, err := findQuarantineEntry(entries, args[0])
+	if err != nil {
+		return err
+	}
+
+	instance, err := instanceByName(entry.Instance)
+	if err != nil {
+		return fmt.Errorf("can't restore %s: %w", entry.Title, err)
+	}
+
+	if cfg.Safety.DryRun {
+		fmt.Printf("[DRY RUN] Would restore %s (%d) to %s\n", entry.Title, entry.Year, entry.OriginalPath)
+		return nil
+	}
+
+	if err := instance.Operations.RestoreQuarantinedMovie(context.Background(), entry, store); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", entry.Title, err)
+	}
+
+	fmt.Printf("✓ Restored %s (%d) to %s\n", entry.Title, entry.Year, entry.OriginalPath)
+	return nil
+}
+
+// findQuarantineEntry looks up a single quarantined movie by TMDB ID or title
+func findQuarantineEntry(entries []quarantine.Entry, query string) (quarantine.Entry, error) {
+	if tmdbID, err := strconv.ParseInt(query, 10, 64); err == nil {
+		for _, entry := range entries {
+			if entry.TMDBID == tmdbID {
+				return entry, nil
+			}
+		}
+	}
+
+	var partial []quarantine.Entry
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Title, query) {
+			return entry, nil
+		}
+		if strings.Contains(strings.ToLower(entry.Title), strings.ToLower(query)) {
+			partial = append(partial, entry)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return quarantine.Entry{}, fmt.Errorf("no quarantined movie found matching '%s'", query)
+	case 1:
+		return partial[0], nil
+	}
+
+	var candidates []string
+	for _, entry := range partial {
+		candidates = append(candidates, fmt.Sprintf("  - %s (%d) [TMDB %d]", entry.Title, entry.Year, entry.TMDBID))
+	}
+	return quarantine.Entry{}, fmt.Errorf("'%s' matches %d quarantined movies, use the TMDB ID instead:\n%s",
+		query, len(partial), strings.Join(candidates, "\n"))
+}
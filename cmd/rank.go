@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/filter"
+)
+
+var listRank bool
+
+// compileScores compiles the score expression of every filter that has one
+func compileScores(filters config.FilterConfig) (map[string]filter.CompiledScore, error) {
+	scores := make(map[string]filter.CompiledScore)
+	for filterName, def := range filters {
+		if def.Score == "" {
+			continue
+		}
+		score, err := filter.CompileScore(def.Score)
+		if err != nil {
+			return nil, fmt.Errorf("invalid score for filter '%s': %w", filterName, err)
+		}
+		scores[filterName] = score
+	}
+	return scores, nil
+}
+
+// printRanking shows movies from most to least deletable with each filter's share of the score
+func printRanking(ranked []filter.RankedMovie) {
+	fmt.Printf("╭─ Ranked by score (%d movie", len(ranked))
+	if len(ranked) != 1 {
+		fmt.Printf("s")
+	}
+	fmt.Println(")")
+
+	for i, entry := range ranked {
+		var parts []string
+		for _, filterName := range slices.Sorted(maps.Keys(entry.Scores)) {
+			parts = append(parts, fmt.Sprintf("%s %s", filterName, formatScore(entry.Scores[filterName])))
+		}
+
+		fmt.Printf("%s── %8s  %s (%d)", treePrefix(i, len(ranked)), formatScore(entry.Score), entry.Movie.Title, entry.Movie.Year)
+		if len(parts) > 0 {
+			fmt.Printf("  [%s]", strings.Join(parts, ", "))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// formatScore prints a score without trailing zeros
+func formatScore(score float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", score), "0"), ".")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/review"
+)
+
+var reviewDelete bool
+
+func init() {
+	deleteCmd.Flags().BoolVar(&reviewDelete, "review", false, "pick the movies to act on in a full-screen list instead of confirming the whole batch")
+}
+
+// reviewCandidates lets the user pick which of the movies filters are about
+// to change are acted on, and returns the picked movies grouped by filter.
+// Movies of notify filters are left as they are since nothing happens to them.
+func reviewCandidates(ctx context.Context, filters config.FilterConfig, filterNames []string, moviesByFilter map[string][]radarr.MovieInfo) (map[string][]radarr.MovieInfo, error) {
+	var candidates []review.Candidate
+	for _, filterName := range filterNames {
+		def := filters[filterName]
+		if def.Action == config.ActionNotify {
+			continue
+		}
+		for _, movie := range moviesByFilter[filterName] {
+			candidates = append(candidates, review.Candidate{Movie: movie, Filter: filterName, Action: describeAction(def)})
+		}
+	}
+
+	opts := review.Options{ProtectTag: cfg.Review.ProtectTag}
+	if !cfg.Safety.DryRun && cfg.Review.ProtectTag != "" {
+		opts.Protect = func(movie radarr.MovieInfo) error {
+			return forEachInstanceMovies([]radarr.MovieInfo{movie}, func(operations *radarr.Operations, movies []radarr.MovieInfo) error {
+				return operations.TagMovies(ctx, movies, cfg.Review.ProtectTag)
+			})
+		}
+	}
+
+	selected, err := review.Run(candidates, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewed := make(map[string][]radarr.MovieInfo)
+	for filterName, movies := range moviesByFilter {
+		if filters[filterName].Action == config.ActionNotify {
+			reviewed[filterName] = movies
+		}
+	}
+	for _, candidate := range selected {
+		reviewed[candidate.Filter] = append(reviewed[candidate.Filter], candidate.Movie)
+	}
+	return reviewed, nil
+}
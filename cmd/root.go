@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/mattn/go-isatty"
@@ -13,25 +17,33 @@ import (
 
 	"github.com/s0up4200/arrbiter/config"
 	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/jellyfin"
+	"github.com/s0up4200/arrbiter/journal"
 	"github.com/s0up4200/arrbiter/overseerr"
+	"github.com/s0up4200/arrbiter/plex"
 	"github.com/s0up4200/arrbiter/qbittorrent"
 	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/review"
 	"github.com/s0up4200/arrbiter/tautulli"
+	"github.com/s0up4200/arrbiter/watch"
 )
 
 var (
 	cfgFile         string
 	cfg             *config.Config
 	logger          zerolog.Logger
-	radarrClient    *radarr.Client
+	radarrClient    *radarr.Client // Primary Radarr instance
 	tautulliClient  *tautulli.Client
+	plexClient      *plex.Client
+	mediaServers    []*jellyfin.Client // Jellyfin and Emby watch history
 	overseerrClient *overseerr.Client
-	operations      *radarr.Operations
+	operations      *radarr.Operations // Primary Radarr instance
 
 	// Command flags
 	dryRun        bool
 	noConfirm     bool
 	ignoreWatched bool
+	refreshCache  bool
 )
 
 // rootCmd represents the base command
@@ -54,6 +66,8 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "d", false, "perform a dry run without making changes")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh-cache", false, "rebuild the Tautulli history cache from scratch")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", radarr.OutputText, "output format: text, json, ndjson, csv, yaml or template=<Go template>")
 
 	// Add subcommands
 	rootCmd.AddCommand(listCmd)
@@ -64,8 +78,12 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 }
 
-// initializeApp initializes the configuration and clients
-func initializeApp(cmd *cobra.Command, args []string) error {
+// loadConfig loads the configuration and sets up logging without connecting to any service
+func loadConfig(cmd *cobra.Command, args []string) error {
+	if err := initOutput(); err != nil {
+		return err
+	}
+
 	// Load configuration
 	var err error
 	cfg, err = config.Load(cfgFile)
@@ -81,14 +99,67 @@ func initializeApp(cmd *cobra.Command, args []string) error {
 		cfg.Safety.DryRun = dryRun
 	}
 
-	// Create Radarr client
-	radarrClient, err = radarr.NewClient(cfg.Radarr.URL, cfg.Radarr.APIKey, logger)
+	return nil
+}
+
+// watchThreshold returns the configured watch threshold
+func watchThreshold() watch.Threshold {
+	return watch.Threshold{
+		MinPercent: cfg.Watch.MinPercent,
+		Users:      cfg.Watch.Users,
+	}
+}
+
+// openHistoryCache makes the Tautulli client cache its history in the data
+// directory, so runs only fetch plays recorded since the last one
+func openHistoryCache(client *tautulli.Client) {
+	cache, err := tautulli.OpenHistoryCache(filepath.Join(cfg.DataDir, "tautulli-history.json"), cfg.Tautulli.URL)
 	if err != nil {
-		return fmt.Errorf("failed to create Radarr client: %w", err)
+		logger.Warn().Err(err).Msg("Failed to open Tautulli history cache, fetching the full history")
+		return
+	}
+	if refreshCache {
+		cache.Reset()
+	}
+	client.SetHistoryCache(cache)
+}
+
+// initializeApp initializes the configuration and clients
+func initializeApp(cmd *cobra.Command, args []string) error {
+	if err := loadConfig(cmd, args); err != nil {
+		return err
+	}
+
+	// Compile all filters up front so typos are reported before touching any API
+	if err := validateFilters(cfg.Filter, cfg.Protect); err != nil {
+		return err
 	}
-	logger.Info().Msg("Radarr integration enabled")
+	if err := validateSeriesFilters(cfg.SeriesFilter); err != nil {
+		return err
+	}
+
+	// Create Radarr clients, one per instance
+	if err := initRadarr(); err != nil {
+		return err
+	}
+
+	fetchFileDetails := usesCustomFormats(cfg)
+	var movieJournal *journal.Journal
+	if cfg.DataDir != "" {
+		movieJournal = openJournal()
+	}
+	threshold := watchThreshold()
+	filter.SetWatchThreshold(threshold)
+	forEachInstance(func(ops *radarr.Operations) {
+		ops.SetFetchFileDetails(fetchFileDetails)
+		ops.SetWatchThreshold(threshold)
+		ops.SetFormatter(formatter)
+		if movieJournal != nil {
+			ops.SetJournal(movieJournal)
+		}
+	})
 
-	operations = radarr.NewOperations(radarrClient, logger)
+	var err error
 
 	// Create Tautulli client if URL and API key are provided
 	if cfg.Tautulli.URL != "" && cfg.Tautulli.APIKey != "" {
@@ -96,30 +167,83 @@ func initializeApp(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			logger.Warn().Err(err).Msg("Failed to create Tautulli client, continuing without watch status")
 		} else {
-			operations.SetTautulliClient(tautulliClient)
-			operations.SetMinWatchPercent(cfg.Tautulli.MinWatchPercent)
+			if cfg.DataDir != "" {
+				openHistoryCache(tautulliClient)
+			}
+			forEachInstance(func(ops *radarr.Operations) {
+				ops.SetTautulliClient(tautulliClient)
+			})
 			logger.Info().Msg("Tautulli integration enabled")
 		}
 	}
 
+	// Create Plex client if URL and token are provided
+	if cfg.Plex.URL != "" && cfg.Plex.Token != "" {
+		plexClient, err = plex.NewClient(cfg.Plex.URL, cfg.Plex.Token, logger)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Failed to create Plex client, continuing without its watch status")
+		} else {
+			forEachInstance(func(ops *radarr.Operations) {
+				ops.AddWatchProvider(plexClient)
+			})
+			logger.Info().Msg("Plex integration enabled")
+		}
+	}
+
+	// Create Jellyfin and Emby clients if URL and API key are provided
+	mediaServers = nil
+	for _, media := range []struct {
+		server jellyfin.Server
+		cfg    config.JellyfinConfig
+	}{
+		{jellyfin.ServerJellyfin, cfg.Jellyfin},
+		{jellyfin.ServerEmby, cfg.Emby},
+	} {
+		server, serverCfg := media.server, media.cfg
+		if serverCfg.URL == "" || serverCfg.APIKey == "" {
+			continue
+		}
+		client, err := jellyfin.NewClient(server, serverCfg.URL, serverCfg.APIKey, logger)
+		if err != nil {
+			logger.Warn().Err(err).Str("server", string(server)).Msg("Failed to create media server client, continuing without its watch status")
+			continue
+		}
+		mediaServers = append(mediaServers, client)
+		forEachInstance(func(ops *radarr.Operations) {
+			ops.AddWatchProvider(client)
+		})
+		logger.Info().Str("server", string(server)).Msg("Media server watch history enabled")
+	}
+
 	// Create Overseerr client if URL and API key are provided
 	if cfg.Overseerr.URL != "" && cfg.Overseerr.APIKey != "" {
 		overseerrClient, err = overseerr.NewClient(cfg.Overseerr.URL, cfg.Overseerr.APIKey, logger)
 		if err != nil {
 			logger.Warn().Err(err).Msg("Failed to create Overseerr client, continuing without request data")
 		} else {
-			operations.SetOverseerrClient(overseerrClient)
+			forEachInstance(func(ops *radarr.Operations) {
+				ops.SetOverseerrClient(overseerrClient)
+			})
 			logger.Info().Msg("Overseerr integration enabled")
 		}
 	}
 
+	// Link users across Overseerr, Tautulli and configured aliases
+	userAccounts = fetchAccounts()
+	initIdentities(userAccounts)
+
+	// Create Sonarr client if URL and API key are provided
+	initSonarr()
+
 	// Create qBittorrent client if URL is provided
 	if cfg.QBittorrent.URL != "" {
 		qbittorrentClient, err := qbittorrent.NewClient(cfg.QBittorrent.URL, cfg.QBittorrent.Username, cfg.QBittorrent.Password, logger)
 		if err != nil {
 			logger.Warn().Err(err).Msg("Failed to create qBittorrent client, continuing without torrent integration")
 		} else {
-			operations.SetQBittorrentClient(qbittorrentClient)
+			forEachInstance(func(ops *radarr.Operations) {
+				ops.SetQBittorrentClient(qbittorrentClient)
+			})
 			logger.Info().Msg("qBittorrent integration enabled")
 		}
 	}
@@ -127,6 +251,38 @@ func initializeApp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// validateFilters compiles every configured filter and protection rule and reports the first failure
+func validateFilters(filters config.FilterConfig, protect config.ProtectConfig) error {
+	for _, filterName := range slices.Sorted(maps.Keys(filters)) {
+		if _, err := filter.CompileFilter(filters[filterName].Expression); err != nil {
+			return fmt.Errorf("invalid filter '%s': %w", filterName, err)
+		}
+		if score := filters[filterName].Score; score != "" {
+			if _, err := filter.CompileScore(score); err != nil {
+				return fmt.Errorf("invalid score for filter '%s': %w", filterName, err)
+			}
+		}
+	}
+	for _, ruleName := range slices.Sorted(maps.Keys(protect)) {
+		if _, err := filter.CompileFilter(protect[ruleName]); err != nil {
+			return fmt.Errorf("invalid protection rule '%s': %w", ruleName, err)
+		}
+	}
+	return nil
+}
+
+// usesCustomFormats reports whether any expression needs custom format data,
+// which has to be fetched per movie file
+func usesCustomFormats(cfg *config.Config) bool {
+	expressions := slices.Concat(slices.Collect(maps.Values(cfg.Protect)), cfg.Target.Sort, targetSort)
+	for _, def := range cfg.Filter {
+		expressions = append(expressions, def.Expression, def.Score)
+	}
+	return slices.ContainsFunc(expressions, func(expression string) bool {
+		return strings.Contains(expression, "CustomFormat")
+	})
+}
+
 // setupLogger configures the zerolog logger
 func setupLogger(cfg config.LoggingConfig) zerolog.Logger {
 	// Set log level
@@ -166,136 +322,102 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List movies matching the filter criteria",
 	Long:    `List all movies in your Radarr library that match the specified filter criteria.`,
-	PreRunE: initializeApp,
+	PreRunE: initializeWithSnapshot,
 	RunE:    runList,
 }
 
 func init() {
-	// No filter flags needed anymore
+	listCmd.Flags().BoolVar(&listRank, "rank", false, "list all matches as one list ordered by combined filter score")
+	listCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "evaluate filters against a saved snapshot instead of the live services")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	// Check if any filters are defined
-	if len(cfg.Filter) == 0 {
-		fmt.Println("No filters defined in configuration.")
+	if listRank && machineOutput() {
+		return fmt.Errorf("--rank only supports text output")
+	}
+
+	if len(cfg.Filter.Enabled()) == 0 && !seriesFiltersEnabled() {
+		fmt.Fprintln(console(), "No filters defined in configuration.")
+		return nil
+	}
+
+	if err := listMovies(); err != nil {
+		return err
+	}
+	if seriesFiltersEnabled() {
+		return listSeries(context.Background())
+	}
+	return nil
+}
+
+// listMovies lists the movies matching each enabled movie filter
+func listMovies() error {
+	filters := cfg.Filter.Enabled()
+	if len(filters) == 0 {
 		return nil
 	}
 
-	logger.Info().Int("filter_count", len(cfg.Filter)).Msg("Processing filters")
+	logger.Info().Int("filter_count", len(filters)).Msg("Processing filters")
 
 	// Get all movies once
 	ctx := context.Background()
-	allMovies, err := operations.GetAllMovies(ctx)
+	allMovies, err := loadMovies(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get movies: %w", err)
 	}
 
+	// Collection and instance helpers look at the whole library
+	filter.AnnotateCollections(allMovies)
+	filter.AnnotateInstances(allMovies)
+
 	// Track which movies match which filters
 	moviesByFilter := make(map[string][]radarr.MovieInfo)
-	matchedMovies := make(map[int64]bool) // Track unique movies by ID
+	matchedMovies := make(map[radarr.MovieKey]bool) // Track unique movies across instances
 
 	// Process each filter
-	for filterName, filterExpr := range cfg.Filter {
-		logger.Debug().Str("filter", filterName).Str("expression", filterExpr).Msg("Processing filter")
+	for filterName, def := range filters {
+		logger.Debug().Str("filter", filterName).Str("expression", def.Expression).Msg("Processing filter")
 
 		// Parse filter
-		filterFunc, err := filter.ParseAndCreateFilter(filterExpr)
+		compiled, err := filter.CompileFilter(def.Expression)
 		if err != nil {
 			logger.Error().Err(err).Str("filter", filterName).Msg("Invalid filter expression")
 			continue
 		}
+		compiled = filter.WithMinWatchPercent(compiled, def.MinWatchPercent)
 
 		// Find matching movies
 		for _, movie := range allMovies {
-			if filterFunc(movie) {
+			if inFilterInstance(def, movie) && compiled.Evaluate(movie) {
 				moviesByFilter[filterName] = append(moviesByFilter[filterName], movie)
-				matchedMovies[movie.ID] = true
+				matchedMovies[movie.Key()] = true
 			}
 		}
 	}
 
-	// Display results
-	if len(matchedMovies) == 0 {
-		fmt.Println("No movies found matching any filter criteria.")
-		return nil
-	}
-
-	fmt.Printf("\nFound %d movie", len(matchedMovies))
-	if len(matchedMovies) != 1 {
-		fmt.Printf("s")
-	}
-	fmt.Println()
-	fmt.Println()
-
-	// Display movies grouped by filter
-	for filterName, movies := range moviesByFilter {
-		if len(movies) == 0 {
-			continue
-		}
-
-		fmt.Printf("\u256d\u2500 Filter: %s (%d match", filterName, len(movies))
-		if len(movies) != 1 {
-			fmt.Printf("es")
+	if listRank {
+		if len(matchedMovies) == 0 {
+			fmt.Fprintln(console(), "No movies found matching any filter criteria.")
+			return nil
 		}
-		fmt.Println(")")
-
-		for i, movie := range movies {
-			isLast := i == len(movies)-1
-			prefix := "\u251c"
-			if isLast {
-				prefix = "\u2570"
-			}
-
-			fmt.Printf("%s\u2500\u2500 %s (%d)\n", prefix, movie.Title, movie.Year)
-			if cfg.Safety.ShowDetails {
-				indent := "\u2502   "
-				if isLast {
-					indent = "    "
-				}
-
-				if len(movie.TagNames) > 0 {
-					fmt.Printf("%sTags: %s\n", indent, strings.Join(movie.TagNames, ", "))
-				}
-
-				var dateParts []string
-				if !movie.Added.IsZero() {
-					dateParts = append(dateParts, fmt.Sprintf("Available: %s", movie.Added.Format("2006-01-02")))
-				}
-				if !movie.FileImported.IsZero() && !movie.FileImported.Equal(movie.Added) {
-					dateParts = append(dateParts, fmt.Sprintf("Imported: %s", movie.FileImported.Format("2006-01-02")))
-				}
-				if !movie.MonitoredSince.IsZero() && !movie.MonitoredSince.Equal(movie.Added) {
-					dateParts = append(dateParts, fmt.Sprintf("Monitored: %s", movie.MonitoredSince.Format("2006-01-02")))
-				}
-				if len(dateParts) > 0 {
-					fmt.Printf("%s%s\n", indent, strings.Join(dateParts, " | "))
-				}
-
-				if movie.WatchCount > 0 {
-					watchInfo := fmt.Sprintf("Watched %dx", movie.WatchCount)
-					if !movie.LastWatched.IsZero() {
-						watchInfo += fmt.Sprintf(" (last: %s)", movie.LastWatched.Format("2006-01-02"))
-					}
-					fmt.Printf("%s%s\n", indent, watchInfo)
-				}
-
-				// Show request info if available
-				if movie.IsRequested && movie.RequestedBy != "" {
-					requestInfo := fmt.Sprintf("Requested by: %s", movie.RequestedBy)
-					if !movie.RequestDate.IsZero() {
-						requestInfo += fmt.Sprintf(" on %s", movie.RequestDate.Format("2006-01-02"))
-					}
-					fmt.Printf("%s%s\n", indent, requestInfo)
-				}
-			}
-			if i < len(movies)-1 && cfg.Safety.ShowDetails {
-				fmt.Printf("\u2502\n")
-			}
+		scores, err := compileScores(filters)
+		if err != nil {
+			return err
 		}
-		fmt.Println()
+		printRanking(filter.Rank(scores, moviesByFilter))
+		return nil
 	}
 
-	return nil
+	// Display movies grouped by filter
+	var groups []radarr.MovieGroup
+	for _, filterName := range slices.Sorted(maps.Keys(moviesByFilter)) {
+		groups = append(groups, radarr.MovieGroup{
+			Filter:      filterName,
+			Description: filters[filterName].Description,
+			Movies:      moviesByFilter[filterName],
+		})
+	}
+	return printFormatted(formatter.FormatMovieList(groups, formatOptions()))
 }
 
 // deleteCmd represents the delete command
@@ -312,103 +434,334 @@ func init() {
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	// Check if any filters are defined
-	if len(cfg.Filter) == 0 {
-		fmt.Println("No filters defined in configuration.")
+	if reviewDelete && machineOutput() {
+		return fmt.Errorf("--review only supports text output")
+	}
+
+	if len(cfg.Filter.Enabled()) == 0 && !seriesFiltersEnabled() {
+		fmt.Fprintln(console(), "No filters defined in configuration.")
+		return nil
+	}
+
+	if err := deleteMovies(); err != nil {
+		return err
+	}
+	if seriesFiltersEnabled() {
+		return deleteSeries(context.Background())
+	}
+	return nil
+}
+
+// deleteMovies applies each enabled movie filter's action to its matches
+func deleteMovies() error {
+	filters := cfg.Filter.Enabled()
+	if len(filters) == 0 {
 		return nil
 	}
 
-	logger.Info().Int("filter_count", len(cfg.Filter)).Msg("Processing filters for deletion")
+	logger.Info().Int("filter_count", len(filters)).Msg("Processing filters for deletion")
 
 	// Get all movies once
 	ctx := context.Background()
-	allMovies, err := operations.GetAllMovies(ctx)
+	allMovies, err := getAllMovies(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get movies: %w", err)
 	}
 
-	// Track which movies match which filters
-	moviesByFilter := make(map[string][]radarr.MovieInfo)
-	uniqueMovies := make(map[int64]radarr.MovieInfo) // Track unique movies by ID
+	// Quarantined movies stay in Radarr unmonitored until purged, leave them be
+	if cfg.Quarantine.Enabled {
+		if allMovies, err = withoutQuarantined(allMovies); err != nil {
+			return err
+		}
+	}
 
-	// Process each filter
-	for filterName, filterExpr := range cfg.Filter {
-		logger.Debug().Str("filter", filterName).Str("expression", filterExpr).Msg("Processing filter")
+	// Compile filters and protection rules
+	manager := filter.NewManager()
+	defer manager.Close(ctx)
 
-		// Parse filter
-		filterFunc, err := filter.ParseAndCreateFilter(filterExpr)
-		if err != nil {
-			logger.Error().Err(err).Str("filter", filterName).Msg("Invalid filter expression")
-			continue
+	expressions := make(map[string]string, len(filters))
+	for filterName, def := range filters {
+		expressions[filterName] = def.Expression
+	}
+	if err := manager.RegisterFilters(expressions); err != nil {
+		return err
+	}
+	for filterName, def := range filters {
+		if err := manager.SetMinWatchPercent(filterName, def.MinWatchPercent); err != nil {
+			return err
 		}
+	}
+	if err := manager.RegisterProtections(cfg.Protect); err != nil {
+		return err
+	}
 
-		// Find matching movies
-		for _, movie := range allMovies {
-			if filterFunc(movie) {
-				moviesByFilter[filterName] = append(moviesByFilter[filterName], movie)
-				uniqueMovies[movie.ID] = movie
+	matchesByFilter, err := manager.EvaluateAll(ctx, allMovies)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate filters: %w", err)
+	}
+	restrictToFilterInstances(filters, matchesByFilter)
+
+	// Each movie is handled by exactly one filter: the one with the most
+	// destructive action, ties going to the first filter by name
+	claimedBy := make(map[radarr.MovieKey]string)
+	filterNames := slices.Sorted(maps.Keys(filters))
+
+	for _, filterName := range filterNames {
+		def := filters[filterName]
+		for _, movie := range matchesByFilter[filterName] {
+			if current, ok := claimedBy[movie.Key()]; ok && actionPriority[filters[current].Action] >= actionPriority[def.Action] {
+				continue
 			}
+			claimedBy[movie.Key()] = filterName
 		}
 	}
 
-	// Convert unique movies to slice
-	var moviesToDelete []radarr.MovieInfo
-	for _, movie := range uniqueMovies {
-		moviesToDelete = append(moviesToDelete, movie)
+	var candidates []radarr.MovieInfo
+	for _, movie := range allMovies {
+		if _, ok := claimedBy[movie.Key()]; ok {
+			candidates = append(candidates, movie)
+		}
 	}
 
-	if len(moviesToDelete) == 0 {
-		fmt.Println("No movies found matching any filter criteria.")
-		return nil
+	// Protection rules run after every filter and always win
+	candidates, protected := manager.ApplyProtections(candidates)
+
+	// Group remaining movies by filter
+	moviesByFilter := make(map[string][]radarr.MovieInfo)
+	for _, movie := range candidates {
+		filterName := claimedBy[movie.Key()]
+		moviesByFilter[filterName] = append(moviesByFilter[filterName], movie)
+	}
+
+	// Filters with complete_collections leave a collection alone until all of it matches
+	var held []radarr.SkippedMovie
+	for filterName, movies := range moviesByFilter {
+		if !filters[filterName].CompleteCollections {
+			continue
+		}
+		kept, incomplete := filter.CompleteCollections(movies, allMovies)
+		for _, movie := range incomplete {
+			held = append(held, radarr.SkippedMovie{Movie: movie, Filter: filterName})
+		}
+		moviesByFilter[filterName] = kept
 	}
 
-	// Display what will be deleted, grouped by filter
-	fmt.Printf("\nFound %d movie", len(moviesToDelete))
-	if len(moviesToDelete) != 1 {
-		fmt.Printf("s")
+	// With staging, delete filters only act on movies that have been leaving soon long enough
+	var staged *stagingChanges
+	if cfg.Staging.Enabled {
+		if staged, err = planStaging(filters, moviesByFilter, allMovies); err != nil {
+			return err
+		}
 	}
-	fmt.Println(" to delete")
-	fmt.Println()
 
+	// Apply per-filter limits, oldest movies first. This happens before the
+	// disk-space target so its plan shows what can really be reclaimed.
 	for filterName, movies := range moviesByFilter {
+		maxPerRun := filters[filterName].MaxPerRun
+		if maxPerRun == 0 || len(movies) <= maxPerRun {
+			continue
+		}
+		slices.SortFunc(movies, func(a, b radarr.MovieInfo) int {
+			return a.Added.Compare(b.Added)
+		})
+		logger.Info().Str("filter", filterName).Int("matched", len(movies)).Int("max_per_run", maxPerRun).
+			Msg("Filter matched more movies than allowed per run, acting on the oldest only")
+		moviesByFilter[filterName] = movies[:maxPerRun]
+	}
+
+	// With a disk-space target, only delete as much as needed, in ranked order
+	if targetMode() {
+		if err := applyTarget(ctx, filters, moviesByFilter, matchesByFilter); err != nil {
+			return err
+		}
+	}
+
+	plan := radarr.DeletePlan{}
+	for _, p := range protected {
+		if p.Err != nil {
+			logger.Warn().Err(p.Err).Str("rule", p.Rule).Str("movie", p.Movie.Title).
+				Msg("Protection rule failed to evaluate, protecting the movie")
+		}
+		plan.Protected = append(plan.Protected, radarr.SkippedMovie{Movie: p.Movie, Filter: claimedBy[p.Movie.Key()], Rule: p.Rule})
+	}
+	slices.SortFunc(held, func(a, b radarr.SkippedMovie) int {
+		return strings.Compare(strings.ToLower(a.Movie.Title), strings.ToLower(b.Movie.Title))
+	})
+	plan.Held = held
+
+	// Display what will happen, grouped by filter
+	var total, pending int
+	for _, filterName := range filterNames {
+		movies := moviesByFilter[filterName]
 		if len(movies) == 0 {
 			continue
 		}
+		def := filters[filterName]
+		total += len(movies)
+		if def.Action != config.ActionNotify {
+			pending += len(movies)
+		}
+		plan.Groups = append(plan.Groups, radarr.MovieGroup{
+			Filter:      filterName,
+			Description: def.Description,
+			Action:      describeAction(def),
+			Movies:      movies,
+		})
+	}
+
+	options := radarr.FormatOptions{ShowInstance: multipleInstances()}
+	if err := printFormatted(formatter.FormatMoviesToDelete(plan, options)); err != nil {
+		return err
+	}
+	if total == 0 && staged.count() == 0 {
+		return nil
+	}
 
-		fmt.Printf("\u256d\u2500 Filter: %s (%d match", filterName, len(movies))
-		if len(movies) != 1 {
-			fmt.Printf("es")
+	if total > 0 {
+		fmt.Fprintf(console(), "Found %d movie", total)
+		if total != 1 {
+			fmt.Fprintf(console(), "s")
 		}
-		fmt.Println(")")
+		fmt.Fprintf(console(), " (%d to change)\n", pending)
+	}
+
+	if pending == 0 && staged.count() == 0 {
+		return nil
+	}
 
-		for i, movie := range movies {
-			isLast := i == len(movies)-1
-			prefix := "\u251c"
-			if isLast {
-				prefix = "\u2570"
+	// Reviewing confirms the movies picked, so there's no prompt afterwards
+	reviewed := reviewDelete && pending > 0
+	if reviewed {
+		moviesByFilter, err = reviewCandidates(ctx, filters, filterNames, moviesByFilter)
+		if errors.Is(err, review.ErrCancelled) {
+			logger.Info().Msg("Cancelled by user")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		pending = 0
+		for filterName, movies := range moviesByFilter {
+			if filters[filterName].Action != config.ActionNotify {
+				pending += len(movies)
 			}
-			fmt.Printf("%s\u2500\u2500 %s (%d)\n", prefix, movie.Title, movie.Year)
 		}
-		fmt.Println()
+		if pending == 0 && staged.count() == 0 {
+			logger.Info().Msg("No movies selected")
+			return nil
+		}
+		fmt.Fprintf(console(), "Selected %d movie(s) to change\n", pending)
 	}
 
-	// Check for watched movies if not ignoring
-	if !ignoreWatched {
-		var watchedCount int
-		for _, movie := range moviesToDelete {
-			if movie.Watched {
-				watchedCount++
-			}
+	if cfg.Safety.DryRun {
+		logger.Info().Msg("DRY RUN MODE - No changes will be made")
+		return nil
+	}
+
+	if cfg.Safety.ConfirmDelete && !noConfirm && !reviewed {
+		fmt.Fprintf(console(), "\nAre you sure you want to apply these actions to %d movie(s)? [y/N]: ", pending+staged.count())
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			logger.Info().Msg("Cancelled by user")
+			return nil
 		}
 	}
 
-	// Delete movies
-	deleteOpts := radarr.DeleteOptions{
-		DryRun:        cfg.Safety.DryRun,
-		ConfirmDelete: cfg.Safety.ConfirmDelete && !noConfirm,
+	// Leaving soon tags are only changed once the run goes ahead
+	var errs []error
+	if err := staged.apply(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Dispatch each filter's movies to its action
+	for _, filterName := range filterNames {
+		movies := moviesByFilter[filterName]
+		if len(movies) == 0 {
+			continue
+		}
+		if err := applyFilterAction(ctx, filterName, filters[filterName], movies); err != nil {
+			errs = append(errs, fmt.Errorf("filter '%s': %w", filterName, err))
+		}
 	}
 
-	return operations.DeleteMovies(ctx, moviesToDelete, deleteOpts)
+	return errors.Join(errs...)
+}
+
+// actionPriority ranks actions so the most destructive one wins when filters overlap
+var actionPriority = map[config.FilterAction]int{
+	config.ActionNotify:               0,
+	config.ActionTag:                  1,
+	config.ActionChangeQualityProfile: 2,
+	config.ActionUnmonitor:            3,
+	config.ActionDelete:               4,
+}
+
+// describeAction returns a short human readable description of a filter's action
+func describeAction(def config.FilterDefinition) string {
+	switch def.Action {
+	case config.ActionDelete:
+		if quarantinesDeletes(def) {
+			return "quarantine"
+		}
+		var extras []string
+		if !def.DeleteFiles {
+			extras = append(extras, "keep files")
+		}
+		if def.AddImportExclusion {
+			extras = append(extras, "exclude from import")
+		}
+		if len(extras) > 0 {
+			return fmt.Sprintf("delete, %s", strings.Join(extras, ", "))
+		}
+		return "delete"
+	case config.ActionTag:
+		return fmt.Sprintf("tag %q", def.Tag)
+	case config.ActionChangeQualityProfile:
+		return fmt.Sprintf("quality profile %q", def.QualityProfile)
+	case config.ActionNotify:
+		return "notify only"
+	}
+	return string(def.Action)
+}
+
+// applyFilterAction performs a filter's configured action on its matched movies,
+// in the Radarr instance each movie is in
+func applyFilterAction(ctx context.Context, filterName string, def config.FilterDefinition, movies []radarr.MovieInfo) error {
+	return forEachInstanceMovies(movies, func(operations *radarr.Operations, movies []radarr.MovieInfo) error {
+		return applyInstanceFilterAction(ctx, operations, filterName, def, movies)
+	})
+}
+
+// applyInstanceFilterAction performs a filter's action on movies from a single Radarr instance
+func applyInstanceFilterAction(ctx context.Context, operations *radarr.Operations, filterName string, def config.FilterDefinition, movies []radarr.MovieInfo) error {
+	switch def.Action {
+	case config.ActionDelete:
+		opts := radarr.DeleteOptions{
+			KeepFiles:          !def.DeleteFiles,
+			AddImportExclusion: def.AddImportExclusion,
+			FilterName:         filterName,
+			FilterExpression:   def.Expression,
+		}
+		if quarantinesDeletes(def) {
+			store, err := openQuarantine()
+			if err != nil {
+				return err
+			}
+			return operations.QuarantineMovies(ctx, movies, store, opts)
+		}
+		return operations.DeleteMovies(ctx, movies, opts)
+	case config.ActionUnmonitor:
+		return operations.UnmonitorMovies(ctx, movies)
+	case config.ActionTag:
+		return operations.TagMovies(ctx, movies, def.Tag)
+	case config.ActionChangeQualityProfile:
+		return operations.ChangeQualityProfile(ctx, movies, def.QualityProfile)
+	case config.ActionNotify:
+		return nil
+	}
+	return fmt.Errorf("unknown action: %s", def.Action)
 }
 
 // testCmd represents the test command
@@ -422,8 +775,14 @@ var testCmd = &cobra.Command{
 
 func runTest(cmd *cobra.Command, args []string) error {
 	// Test Radarr
-	logger.Info().Str("url", cfg.Radarr.URL).Msg("Testing Radarr connection")
-	logger.Info().Msg("✓ Radarr connection successful")
+	for _, instance := range cfg.Radarr {
+		event := logger.Info().Str("url", instance.URL)
+		if multipleInstances() {
+			event = event.Str("instance", instance.Name)
+		}
+		event.Msg("Testing Radarr connection")
+		logger.Info().Msg("✓ Radarr connection successful")
+	}
 
 	// Test Tautulli if configured
 	if tautulliClient != nil {
@@ -433,6 +792,18 @@ func runTest(cmd *cobra.Command, args []string) error {
 		logger.Info().Msg("Tautulli integration: Not configured")
 	}
 
+	// Test Plex if configured
+	if plexClient != nil {
+		logger.Info().Str("url", cfg.Plex.URL).Msg("Testing Plex connection")
+		logger.Info().Msg("✓ Plex connection successful")
+	}
+
+	// Test Jellyfin and Emby if configured
+	for _, client := range mediaServers {
+		logger.Info().Str("server", client.Name()).Msg("Testing media server connection")
+		logger.Info().Msg("✓ Media server connection successful")
+	}
+
 	// Test Overseerr if configured
 	if overseerrClient != nil {
 		logger.Info().Str("url", cfg.Overseerr.URL).Msg("Testing Overseerr connection")
@@ -441,6 +812,14 @@ func runTest(cmd *cobra.Command, args []string) error {
 		logger.Info().Msg("Overseerr integration: Not configured")
 	}
 
+	// Test Sonarr if configured
+	if sonarrOperations != nil {
+		logger.Info().Str("url", cfg.Sonarr.URL).Msg("Testing Sonarr connection")
+		logger.Info().Msg("✓ Sonarr connection successful")
+	} else {
+		logger.Info().Msg("Sonarr integration: Not configured")
+	}
+
 	// Test qBittorrent if configured
 	if cfg.QBittorrent.URL != "" {
 		logger.Info().Str("url", cfg.QBittorrent.URL).Msg("Testing qBittorrent connection")
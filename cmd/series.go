@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/sonarr"
+)
+
+var sonarrOperations *sonarr.Operations
+
+// seriesMatches holds what a series filter matched: whole series, or single
+// seasons for filters with season scope
+type seriesMatches struct {
+	Series  []sonarr.SeriesInfo
+	Seasons []sonarr.SeasonMatch
+}
+
+// count returns the number of matched series or seasons
+func (m seriesMatches) count() int {
+	return len(m.Series) + len(m.Seasons)
+}
+
+// initSonarr connects to Sonarr when configured, reusing the Tautulli and Overseerr clients
+func initSonarr() {
+	if cfg.Sonarr.URL == "" || cfg.Sonarr.APIKey == "" {
+		return
+	}
+
+	client, err := sonarr.NewClient(cfg.Sonarr.URL, cfg.Sonarr.APIKey, logger)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to create Sonarr client, continuing without series")
+		return
+	}
+
+	sonarrOperations = sonarr.NewOperations(client, logger)
+	sonarrOperations.SetWatchThreshold(watchThreshold())
+	if tautulliClient != nil {
+		sonarrOperations.SetTautulliClient(tautulliClient)
+	}
+	if overseerrClient != nil {
+		sonarrOperations.SetOverseerrClient(overseerrClient)
+	}
+	logger.Info().Msg("Sonarr integration enabled")
+}
+
+// validateSeriesFilters compiles every configured series filter and reports the first failure
+func validateSeriesFilters(filters config.FilterConfig) error {
+	for _, filterName := range slices.Sorted(maps.Keys(filters)) {
+		if _, err := filter.CompileSeriesFilter(filters[filterName].Expression); err != nil {
+			return fmt.Errorf("invalid series filter '%s': %w", filterName, err)
+		}
+	}
+	return nil
+}
+
+// seriesFiltersEnabled reports whether series filters will run in this invocation
+func seriesFiltersEnabled() bool {
+	return sonarrOperations != nil && len(cfg.SeriesFilter.Enabled()) > 0
+}
+
+// evaluateSeriesFilters runs every series filter against the library. Each series
+// or season is handled by exactly one filter, the one with the most destructive
+// action, and seasons of series that are deleted as a whole are dropped.
+func evaluateSeriesFilters(filters config.FilterConfig, library []sonarr.SeriesInfo) (map[string]seriesMatches, error) {
+	type key struct {
+		seriesID int64
+		season   int // -1 for the whole series
+	}
+	claimedBy := make(map[key]string)
+	filterNames := slices.Sorted(maps.Keys(filters))
+
+	claim := func(k key, filterName string) {
+		if current, ok := claimedBy[k]; ok && actionPriority[filters[current].Action] >= actionPriority[filters[filterName].Action] {
+			return
+		}
+		claimedBy[k] = filterName
+	}
+
+	for _, filterName := range filterNames {
+		def := filters[filterName]
+		compiled, err := filter.CompileSeriesFilter(def.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series filter '%s': %w", filterName, err)
+		}
+
+		for _, series := range library {
+			if def.Scope != config.ScopeSeason {
+				if compiled.EvaluateSeries(series) {
+					claim(key{series.ID, -1}, filterName)
+				}
+				continue
+			}
+			for _, season := range series.Seasons {
+				if season.EpisodeFileCount > 0 && compiled.EvaluateSeason(series, season) {
+					claim(key{series.ID, season.Number}, filterName)
+				}
+			}
+		}
+	}
+
+	matches := make(map[string]seriesMatches)
+	for _, series := range library {
+		seriesFilter, seriesClaimed := claimedBy[key{series.ID, -1}]
+		if seriesClaimed {
+			m := matches[seriesFilter]
+			m.Series = append(m.Series, series)
+			matches[seriesFilter] = m
+		}
+
+		for _, season := range series.Seasons {
+			filterName, ok := claimedBy[key{series.ID, season.Number}]
+			if !ok {
+				continue
+			}
+			if seriesClaimed && filters[seriesFilter].Action == config.ActionDelete {
+				continue
+			}
+			m := matches[filterName]
+			m.Seasons = append(m.Seasons, sonarr.SeasonMatch{Series: series, Season: season})
+			matches[filterName] = m
+		}
+	}
+
+	// Apply per-filter limits, oldest series first
+	for filterName, m := range matches {
+		maxPerRun := filters[filterName].MaxPerRun
+		if maxPerRun == 0 || m.count() <= maxPerRun {
+			continue
+		}
+		logger.Info().Str("series_filter", filterName).Int("matched", m.count()).Int("max_per_run", maxPerRun).
+			Msg("Series filter matched more than allowed per run, acting on the oldest only")
+		slices.SortStableFunc(m.Series, func(a, b sonarr.SeriesInfo) int {
+			return a.Added.Compare(b.Added)
+		})
+		slices.SortStableFunc(m.Seasons, func(a, b sonarr.SeasonMatch) int {
+			return a.Series.Added.Compare(b.Series.Added)
+		})
+		m.Series = m.Series[:min(len(m.Series), maxPerRun)]
+		m.Seasons = m.Seasons[:min(len(m.Seasons), maxPerRun)]
+		matches[filterName] = m
+	}
+
+	return matches, nil
+}
+
+// runSeriesFilters fetches the Sonarr library and evaluates the enabled series filters
+func runSeriesFilters(ctx context.Context) (config.FilterConfig, map[string]seriesMatches, error) {
+	filters := cfg.SeriesFilter.Enabled()
+	logger.Info().Int("filter_count", len(filters)).Msg("Processing series filters")
+
+	library, err := sonarrOperations.GetAllSeries(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	matches, err := evaluateSeriesFilters(filters, library)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filters, matches, nil
+}
+
+// listSeries prints the series and seasons matching each series filter
+func listSeries(ctx context.Context) error {
+	filters, matches, err := runSeriesFilters(ctx)
+	if err != nil {
+		return err
+	}
+
+	printSeriesMatches(filters, matches)
+	return nil
+}
+
+// deleteSeries applies each series filter's action to its matches
+func deleteSeries(ctx context.Context) error {
+	filters, matches, err := runSeriesFilters(ctx)
+	if err != nil {
+		return err
+	}
+
+	total, pending := printSeriesMatches(filters, matches)
+	if total == 0 || pending == 0 {
+		return nil
+	}
+
+	if cfg.Safety.DryRun {
+		logger.Info().Msg("DRY RUN MODE - No series will be changed")
+		return nil
+	}
+
+	if cfg.Safety.ConfirmDelete && !noConfirm {
+		fmt.Fprintf(console(), "\nAre you sure you want to apply these actions to %d series/season(s)? [y/N]: ", pending)
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			logger.Info().Msg("Cancelled by user")
+			return nil
+		}
+	}
+
+	var errs []error
+	for _, filterName := range slices.Sorted(maps.Keys(matches)) {
+		if err := applySeriesFilterAction(ctx, filters[filterName], matches[filterName]); err != nil {
+			errs = append(errs, fmt.Errorf("series filter '%s': %w", filterName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// printSeriesMatches shows what each series filter matched, grouped by filter.
+// It returns the number of matches and how many of them will be changed.
+func printSeriesMatches(filters config.FilterConfig, matches map[string]seriesMatches) (total, pending int) {
+	for _, filterName := range slices.Sorted(maps.Keys(matches)) {
+		m := matches[filterName]
+		def := filters[filterName]
+		total += m.count()
+		if def.Action != config.ActionNotify {
+			pending += m.count()
+		}
+
+		fmt.Fprintf(console(), "╭─ Series filter: %s → %s (%d match", filterName, describeSeriesAction(def), m.count())
+		if m.count() != 1 {
+			fmt.Fprintf(console(), "es")
+		}
+		fmt.Fprintln(console(), ")")
+		if def.Description != "" {
+			fmt.Fprintf(console(), "│  %s\n", def.Description)
+		}
+
+		var lines []string
+		for _, series := range m.Series {
+			lines = append(lines, fmt.Sprintf("%s (%d) - %s, %d/%d episodes watched",
+				series.Title, series.Year, formatSize(series.SizeBytes), series.EpisodesWatched, series.EpisodeFileCount))
+		}
+		for _, match := range m.Seasons {
+			lines = append(lines, fmt.Sprintf("%s (%d) season %d - %s, %d/%d episodes watched",
+				match.Series.Title, match.Series.Year, match.Season.Number, formatSize(match.Season.SizeBytes),
+				match.Season.EpisodesWatched, match.Season.EpisodeFileCount))
+		}
+		for i, line := range lines {
+			fmt.Fprintf(console(), "%s── %s\n", treePrefix(i, len(lines)), line)
+		}
+		fmt.Fprintln(console())
+	}
+
+	if total == 0 {
+		fmt.Fprintln(console(), "No series found matching any series filter criteria.")
+		return 0, 0
+	}
+
+	fmt.Fprintf(console(), "Found %d series/season match", total)
+	if total != 1 {
+		fmt.Fprintf(console(), "es")
+	}
+	fmt.Fprintf(console(), " (%d to change)\n", pending)
+	return total, pending
+}
+
+// describeSeriesAction returns a short human readable description of a series filter's action
+func describeSeriesAction(def config.FilterDefinition) string {
+	if def.Scope == config.ScopeSeason {
+		switch def.Action {
+		case config.ActionDelete:
+			return "delete season files, unmonitor season"
+		case config.ActionUnmonitor:
+			return "unmonitor season"
+		}
+	}
+	if def.Action == config.ActionDelete {
+		var extras []string
+		if !def.DeleteFiles {
+			extras = append(extras, "keep files")
+		}
+		if def.AddImportExclusion {
+			extras = append(extras, "exclude from import")
+		}
+		if len(extras) > 0 {
+			return fmt.Sprintf("delete series, %s", strings.Join(extras, ", "))
+		}
+		return "delete series"
+	}
+	return describeAction(def)
+}
+
+// applySeriesFilterAction performs a series filter's configured action on its matches
+func applySeriesFilterAction(ctx context.Context, def config.FilterDefinition, matches seriesMatches) error {
+	if def.Scope == config.ScopeSeason {
+		switch def.Action {
+		case config.ActionDelete:
+			return sonarrOperations.DeleteSeasons(ctx, matches.Seasons)
+		case config.ActionUnmonitor:
+			return sonarrOperations.UnmonitorSeasons(ctx, matches.Seasons)
+		case config.ActionNotify:
+			return nil
+		}
+		return fmt.Errorf("action %s is not supported for seasons", def.Action)
+	}
+
+	switch def.Action {
+	case config.ActionDelete:
+		return sonarrOperations.DeleteSeries(ctx, matches.Series, sonarr.DeleteOptions{
+			KeepFiles:          !def.DeleteFiles,
+			AddImportExclusion: def.AddImportExclusion,
+		})
+	case config.ActionUnmonitor:
+		return sonarrOperations.UnmonitorSeries(ctx, matches.Series)
+	case config.ActionTag:
+		return sonarrOperations.TagSeries(ctx, matches.Series, def.Tag)
+	case config.ActionChangeQualityProfile:
+		return sonarrOperations.ChangeQualityProfile(ctx, matches.Series, def.QualityProfile)
+	case config.ActionNotify:
+		return nil
+	}
+	return fmt.Errorf("unknown action: %s", def.Action)
+}
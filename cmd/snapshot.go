@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/snapshot"
+)
+
+var (
+	fromSnapshot   string
+	loadedSnapshot *snapshot.Snapshot // Set when a command runs from --from-snapshot
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot [file]",
+	Short: "Save the library with all its watch and request data to a file",
+	Long: `Save every movie in every Radarr instance, with the watch, request,
+hardlink and qBittorrent data filters see, to a JSON file.
+
+Run list or filter explain with --from-snapshot to evaluate filters against
+the file instead of the live services, to iterate on expressions quickly or
+to reproduce a problem from a bug report.
+
+The file defaults to arrbiter-snapshot-<date>.json in the current directory.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: initializeApp,
+	RunE:    runSnapshot,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	snap, err := takeSnapshot(context.Background())
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("arrbiter-snapshot-%s.json", snap.CreatedAt.Format("20060102-150405"))
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if err := snapshot.Save(path, *snap); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved %d movies to %s\n", len(snap.Movies), path)
+	return nil
+}
+
+// takeSnapshot fetches the whole library with every piece of data filters can use
+func takeSnapshot(ctx context.Context) (*snapshot.Snapshot, error) {
+	now := time.Now()
+
+	// Custom formats are otherwise only fetched when a filter uses them
+	forEachInstance(func(ops *radarr.Operations) {
+		ops.SetFetchFileDetails(true)
+	})
+
+	allMovies, err := getAllMovies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movies: %w", err)
+	}
+
+	// Hardlink and qBittorrent data is only gathered by the hardlink command otherwise
+	var movies []radarr.MovieInfo
+	err = forEachInstanceMovies(allMovies, func(ops *radarr.Operations, instanceMovies []radarr.MovieInfo) error {
+		if err := ops.AddHardlinkInfo(ctx, instanceMovies); err != nil {
+			return fmt.Errorf("failed to check hardlinks: %w", err)
+		}
+		movies = append(movies, instanceMovies...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot.Snapshot{
+		Version:   snapshot.Version,
+		CreatedAt: now,
+		Arrbiter:  version,
+		Instances: cfg.Radarr.Names(),
+		Accounts:  userAccounts,
+		Movies:    movies,
+	}, nil
+}
+
+// initializeWithSnapshot sets up a command that can run from --from-snapshot.
+// Without the flag it connects to every service as usual; with it no service
+// is contacted and the library comes from the file.
+func initializeWithSnapshot(cmd *cobra.Command, args []string) error {
+	if fromSnapshot == "" {
+		return initializeApp(cmd, args)
+	}
+
+	var err error
+	loadedSnapshot, err = snapshot.Load(fromSnapshot)
+	if err != nil {
+		return err
+	}
+	if err := initializeOffline(cmd, args, loadedSnapshot); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("file", fromSnapshot).
+		Time("created", loadedSnapshot.CreatedAt).
+		Int("movies", len(loadedSnapshot.Movies)).
+		Msg("Using library snapshot")
+	return nil
+}
+
+// initializeOffline loads the configuration and prepares filters to run
+// against a snapshot, without contacting any service
+func initializeOffline(cmd *cobra.Command, args []string, snap *snapshot.Snapshot) error {
+	if err := loadConfig(cmd, args); err != nil {
+		return err
+	}
+	if err := validateFilters(cfg.Filter, cfg.Protect); err != nil {
+		return err
+	}
+
+	// Instances only carry their names, there is nothing to connect to
+	radarrInstances = nil
+	for _, name := range snap.Instances {
+		radarrInstances = append(radarrInstances, radarrInstance{Name: name})
+	}
+
+	filter.SetWatchThreshold(watchThreshold())
+	initIdentities(snap.Accounts)
+	return nil
+}
+
+// loadMovies returns every movie, from the snapshot when running from one
+func loadMovies(ctx context.Context) ([]radarr.MovieInfo, error) {
+	if loadedSnapshot != nil {
+		return loadedSnapshot.Movies, nil
+	}
+	return getAllMovies(ctx)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/staging"
+)
+
+// stagingChanges are the leaving soon tags a run adds and removes. They are
+// worked out before confirmation and only applied once the run goes ahead.
+type stagingChanges struct {
+	store   *staging.Store
+	stage   []radarr.MovieInfo // Newly matched, to tag
+	release []radarr.MovieInfo // No longer matching and still in Radarr, to untag
+	remove  []staging.Entry    // No longer matching, to forget
+	now     time.Time
+}
+
+// planStaging holds back delete candidates until they have been tagged as
+// leaving soon for the grace period. Only movies whose grace period is over
+// are left in the delete filters' groups; the tags for newly matched movies
+// and movies that stopped matching are returned to apply once confirmed.
+func planStaging(filters config.FilterConfig, moviesByFilter map[string][]radarr.MovieInfo, allMovies []radarr.MovieInfo) (*stagingChanges, error) {
+	store, err := staging.Open(filepath.Join(cfg.DataDir, "staging.json"))
+	if err != nil {
+		return nil, err
+	}
+	store.AssignInstance(radarrInstances[0].Name)
+
+	var candidates []radarr.MovieInfo
+	for filterName, movies := range moviesByFilter {
+		if filters[filterName].Action == config.ActionDelete {
+			candidates = append(candidates, movies...)
+		}
+	}
+
+	now := time.Now()
+	grace := time.Duration(cfg.Staging.GraceDays) * 24 * time.Hour
+	plan := store.Plan(candidates, grace, now)
+
+	// Only movies past their grace period stay up for deletion
+	ready := make(map[radarr.MovieKey]bool, len(plan.Ready))
+	for _, movie := range plan.Ready {
+		ready[movie.Key()] = true
+	}
+	for filterName, movies := range moviesByFilter {
+		if filters[filterName].Action != config.ActionDelete {
+			continue
+		}
+		var kept []radarr.MovieInfo
+		for _, movie := range movies {
+			if ready[movie.Key()] {
+				kept = append(kept, movie)
+			}
+		}
+		if len(kept) == 0 {
+			delete(moviesByFilter, filterName)
+		} else {
+			moviesByFilter[filterName] = kept
+		}
+	}
+
+	// Staged movies that are still in Radarr get their tag removed
+	existing := make(map[radarr.MovieKey]radarr.MovieInfo, len(allMovies))
+	for _, movie := range allMovies {
+		existing[movie.Key()] = movie
+	}
+	changes := &stagingChanges{store: store, stage: plan.Stage, remove: plan.Release, now: now}
+	for _, entry := range plan.Release {
+		if movie, ok := existing[entry.Key()]; ok {
+			changes.release = append(changes.release, movie)
+		}
+	}
+
+	printStagingPlan(plan, store, now)
+
+	return changes, nil
+}
+
+// count returns the number of movies whose leaving soon tag changes
+func (c *stagingChanges) count() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.stage) + len(c.release)
+}
+
+// apply tags and untags the movies and saves the staging store
+func (c *stagingChanges) apply(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.stage) > 0 {
+		if err := forEachInstanceMovies(c.stage, func(operations *radarr.Operations, movies []radarr.MovieInfo) error {
+			return operations.TagMovies(ctx, movies, cfg.Staging.Tag)
+		}); err != nil {
+			return fmt.Errorf("failed to tag movies as leaving soon: %w", err)
+		}
+		c.store.Add(c.stage, c.now)
+	}
+
+	if len(c.release) > 0 {
+		if err := forEachInstanceMovies(c.release, func(operations *radarr.Operations, movies []radarr.MovieInfo) error {
+			return operations.UntagMovies(ctx, movies, cfg.Staging.Tag)
+		}); err != nil {
+			return fmt.Errorf("failed to remove leaving soon tag: %w", err)
+		}
+	}
+	c.store.Remove(c.remove)
+
+	return c.store.Save()
+}
+
+// printStagingPlan shows which movies are leaving soon and which were spared
+func printStagingPlan(plan staging.Plan, store *staging.Store, now time.Time) {
+	if len(plan.Stage) > 0 {
+		fmt.Fprintf(console(), "╭─ Leaving soon: newly tagged '%s' (%d)\n", cfg.Staging.Tag, len(plan.Stage))
+		for i, movie := range plan.Stage {
+			fmt.Fprintf(console(), "%s── %s (%d)%s - deletion after %s\n", treePrefix(i, len(plan.Stage)), movie.Title, movie.Year, instanceLabel(movie),
+				now.AddDate(0, 0, cfg.Staging.GraceDays).Format("2006-01-02"))
+		}
+		fmt.Fprintln(console())
+	}
+
+	if len(plan.Waiting) > 0 {
+		fmt.Fprintf(console(), "╭─ Leaving soon: in grace period (%d)\n", len(plan.Waiting))
+		for i, movie := range plan.Waiting {
+			entry, _ := store.Get(movie.Key())
+			fmt.Fprintf(console(), "%s── %s (%d)%s - deletion after %s\n", treePrefix(i, len(plan.Waiting)), movie.Title, movie.Year, instanceLabel(movie),
+				entry.StagedAt.AddDate(0, 0, cfg.Staging.GraceDays).Format("2006-01-02"))
+		}
+		fmt.Fprintln(console())
+	}
+
+	if len(plan.Release) > 0 {
+		fmt.Fprintf(console(), "╭─ No longer leaving (%d)\n", len(plan.Release))
+		for i, entry := range plan.Release {
+			fmt.Fprintf(console(), "%s── %s (%d) - no longer matches, removing tag\n", treePrefix(i, len(plan.Release)), entry.Title, entry.Year)
+		}
+		fmt.Fprintln(console())
+	}
+}
+
+// treePrefix returns the box-drawing branch for item i of n
+func treePrefix(i, n int) string {
+	if i == n-1 {
+		return "╰"
+	}
+	return "├"
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/s0up4200/arrbiter/config"
+	"github.com/s0up4200/arrbiter/filter"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+var (
+	reclaimSize       string
+	targetFreePercent float64
+	targetSort        []string
+	targetRootFolder  string
+	targetRank        bool
+)
+
+func init() {
+	deleteCmd.Flags().StringVar(&reclaimSize, "reclaim", "", "delete only until this much space is reclaimed (e.g. 500GB, 1.5TB)")
+	deleteCmd.Flags().Float64Var(&targetFreePercent, "free-percent", 0, "delete only until the root folder's disk is this percent free")
+	deleteCmd.Flags().StringArrayVar(&targetSort, "sort", nil, "expression ranking delete candidates, deleted first to last (repeatable, default target.sort)")
+	deleteCmd.Flags().StringVar(&targetRootFolder, "root-folder", "", "root folder --free-percent applies to (default target.root_folder)")
+	deleteCmd.Flags().BoolVar(&targetRank, "rank", false, "rank --reclaim/--free-percent candidates by combined filter score instead of --sort")
+	deleteCmd.MarkFlagsMutuallyExclusive("reclaim", "free-percent")
+	deleteCmd.MarkFlagsMutuallyExclusive("sort", "rank")
+}
+
+// targetMode reports whether delete should stop once a disk-space target is met
+func targetMode() bool {
+	return reclaimSize != "" || targetFreePercent > 0
+}
+
+// reclaimsSpace reports whether a filter's matches free disk space when acted on
+func reclaimsSpace(def config.FilterDefinition) bool {
+	return def.Action == config.ActionDelete && def.DeleteFiles
+}
+
+// applyTarget ranks the matches of every file-deleting filter and keeps only as
+// many as are needed to reach the disk-space target. Other filters are untouched.
+// With --rank, candidates are ordered by their combined score across matchesByFilter.
+func applyTarget(ctx context.Context, filters config.FilterConfig, moviesByFilter, matchesByFilter map[string][]radarr.MovieInfo) error {
+	order, err := targetOrder(filters, matchesByFilter)
+	if err != nil {
+		return err
+	}
+
+	var target int64
+	var rootFolder, goal string
+	if reclaimSize != "" {
+		if target, err = parseSize(reclaimSize); err != nil {
+			return err
+		}
+		goal = fmt.Sprintf("reclaim %s", formatSize(target))
+	} else {
+		if targetFreePercent >= 100 {
+			return fmt.Errorf("--free-percent must be below 100")
+		}
+		var client *radarr.Client
+		if rootFolder, client, err = resolveRootFolder(ctx); err != nil {
+			return err
+		}
+		disk, err := client.GetDiskSpace(ctx, rootFolder)
+		if err != nil {
+			return err
+		}
+		target = disk.BytesToReachFree(targetFreePercent)
+		goal = fmt.Sprintf("%g%% free on %s (currently %.1f%%)", targetFreePercent, rootFolder, disk.FreePercent())
+	}
+
+	var candidates []radarr.MovieInfo
+	filterOf := make(map[radarr.MovieKey]string)
+	for filterName, movies := range moviesByFilter {
+		if !reclaimsSpace(filters[filterName]) {
+			continue
+		}
+		for _, movie := range movies {
+			if rootFolder != "" && !movie.InRootFolder(rootFolder) {
+				continue
+			}
+			candidates = append(candidates, movie)
+			filterOf[movie.Key()] = filterName
+		}
+	}
+
+	order(candidates)
+	selected, reclaimed := radarr.SelectToReclaim(candidates, target)
+
+	// max_per_run has already cut the candidates down, say so when that's why the target is missed
+	if reclaimed < target {
+		for filterName, movies := range moviesByFilter {
+			if maxPerRun := filters[filterName].MaxPerRun; reclaimsSpace(filters[filterName]) && maxPerRun > 0 && len(movies) == maxPerRun {
+				logger.Warn().Str("filter", filterName).Int("max_per_run", maxPerRun).
+					Msg("Space target can't be met within the filter's max_per_run")
+			}
+		}
+	}
+
+	if cfg.Quarantine.Enabled {
+		logger.Warn().Msg("Quarantine is enabled, space is only reclaimed once quarantined movies are purged")
+	}
+
+	// Only the selected movies stay up for deletion
+	keep := make(map[radarr.MovieKey]bool, len(selected))
+	for _, movie := range selected {
+		keep[movie.Key()] = true
+	}
+	for filterName, movies := range moviesByFilter {
+		if !reclaimsSpace(filters[filterName]) {
+			continue
+		}
+		var kept []radarr.MovieInfo
+		for _, movie := range movies {
+			if keep[movie.Key()] {
+				kept = append(kept, movie)
+			}
+		}
+		if len(kept) == 0 {
+			delete(moviesByFilter, filterName)
+		} else {
+			moviesByFilter[filterName] = kept
+		}
+	}
+
+	printTargetPlan(goal, target, selected, filterOf, reclaimed)
+	return nil
+}
+
+// targetOrder returns the function that puts target candidates in deletion order
+func targetOrder(filters config.FilterConfig, matchesByFilter map[string][]radarr.MovieInfo) (func([]radarr.MovieInfo), error) {
+	if targetRank {
+		scores, err := compileScores(filters)
+		if err != nil {
+			return nil, err
+		}
+		position := make(map[radarr.MovieKey]int)
+		for i, ranked := range filter.Rank(scores, matchesByFilter) {
+			position[ranked.Movie.Key()] = i
+		}
+		return func(movies []radarr.MovieInfo) {
+			slices.SortStableFunc(movies, func(a, b radarr.MovieInfo) int {
+				return cmp.Compare(position[a.Key()], position[b.Key()])
+			})
+		}, nil
+	}
+
+	sortKeys := cfg.Target.Sort
+	if len(targetSort) > 0 {
+		sortKeys = targetSort
+	}
+	sorter, err := filter.CompileSort(sortKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort expression: %w", err)
+	}
+	return sorter.Sort, nil
+}
+
+// printTargetPlan shows the movies picked to reach the target in deletion order
+// with the space reclaimed so far
+func printTargetPlan(goal string, target int64, selected []radarr.MovieInfo, filterOf map[radarr.MovieKey]string, reclaimed int64) {
+	fmt.Fprintf(console(), "╭─ Space target: %s\n", goal)
+	if target == 0 {
+		fmt.Fprintln(console(), "╰── Target already met, nothing to delete")
+		fmt.Fprintln(console())
+		return
+	}
+
+	var cumulative int64
+	for _, movie := range selected {
+		cumulative += movie.MovieFile.Size
+		fmt.Fprintf(console(), "├── %s (%d)%s - %s, %s total [%s]\n", movie.Title, movie.Year, instanceLabel(movie),
+			formatSize(movie.MovieFile.Size), formatSize(cumulative), filterOf[movie.Key()])
+	}
+
+	if reclaimed >= target {
+		fmt.Fprintf(console(), "╰── Reclaims %s of %s needed with %d movie(s)\n", formatSize(reclaimed), formatSize(target), len(selected))
+	} else {
+		fmt.Fprintf(console(), "╰── Only %s of %s needed can be reclaimed from matching movies\n", formatSize(max(reclaimed, 0)), formatSize(target))
+	}
+	fmt.Fprintln(console())
+}
+
+// resolveRootFolder picks the root folder a free-space target applies to and
+// the client of the Radarr instance it belongs to, which reports its disk space
+func resolveRootFolder(ctx context.Context) (string, *radarr.Client, error) {
+	wanted := targetRootFolder
+	if wanted == "" {
+		wanted = cfg.Target.RootFolder
+	}
+
+	var folders []string
+	clientOf := make(map[string]*radarr.Client)
+	for _, instance := range radarrInstances {
+		instanceFolders, err := instance.Client.GetRootFolders(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, folder := range instanceFolders {
+			if _, ok := clientOf[folder]; !ok {
+				folders = append(folders, folder)
+				clientOf[folder] = instance.Client
+			}
+		}
+	}
+
+	if wanted != "" {
+		if client, ok := clientOf[wanted]; ok {
+			return wanted, client, nil
+		}
+		// Not a root folder itself, any instance can look up the disk it's on
+		return wanted, radarrClient, nil
+	}
+
+	switch len(folders) {
+	case 0:
+		return "", nil, fmt.Errorf("radarr has no root folders")
+	case 1:
+		return folders[0], clientOf[folders[0]], nil
+	}
+	return "", nil, fmt.Errorf("radarr has %d root folders, choose one with --root-folder or target.root_folder: %s",
+		len(folders), strings.Join(folders, ", "))
+}
+
+// parseSize parses a size such as "500GB", "1.5T" or "750000000" into bytes.
+// Units are binary, matching how sizes are displayed.
+func parseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	multiplier := int64(1)
+	if value != "" {
+		switch value[len(value)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			value = value[:len(value)-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 500GB or 1.5TB)", s)
+	}
+	return int64(number * float64(multiplier)), nil
+}
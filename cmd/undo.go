@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/s0up4200/arrbiter/journal"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+var (
+	undoSearch   bool
+	undoInstance string
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo <movie>",
+	Short: "Re-add a movie that arrbiter deleted",
+	Long: `Look up a deletion in the history journal and add the movie back to Radarr
+with the quality profile, root folder, tags and monitored state it had before.
+Any import exclusion arrbiter created when deleting it is removed.
+
+The movie can be given as a TMDB ID or as a title. Deleted files are not
+brought back; use --search to have Radarr grab the movie again.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: initializeApp,
+	RunE:    runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolVar(&undoSearch, "search", false, "search for the movie after re-adding it")
+	undoCmd.Flags().StringVar(&undoInstance, "instance", "", "only undo deletions from this Radarr instance")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	if err := textOutputOnly(cmd); err != nil {
+		return err
+	}
+
+	records, err := openJournal().Read(journal.Query{})
+	if err != nil {
+		return err
+	}
+
+	// Records from before instances were tracked belong to the primary instance
+	for i := range records {
+		if records[i].Instance == "" {
+			records[i].Instance = radarrInstances[0].Name
+		}
+	}
+	if undoInstance != "" {
+		records = slices.DeleteFunc(records, func(record journal.Record) bool {
+			return record.Instance != undoInstance
+		})
+	}
+
+	record, err := findUndoableDeletion(records, args[0])
+	if err != nil {
+		return err
+	}
+	if record.Radarr == nil {
+		return fmt.Errorf("journal entry for %s was recorded without Radarr settings and can't be undone", record.Title)
+	}
+
+	instance, err := instanceByName(record.Instance)
+	if err != nil {
+		return fmt.Errorf("can't undo deletion of %s: %w", record.Title, err)
+	}
+
+	if cfg.Safety.DryRun {
+		fmt.Printf("[DRY RUN] Would re-add %s (%d) to %s\n", record.Title, record.Year, record.Radarr.RootFolder)
+		return nil
+	}
+
+	// A restored movie starts over rather than going straight back to leaving soon
+	opts := radarr.UndoOptions{Search: undoSearch}
+	if cfg.Staging.Tag != "" {
+		opts.DropTags = append(opts.DropTags, cfg.Staging.Tag)
+	}
+
+	movie, err := instance.Operations.UndoDeletion(context.Background(), record, opts)
+	if movie == nil && err != nil {
+		return fmt.Errorf("failed to undo deletion of %s: %w", record.Title, err)
+	}
+
+	fmt.Printf("✓ Re-added %s (%d) to %s\n", record.Title, record.Year, record.Radarr.RootFolder)
+	return err
+}
+
+// findUndoableDeletion finds the deletion of a movie by TMDB ID or title that
+// hasn't been undone yet
+func findUndoableDeletion(records []journal.Record, query string) (journal.Record, error) {
+	// The latest delete or undo for each movie in each instance decides whether it can be undone
+	type key struct {
+		instance string
+		tmdbID   int64
+	}
+	latest := make(map[key]journal.Record)
+	var order []key
+	for _, record := range records {
+		if record.Outcome != journal.OutcomeSuccess {
+			continue
+		}
+		if record.Action != journal.ActionDelete && record.Action != journal.ActionUndo {
+			continue
+		}
+		k := key{record.Instance, record.TMDBID}
+		if _, seen := latest[k]; !seen {
+			order = append(order, k)
+		}
+		latest[k] = record
+	}
+
+	var deletions []journal.Record
+	for _, k := range order {
+		if record := latest[k]; record.Action == journal.ActionDelete {
+			deletions = append(deletions, record)
+		}
+	}
+
+	if tmdbID, err := strconv.ParseInt(query, 10, 64); err == nil {
+		var undone *journal.Record
+		var matches []journal.Record
+		for _, k := range order {
+			if k.tmdbID != tmdbID {
+				continue
+			}
+			record := latest[k]
+			if record.Action == journal.ActionUndo {
+				undone = &record
+				continue
+			}
+			matches = append(matches, record)
+		}
+		switch {
+		case len(matches) == 1:
+			return matches[0], nil
+		case len(matches) > 1:
+			var instances []string
+			for _, record := range matches {
+				instances = append(instances, record.Instance)
+			}
+			return journal.Record{}, fmt.Errorf("%s was deleted from several Radarr instances, choose one with --instance: %s",
+				matches[0].Title, strings.Join(instances, ", "))
+		case undone != nil:
+			return journal.Record{}, fmt.Errorf("deletion of %s was already undone", undone.Title)
+		}
+	}
+
+	var partial []journal.Record
+	for _, record := range deletions {
+		if strings.EqualFold(record.Title, query) {
+			return record, nil
+		}
+		if strings.Contains(strings.ToLower(record.Title), strings.ToLower(query)) {
+			partial = append(partial, record)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return journal.Record{}, fmt.Errorf("no deleted movie found matching '%s'", query)
+	case 1:
+		return partial[0], nil
+	}
+
+	var candidates []string
+	for _, record := range partial {
+		candidates = append(candidates, fmt.Sprintf("  - %s (%d) [TMDB %d]", record.Title, record.Year, record.TMDBID))
+	}
+	return journal.Record{}, fmt.Errorf("'%s' matches %d deleted movies, use the TMDB ID instead:\n%s",
+		query, len(partial), strings.Join(candidates, "\n"))
+}
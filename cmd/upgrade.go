@@ -89,39 +89,13 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := printFormatted(formatter.FormatUpgradeCandidates(filteredResults)); err != nil {
+		return err
+	}
 	if len(filteredResults) == 0 {
-		fmt.Println("✓ All movies have the required custom formats!")
 		return nil
 	}
 
-	// Display the list of movies
-	movieText := "movie"
-	if len(filteredResults) != 1 {
-		movieText = "movies"
-	}
-	fmt.Printf("Found %d %s missing custom formats:\n\n", len(filteredResults), movieText)
-
-	fmt.Println(strings.Repeat("━", 85))
-	fmt.Printf("%-4s %-50s %-15s %s\n", "#", "MOVIE", "YEAR", "CURRENT FORMATS")
-	fmt.Println(strings.Repeat("━", 85))
-
-	for i, result := range filteredResults {
-		// Build current custom formats string
-		currentFormats := "None"
-		if len(result.CurrentFormats) > 0 {
-			currentFormats = strings.Join(result.CurrentFormats, ", ")
-		}
-
-		// Truncate title if too long
-		title := result.Movie.Title
-		if len(title) > 48 {
-			title = title[:45] + "..."
-		}
-
-		fmt.Printf("%-4d %-50s %-15d %s\n", i+1, title, result.Movie.Year, currentFormats)
-	}
-	fmt.Println(strings.Repeat("━", 85))
-
 	// Determine how many movies to upgrade
 	var upgradeCount int
 	var selectedResults []radarr.UpgradeResult
@@ -133,10 +107,10 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		if upgradeCount != 1 {
 			movieText = "movies"
 		}
-		fmt.Printf("\n[UNATTENDED MODE] Upgrading %d %s\n", upgradeCount, movieText)
+		fmt.Fprintf(console(), "\n[UNATTENDED MODE] Upgrading %d %s\n", upgradeCount, movieText)
 	} else {
 		// Interactive mode
-		fmt.Printf("\nEnter movie numbers to upgrade (comma-separated, e.g. 1,3,5) or 'all' for all [Enter to cancel]: ")
+		fmt.Fprintf(console(), "\nEnter movie numbers to upgrade (comma-separated, e.g. 1,3,5) or 'all' for all [Enter to cancel]: ")
 
 		scanner := bufio.NewScanner(os.Stdin)
 		if !scanner.Scan() {
@@ -144,7 +118,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to read input: %w", err)
 			}
 			// No input (Ctrl+D or similar)
-			fmt.Println("No movies selected for upgrade.")
+			fmt.Fprintln(console(), "No movies selected for upgrade.")
 			return nil
 		}
 		input := scanner.Text()
@@ -152,7 +126,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		input = strings.TrimSpace(input)
 
 		if input == "" {
-			fmt.Println("No movies selected for upgrade.")
+			fmt.Fprintln(console(), "No movies selected for upgrade.")
 			return nil
 		}
 
@@ -192,7 +166,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 			}
 
 			if len(selectedIndices) == 0 {
-				fmt.Println("No valid movies selected for upgrade.")
+				fmt.Fprintln(console(), "No valid movies selected for upgrade.")
 				return nil
 			}
 		}
@@ -227,94 +201,49 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	}
 
 	// Trigger upgrade searches
-	movieText = "movie"
+	movieText := "movie"
 	if len(selectedResults) != 1 {
 		movieText = "movies"
 	}
-	fmt.Printf("\nTriggering upgrade searches for %d %s...\n", len(selectedResults), movieText)
+	fmt.Fprintf(console(), "\nTriggering upgrade searches for %d %s...\n", len(selectedResults), movieText)
 
 	if !dryRun {
-		var successCount int
-		var monitoringFailures int
-		var searchFailures int
-		var movieIDs []int64
-
-		// Enable monitoring if needed
-		if shouldMonitor {
-			for _, result := range selectedResults {
-				if result.NeedsMonitoring {
-					fmt.Printf("→ Enabling monitoring for %s (%d)... ", result.Movie.Title, result.Movie.Year)
-					err := operations.MonitorMovie(ctx, result.Movie.ID)
-					if err != nil {
-						logger.Error().Err(err).Str("movie", result.Movie.Title).Msg("Failed to enable monitoring")
-						fmt.Printf("✗ Failed: %v\n", err)
-						monitoringFailures++
-					} else {
-						fmt.Printf("✓ Enabled\n")
-					}
-				}
-			}
-		}
-
-		// Collect movie IDs for batch search
-		for _, result := range selectedResults {
-			movieIDs = append(movieIDs, result.Movie.ID)
-		}
-
-		// Trigger searches in batches
-		batchSize := 10
-		for i := 0; i < len(movieIDs); i += batchSize {
-			end := min(i+batchSize, len(movieIDs))
-
-			batch := movieIDs[i:end]
-			fmt.Printf("→ Searching batch of %d movies... ", len(batch))
-
-			err := operations.TriggerUpgradeSearch(ctx, batch)
-			if err != nil {
-				logger.Error().Err(err).Msg("Failed to trigger upgrade search")
-				fmt.Printf("✗ Failed: %v\n", err)
-				searchFailures += len(batch)
-			} else {
-				fmt.Printf("✓ Search triggered\n")
-				successCount += len(batch)
-			}
-
-			// Add a small delay between batches
-			if end < len(movieIDs) {
-				time.Sleep(2 * time.Second)
-			}
+		// Searches go out whether or not Radarr considers the movie released
+		summary, err := operations.ProcessUpgrades(ctx, selectedResults, radarr.UpgradeOptions{Monitor: shouldMonitor})
+		if err != nil {
+			return err
 		}
 
 		// Summary
 		movieText = "movie"
-		if successCount != 1 {
+		if summary.Searched != 1 {
 			movieText = "movies"
 		}
-		fmt.Printf("\n✓ Successfully triggered searches for %d %s\n", successCount, movieText)
+		fmt.Fprintf(console(), "\n✓ Successfully triggered searches for %d %s\n", summary.Searched, movieText)
 
-		if searchFailures > 0 {
+		if summary.SearchFailed > 0 {
 			movieText = "movie"
-			if searchFailures != 1 {
+			if summary.SearchFailed != 1 {
 				movieText = "movies"
 			}
-			fmt.Printf("✗ Failed to trigger searches for %d %s\n", searchFailures, movieText)
+			fmt.Fprintf(console(), "✗ Failed to trigger searches for %d %s\n", summary.SearchFailed, movieText)
 		}
 
-		if monitoringFailures > 0 {
+		if summary.MonitorFailed > 0 {
 			movieText = "movie"
-			if monitoringFailures != 1 {
+			if summary.MonitorFailed != 1 {
 				movieText = "movies"
 			}
-			fmt.Printf("✗ Failed to enable monitoring for %d %s\n", monitoringFailures, movieText)
+			fmt.Fprintf(console(), "✗ Failed to enable monitoring for %d %s\n", summary.MonitorFailed, movieText)
 		}
 	} else {
-		fmt.Println("[DRY RUN] Would trigger upgrade searches for:")
+		fmt.Fprintln(console(), "[DRY RUN] Would trigger upgrade searches for:")
 		for _, result := range selectedResults {
-			fmt.Printf("  - %s (%d)", result.Movie.Title, result.Movie.Year)
+			fmt.Fprintf(console(), "  - %s (%d)", result.Movie.Title, result.Movie.Year)
 			if shouldMonitor && result.NeedsMonitoring {
-				fmt.Printf(" [would enable monitoring]")
+				fmt.Fprintf(console(), " [would enable monitoring]")
 			}
-			fmt.Println()
+			fmt.Fprintln(console())
 		}
 	}
 
@@ -3,9 +3,16 @@ package config
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
@@ -49,10 +56,15 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	// Older configs set the watch threshold in the tautulli section
+	if !v.IsSet("watch.min_percent") {
+		cfg.Watch.MinPercent = cfg.Tautulli.MinWatchPercent
+	}
+
 	// Validate configuration
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -63,8 +75,10 @@ func Load(configPath string) (*Config, error) {
 
 // setDefaults sets default configuration values
 func setDefaults(v *viper.Viper) {
-	// Radarr defaults
-	v.SetDefault("radarr.url", "http://localhost:7878")
+	// Local state lives next to the config by default
+	if home, err := os.UserHomeDir(); err == nil {
+		v.SetDefault("data_dir", filepath.Join(home, ".config", "arrbiter"))
+	}
 
 	// Tautulli defaults
 	v.SetDefault("tautulli.min_watch_percent", 85.0)
@@ -82,16 +96,269 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("upgrade.custom_formats", []string{})
 	v.SetDefault("upgrade.match_mode", "all")
 	v.SetDefault("upgrade.auto_monitor", true)
+
+	// Quarantine defaults
+	v.SetDefault("quarantine.enabled", false)
+	v.SetDefault("quarantine.retention_days", 30)
+
+	// Staging defaults
+	v.SetDefault("staging.enabled", false)
+	v.SetDefault("staging.tag", "leaving-soon")
+	v.SetDefault("staging.grace_days", 7)
+
+	// Target defaults: unwatched movies first, then the longest unwatched, then the oldest
+	v.SetDefault("target.sort", []string{"Watched", "LastWatched", "Added"})
+
+	// Review defaults
+	v.SetDefault("review.protect_tag", "keep")
 }
 
-// validate checks if the configuration is valid
-func validate(cfg *Config) error {
-	if cfg.Radarr.URL == "" {
+// decodeHook returns viper's default decode hooks plus support for filter definitions
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		filterDefinitionHook,
+		radarrInstancesHook,
+	)
+}
+
+// filterDefinitionHook accepts either a bare expression string or a full object
+// for each filter, filling in defaults for any fields that were left out
+func filterDefinitionHook(from, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(FilterDefinition{}) {
+		return data, nil
+	}
+
+	def := map[string]any{
+		"action":       string(ActionDelete),
+		"delete_files": true,
+		"enabled":      true,
+	}
+
+	switch v := data.(type) {
+	case string:
+		def["expression"] = v
+	case map[string]any:
+		maps.Copy(def, v)
+	default:
+		return data, nil
+	}
+
+	return def, nil
+}
+
+// radarrInstancesHook accepts either a single Radarr object, which becomes the
+// instance named "default", or a list of named instances
+func radarrInstancesHook(from, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(RadarrInstances{}) {
+		return data, nil
+	}
+
+	v, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+
+	instance := map[string]any{
+		"name": DefaultRadarrInstance,
+		"url":  "http://localhost:7878",
+	}
+	maps.Copy(instance, v)
+	return []any{instance}, nil
+}
+
+// validateRadarrInstances checks every Radarr instance has a unique name and connection details
+func validateRadarrInstances(instances RadarrInstances) error {
+	if len(instances) == 0 {
 		return fmt.Errorf("radarr.url is required")
 	}
 
-	if cfg.Radarr.APIKey == "" || cfg.Radarr.APIKey == "your-api-key-here" {
-		return fmt.Errorf("radarr.api_key must be set to a valid API key")
+	seen := make(map[string]bool, len(instances))
+	for i, instance := range instances {
+		key := "radarr"
+		if len(instances) > 1 {
+			if instance.Name == "" {
+				return fmt.Errorf("radarr[%d].name is required with more than one instance", i)
+			}
+			key = "radarr." + instance.Name
+		}
+		if seen[instance.Name] {
+			return fmt.Errorf("duplicate Radarr instance name: %s", instance.Name)
+		}
+		seen[instance.Name] = true
+
+		if instance.URL == "" {
+			return fmt.Errorf("%s.url is required", key)
+		}
+		if instance.APIKey == "" || instance.APIKey == "your-api-key-here" {
+			return fmt.Errorf("%s.api_key must be set to a valid API key", key)
+		}
+	}
+
+	return nil
+}
+
+// validateFilterDefinition checks that a filter's action has what it needs
+func validateFilterDefinition(name string, def FilterDefinition) error {
+	if def.Scope != "" {
+		return fmt.Errorf("filter.%s.scope is only supported in series_filter", name)
+	}
+	return validateFilterIn("filter", name, def)
+}
+
+// validateFilterIn validates a filter definition from the given config section
+func validateFilterIn(section, name string, def FilterDefinition) error {
+	key := section + "." + name
+
+	if def.Expression == "" {
+		return fmt.Errorf("%s.expression is required", key)
+	}
+
+	switch def.Action {
+	case ActionDelete, ActionUnmonitor, ActionNotify:
+	case ActionTag:
+		if def.Tag == "" {
+			return fmt.Errorf("%s.tag is required for the tag action", key)
+		}
+	case ActionChangeQualityProfile:
+		if def.QualityProfile == "" {
+			return fmt.Errorf("%s.quality_profile is required for the change_quality_profile action", key)
+		}
+	default:
+		return fmt.Errorf("invalid %s.action: %s (must be 'delete', 'unmonitor', 'tag', 'change_quality_profile' or 'notify')", key, def.Action)
+	}
+
+	if def.MaxPerRun < 0 {
+		return fmt.Errorf("%s.max_per_run must not be negative", key)
+	}
+	if err := validatePercent(key+".min_watch_percent", def.MinWatchPercent); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSeriesFilterDefinition checks a series filter, which supports fewer
+// options than a movie filter and can work on single seasons
+func validateSeriesFilterDefinition(name string, def FilterDefinition) error {
+	if err := validateFilterIn("series_filter", name, def); err != nil {
+		return err
+	}
+
+	if def.Score != "" {
+		return fmt.Errorf("series_filter.%s.score is not supported for series", name)
+	}
+	if def.CompleteCollections {
+		return fmt.Errorf("series_filter.%s.complete_collections is not supported for series", name)
+	}
+	if def.Instance != "" {
+		return fmt.Errorf("series_filter.%s.instance is only supported for movie filters", name)
+	}
+	if def.MinWatchPercent != 0 {
+		return fmt.Errorf("series_filter.%s.min_watch_percent is only supported for movie filters", name)
+	}
+
+	switch def.Scope {
+	case "", ScopeSeries:
+	case ScopeSeason:
+		switch def.Action {
+		case ActionTag, ActionChangeQualityProfile:
+			return fmt.Errorf("series_filter.%s.action %s needs scope 'series'", name, def.Action)
+		case ActionDelete:
+			if !def.DeleteFiles || def.AddImportExclusion {
+				return fmt.Errorf("series_filter.%s: deleting a season always deletes its files and never adds an import exclusion", name)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid series_filter.%s.scope: %s (must be 'series' or 'season')", name, def.Scope)
+	}
+
+	return nil
+}
+
+// groupHelpers are the filter helpers that take the name of a user group
+var groupHelpers = []string{"watchedByAnyOf", "watchedByAllOf", "requestedByGroup", "lastWatchedByAnyOf"}
+
+// validateGroupReferences checks that every group named in a filter, score or
+// protection rule is defined in users.groups. An unknown group has no members,
+// so a typo in `not watchedByAnyOf("famly")` would match every movie.
+func validateGroupReferences(cfg *Config) error {
+	expressions := make(map[string]string)
+	for name, def := range cfg.Filter {
+		expressions["filter."+name] = def.Expression
+		if def.Score != "" {
+			expressions["filter."+name+".score"] = def.Score
+		}
+	}
+	for name, def := range cfg.SeriesFilter {
+		expressions["series_filter."+name] = def.Expression
+	}
+	for name, rule := range cfg.Protect {
+		expressions["protect."+name] = rule
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(expressions)) {
+		for _, ref := range groupReferences(expressions[key]) {
+			if _, ok := cfg.Users.Groups[strings.ToLower(ref.group)]; !ok {
+				return fmt.Errorf("%s: %s(%q) names a user group that is not defined in users.groups", key, ref.helper, ref.group)
+			}
+		}
+	}
+	return nil
+}
+
+// groupReference is a group helper called with a constant group name
+type groupReference struct {
+	helper string
+	group  string
+}
+
+// groupReferences returns the constant group names passed to group helpers in
+// an expression. Expressions that don't parse are left for the filter compiler
+// to report.
+func groupReferences(expression string) []groupReference {
+	tree, err := parser.Parse(expression)
+	if err != nil {
+		return nil
+	}
+
+	var finder groupFinder
+	ast.Walk(&tree.Node, &finder)
+	return finder.refs
+}
+
+// groupFinder collects group helper calls while walking an expression
+type groupFinder struct {
+	refs []groupReference
+}
+
+func (f *groupFinder) Visit(node *ast.Node) {
+	call, ok := (*node).(*ast.CallNode)
+	if !ok || len(call.Arguments) == 0 {
+		return
+	}
+	callee, ok := call.Callee.(*ast.IdentifierNode)
+	if !ok || !slices.Contains(groupHelpers, callee.Value) {
+		return
+	}
+	if group, ok := call.Arguments[0].(*ast.StringNode); ok {
+		f.refs = append(f.refs, groupReference{helper: callee.Value, group: group.Value})
+	}
+}
+
+// validatePercent checks that a watch threshold is a percentage
+func validatePercent(key string, percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("%s must be between 0 and 100", key)
+	}
+	return nil
+}
+
+// validate checks if the configuration is valid
+func validate(cfg *Config) error {
+	if err := validateRadarrInstances(cfg.Radarr); err != nil {
+		return err
 	}
 
 	// Validate logging level
@@ -105,6 +372,67 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("invalid logging level: %s", cfg.Logging.Level)
 	}
 
+	// Validate filter definitions
+	for name, def := range cfg.Filter {
+		if err := validateFilterDefinition(name, def); err != nil {
+			return err
+		}
+		if def.Instance != "" && !slices.Contains(cfg.Radarr.Names(), def.Instance) {
+			return fmt.Errorf("filter.%s.instance: unknown Radarr instance %s", name, def.Instance)
+		}
+	}
+
+	// Validate series filter definitions
+	for name, def := range cfg.SeriesFilter {
+		if err := validateSeriesFilterDefinition(name, def); err != nil {
+			return err
+		}
+	}
+	if len(cfg.SeriesFilter) > 0 && cfg.Sonarr.URL != "" && cfg.Sonarr.APIKey == "" {
+		return fmt.Errorf("sonarr.api_key is required when sonarr.url is set")
+	}
+
+	// Validate watch thresholds
+	if err := validatePercent("watch.min_percent", cfg.Watch.MinPercent); err != nil {
+		return err
+	}
+	for name, percent := range cfg.Watch.Users {
+		if err := validatePercent("watch.users."+name, percent); err != nil {
+			return err
+		}
+	}
+
+	// Validate user groups
+	for name, members := range cfg.Users.Groups {
+		if len(members) == 0 {
+			return fmt.Errorf("users.groups.%s must list at least one user", name)
+		}
+	}
+	if err := validateGroupReferences(cfg); err != nil {
+		return err
+	}
+
+	// Validate quarantine settings
+	if cfg.Quarantine.Enabled && cfg.Quarantine.Path == "" {
+		return fmt.Errorf("quarantine.path is required when quarantine is enabled")
+	}
+	if cfg.Quarantine.RetentionDays < 0 {
+		return fmt.Errorf("quarantine.retention_days must not be negative")
+	}
+
+	// Validate staging settings
+	if cfg.Staging.Enabled {
+		if cfg.Staging.Tag == "" {
+			return fmt.Errorf("staging.tag is required when staging is enabled")
+		}
+		if cfg.DataDir == "" {
+			return fmt.Errorf("data_dir is required when staging is enabled")
+		}
+	}
+	if cfg.Staging.GraceDays < 0 {
+		return fmt.Errorf("staging.grace_days must not be negative")
+	}
+
 	// Validate upgrade match mode
 	if cfg.Upgrade.MatchMode != "" && cfg.Upgrade.MatchMode != "any" && cfg.Upgrade.MatchMode != "all" {
 		return fmt.Errorf("invalid upgrade.match_mode: %s (must be 'any' or 'all')", cfg.Upgrade.MatchMode)
@@ -163,6 +491,16 @@ tautulli:
   api_key: your-tautulli-api-key
   min_watch_percent: 85  # Consider watched if > 85% viewed
 
+# Plex watch state read directly from the server, instead of Tautulli
+# plex:
+#   url: http://localhost:32400
+#   token: your-plex-token
+
+# Jellyfin or Emby watch history, instead of or alongside Tautulli
+# jellyfin:
+#   url: http://localhost:8096
+#   api_key: your-jellyfin-api-key
+
 overseerr:
   url: http://localhost:5055
   api_key: your-overseerr-api-key
@@ -178,6 +516,15 @@ filter:
   unwatched_requests: notWatchedByRequester() and Added < daysAgo(30)
   space_cleanup: not Watched and Added < monthsAgo(3) and not hasTag("keep")
   poor_quality: imdbRating() < 5.5 and notRequested() and Added < daysAgo(30)
+  # Filters can also be objects with an action other than delete
+  stale_requests:
+    expression: notWatchedByRequester() and Added < daysAgo(60)
+    action: unmonitor
+    description: Stop upgrading requests nobody watched
+
+protect:
+  # Movies matching any of these are never touched, whatever the filters say
+  favourites: hasTag("keep")
 
 safety:
   dry_run: true
@@ -196,6 +543,29 @@ upgrade:
   match_mode: all
   # Automatically monitor upgraded movies
   auto_monitor: true
+
+quarantine:
+  # Move deleted movies here instead of removing them; purge removes them for good
+  enabled: false
+  path: /data/quarantine
+  retention_days: 30
+
+staging:
+  # Tag matches as leaving soon and only delete them after the grace period
+  enabled: false
+  tag: leaving-soon
+  grace_days: 7
+
+target:
+  # Order in which 'delete --reclaim' and 'delete --free-percent' pick movies
+  sort:
+    - Watched
+    - LastWatched
+    - Added
+
+review:
+  # Tag added by 'p' in 'delete --review', matched by the favourites rule above
+  protect_tag: keep
 `
 		return os.WriteFile(configPath, []byte(defaultConfig), 0644)
 	}
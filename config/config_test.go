@@ -1,7 +1,10 @@
 package config
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestValidateUpgradeMatchMode(t *testing.T) {
@@ -40,10 +43,11 @@ func TestValidateUpgradeMatchMode(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &Config{
-				Radarr: RadarrConfig{
+				Radarr: RadarrInstances{{
+					Name:   DefaultRadarrInstance,
 					URL:    "http://localhost:7878",
 					APIKey: "valid-api-key",
-				},
+				}},
 				Logging: LoggingConfig{
 					Level: "info",
 				},
@@ -64,4 +68,289 @@ func TestValidateUpgradeMatchMode(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestFilterDefinitionDecoding(t *testing.T) {
+	yaml := `
+filter:
+  old_unwatched: not Watched and Added < monthsAgo(6)
+  stale_requests:
+    expression: notWatchedByRequester()
+    action: tag
+    tag: leaving-soon
+    max_per_run: 5
+  keep_files:
+    expression: hasTag("remove")
+    delete_files: false
+    add_import_exclusion: true
+  disabled:
+    expression: Year < 1990
+    enabled: false
+`
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want FilterDefinition
+	}{
+		{
+			name: "old_unwatched",
+			want: FilterDefinition{
+				Expression:  "not Watched and Added < monthsAgo(6)",
+				Action:      ActionDelete,
+				DeleteFiles: true,
+				Enabled:     true,
+			},
+		},
+		{
+			name: "stale_requests",
+			want: FilterDefinition{
+				Expression:  "notWatchedByRequester()",
+				Action:      ActionTag,
+				Tag:         "leaving-soon",
+				DeleteFiles: true,
+				Enabled:     true,
+				MaxPerRun:   5,
+			},
+		},
+		{
+			name: "keep_files",
+			want: FilterDefinition{
+				Expression:         `hasTag("remove")`,
+				Action:             ActionDelete,
+				AddImportExclusion: true,
+				Enabled:            true,
+			},
+		},
+		{
+			name: "disabled",
+			want: FilterDefinition{
+				Expression:  "Year < 1990",
+				Action:      ActionDelete,
+				DeleteFiles: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cfg.Filter[tt.name]
+			if !ok {
+				t.Fatalf("filter %s not decoded", tt.name)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if err := validateFilterDefinition(tt.name, got); err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+
+	if enabled := cfg.Filter.Enabled(); len(enabled) != 3 {
+		t.Errorf("expected 3 enabled filters, got %d", len(enabled))
+	}
+}
+
+func TestValidateFilterDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     FilterDefinition
+		wantErr bool
+	}{
+		{"missing expression", FilterDefinition{Action: ActionDelete}, true},
+		{"unknown action", FilterDefinition{Expression: "Watched", Action: "archive"}, true},
+		{"tag without tag name", FilterDefinition{Expression: "Watched", Action: ActionTag}, true},
+		{"profile without name", FilterDefinition{Expression: "Watched", Action: ActionChangeQualityProfile}, true},
+		{"negative max per run", FilterDefinition{Expression: "Watched", Action: ActionUnmonitor, MaxPerRun: -1}, true},
+		{"notify", FilterDefinition{Expression: "Watched", Action: ActionNotify}, false},
+		{"quality profile", FilterDefinition{Expression: "Watched", Action: ActionChangeQualityProfile, QualityProfile: "HD-720p"}, false},
+		{"watch threshold", FilterDefinition{Expression: "Watched", Action: ActionNotify, MinWatchPercent: 50}, false},
+		{"watch threshold over 100", FilterDefinition{Expression: "Watched", Action: ActionNotify, MinWatchPercent: 150}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFilterDefinition("test", tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFilterDefinition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSeriesFilterDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     FilterDefinition
+		wantErr bool
+	}{
+		{"series delete", FilterDefinition{Expression: "Watched", Action: ActionDelete, DeleteFiles: true}, false},
+		{"season delete", FilterDefinition{Expression: "SeasonWatched", Action: ActionDelete, DeleteFiles: true, Scope: ScopeSeason}, false},
+		{"season unmonitor", FilterDefinition{Expression: "SeasonWatched", Action: ActionUnmonitor, Scope: ScopeSeason}, false},
+		{"season delete keeping files", FilterDefinition{Expression: "SeasonWatched", Action: ActionDelete, Scope: ScopeSeason}, true},
+		{"season tag", FilterDefinition{Expression: "SeasonWatched", Action: ActionTag, Tag: "old", Scope: ScopeSeason}, true},
+		{"unknown scope", FilterDefinition{Expression: "Watched", Action: ActionNotify, Scope: "episode"}, true},
+		{"score", FilterDefinition{Expression: "Watched", Action: ActionNotify, Score: "SizeGB"}, true},
+		{"watch threshold", FilterDefinition{Expression: "Watched", Action: ActionNotify, MinWatchPercent: 50}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSeriesFilterDefinition("test", tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSeriesFilterDefinition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := validateFilterDefinition("test", FilterDefinition{Expression: "Watched", Action: ActionNotify, Scope: ScopeSeason}); err == nil {
+		t.Error("expected scope to be rejected for movie filters")
+	}
+}
+
+func TestValidateGroupReferences(t *testing.T) {
+	users := UsersConfig{Groups: map[string][]string{"family": {"alice", "bob"}}}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"defined group", Config{Users: users, Filter: FilterConfig{
+			"shared": {Expression: `not watchedByAnyOf("family") and requestedByGroup("Family")`},
+		}}, ""},
+		{"typo", Config{Users: users, Filter: FilterConfig{
+			"shared": {Expression: `not watchedByAnyOf("famly")`},
+		}}, `filter.shared: watchedByAnyOf("famly")`},
+		{"no groups configured", Config{Filter: FilterConfig{
+			"shared": {Expression: `watchedByAllOf("family")`},
+		}}, `filter.shared: watchedByAllOf("family")`},
+		{"score", Config{Users: users, Filter: FilterConfig{
+			"shared": {Expression: "Watched", Score: `lastWatchedByAnyOf("kids") < daysAgo(30) ? 1 : 0`},
+		}}, `filter.shared.score: lastWatchedByAnyOf("kids")`},
+		{"series filter", Config{Users: users, SeriesFilter: FilterConfig{
+			"shows": {Expression: `watchedByAllOf("roommates")`},
+		}}, `series_filter.shows: watchedByAllOf("roommates")`},
+		{"protection rule", Config{Users: users, Protect: ProtectConfig{
+			"household": `requestedByGroup("house")`,
+		}}, `protect.household: requestedByGroup("house")`},
+		// Syntax errors are reported by the filter compiler
+		{"invalid expression", Config{Users: users, Filter: FilterConfig{
+			"broken": {Expression: `watchedByAnyOf("famly"`},
+		}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGroupReferences(&tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateGroupReferences() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateGroupReferences() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRadarrInstancesDecoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    RadarrInstances
+		wantErr bool
+	}{
+		{
+			name: "single object",
+			yaml: `
+radarr:
+  api_key: abc
+`,
+			want: RadarrInstances{{Name: DefaultRadarrInstance, URL: "http://localhost:7878", APIKey: "abc"}},
+		},
+		{
+			name: "named list",
+			yaml: `
+radarr:
+  - name: hd
+    url: http://radarr:7878
+    api_key: abc
+  - name: 4k
+    url: http://radarr4k:7878
+    api_key: def
+`,
+			want: RadarrInstances{
+				{Name: "hd", URL: "http://radarr:7878", APIKey: "abc"},
+				{Name: "4k", URL: "http://radarr4k:7878", APIKey: "def"},
+			},
+		},
+		{
+			name: "duplicate names",
+			yaml: `
+radarr:
+  - name: hd
+    url: http://radarr:7878
+    api_key: abc
+  - name: hd
+    url: http://radarr4k:7878
+    api_key: def
+`,
+			wantErr: true,
+		},
+		{
+			name: "missing name",
+			yaml: `
+radarr:
+  - url: http://radarr:7878
+    api_key: abc
+  - name: 4k
+    url: http://radarr4k:7878
+    api_key: def
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(tt.yaml)); err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+
+			var cfg Config
+			if err := v.Unmarshal(&cfg, viper.DecodeHook(decodeHook())); err != nil {
+				t.Fatalf("failed to unmarshal config: %v", err)
+			}
+
+			err := validateRadarrInstances(cfg.Radarr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRadarrInstances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(cfg.Radarr) != len(tt.want) {
+				t.Fatalf("got %d instances, want %d", len(cfg.Radarr), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if cfg.Radarr[i] != want {
+					t.Errorf("instance %d = %+v, want %+v", i, cfg.Radarr[i], want)
+				}
+			}
+		})
+	}
+}
@@ -2,24 +2,126 @@ package config
 
 // Config represents the complete configuration structure
 type Config struct {
-	Radarr      RadarrConfig      `mapstructure:"radarr"`
-	Tautulli    TautulliConfig    `mapstructure:"tautulli"`
-	Overseerr   OverseerrConfig   `mapstructure:"overseerr"`
-	QBittorrent QBittorrentConfig `mapstructure:"qbittorrent"`
-	Filter      FilterConfig      `mapstructure:"filter"`
-	Safety      SafetyConfig      `mapstructure:"safety"`
-	Logging     LoggingConfig     `mapstructure:"logging"`
-	Upgrade     UpgradeConfig     `mapstructure:"upgrade"`
+	DataDir      string            `mapstructure:"data_dir"`
+	Radarr       RadarrInstances   `mapstructure:"radarr"`
+	Sonarr       SonarrConfig      `mapstructure:"sonarr"`
+	Tautulli     TautulliConfig    `mapstructure:"tautulli"`
+	Plex         PlexConfig        `mapstructure:"plex"`
+	Jellyfin     JellyfinConfig    `mapstructure:"jellyfin"`
+	Emby         JellyfinConfig    `mapstructure:"emby"`
+	Watch        WatchConfig       `mapstructure:"watch"`
+	Overseerr    OverseerrConfig   `mapstructure:"overseerr"`
+	Users        UsersConfig       `mapstructure:"users"`
+	QBittorrent  QBittorrentConfig `mapstructure:"qbittorrent"`
+	Filter       FilterConfig      `mapstructure:"filter"`
+	SeriesFilter FilterConfig      `mapstructure:"series_filter"`
+	Protect      ProtectConfig     `mapstructure:"protect"`
+	Safety       SafetyConfig      `mapstructure:"safety"`
+	Logging      LoggingConfig     `mapstructure:"logging"`
+	Upgrade      UpgradeConfig     `mapstructure:"upgrade"`
+	Quarantine   QuarantineConfig  `mapstructure:"quarantine"`
+	Staging      StagingConfig     `mapstructure:"staging"`
+	Target       TargetConfig      `mapstructure:"target"`
+	Review       ReviewConfig      `mapstructure:"review"`
 }
 
+// DefaultRadarrInstance names the Radarr instance when radarr is a single object
+const DefaultRadarrInstance = "default"
+
 // RadarrConfig holds Radarr API connection details
 type RadarrConfig struct {
+	Name   string `mapstructure:"name"`
+	URL    string `mapstructure:"url"`
+	APIKey string `mapstructure:"api_key"`
+}
+
+// RadarrInstances lists the Radarr instances to work on. A single object in the
+// config is shorthand for one instance named "default". The first instance is
+// the primary one, used by commands that only work on one instance.
+type RadarrInstances []RadarrConfig
+
+// Primary returns the first configured instance
+func (r RadarrInstances) Primary() RadarrConfig {
+	if len(r) == 0 {
+		return RadarrConfig{}
+	}
+	return r[0]
+}
+
+// Names returns the instance names in config order
+func (r RadarrInstances) Names() []string {
+	names := make([]string, 0, len(r))
+	for _, instance := range r {
+		names = append(names, instance.Name)
+	}
+	return names
+}
+
+// SonarrConfig holds Sonarr API connection details. Series filters only run when URL is set.
+type SonarrConfig struct {
 	URL    string `mapstructure:"url"`
 	APIKey string `mapstructure:"api_key"`
 }
 
-// FilterConfig contains filter definitions
-type FilterConfig map[string]string
+// FilterConfig contains filter definitions keyed by name
+type FilterConfig map[string]FilterDefinition
+
+// Enabled returns only the filters that are switched on
+func (f FilterConfig) Enabled() FilterConfig {
+	enabled := make(FilterConfig, len(f))
+	for name, def := range f {
+		if def.Enabled {
+			enabled[name] = def
+		}
+	}
+	return enabled
+}
+
+// ProtectConfig contains protection rules keyed by name. Movies matching any
+// rule are never acted on, regardless of which filters match them.
+type ProtectConfig map[string]string
+
+// FilterAction is what happens to movies matched by a filter
+type FilterAction string
+
+const (
+	ActionDelete               FilterAction = "delete"
+	ActionUnmonitor            FilterAction = "unmonitor"
+	ActionTag                  FilterAction = "tag"
+	ActionChangeQualityProfile FilterAction = "change_quality_profile"
+	ActionNotify               FilterAction = "notify"
+)
+
+// FilterScope is what a series filter is evaluated against
+type FilterScope string
+
+const (
+	ScopeSeries FilterScope = "series"
+	ScopeSeason FilterScope = "season"
+)
+
+// FilterDefinition describes a single filter and what to do with its matches.
+// A plain string in the config is shorthand for a delete filter with that expression.
+type FilterDefinition struct {
+	Expression         string       `mapstructure:"expression"`
+	Action             FilterAction `mapstructure:"action"`
+	Tag                string       `mapstructure:"tag"`             // Tag to add for the tag action
+	QualityProfile     string       `mapstructure:"quality_profile"` // Profile name for change_quality_profile
+	DeleteFiles        bool         `mapstructure:"delete_files"`
+	AddImportExclusion bool         `mapstructure:"add_import_exclusion"`
+	Enabled            bool         `mapstructure:"enabled"`
+	MaxPerRun          int          `mapstructure:"max_per_run"` // 0 means unlimited
+	Description        string       `mapstructure:"description"`
+	Score              string       `mapstructure:"score"` // Numeric expression, higher means more deletable
+	// Only act on a movie in a TMDB collection once every collection member in the library matches
+	CompleteCollections bool `mapstructure:"complete_collections"`
+	// Only match movies in this Radarr instance, all instances when empty
+	Instance string `mapstructure:"instance"`
+	// Series filters only: match whole series (default) or each season on its own
+	Scope FilterScope `mapstructure:"scope"`
+	// Watch threshold for this filter's watch helpers, the watch section's when 0
+	MinWatchPercent float64 `mapstructure:"min_watch_percent"`
+}
 
 // SafetyConfig contains safety-related settings
 type SafetyConfig struct {
@@ -41,12 +143,42 @@ type TautulliConfig struct {
 	MinWatchPercent float64 `mapstructure:"min_watch_percent"`
 }
 
+// PlexConfig holds Plex Media Server connection details for reading watch
+// state directly. The token must be the server owner's.
+type PlexConfig struct {
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+}
+
+// JellyfinConfig holds Jellyfin or Emby API connection details
+type JellyfinConfig struct {
+	URL    string `mapstructure:"url"`
+	APIKey string `mapstructure:"api_key"`
+}
+
+// WatchConfig sets how much of a movie or episode must be played for it to
+// count as watched, for every watch history provider. MinPercent defaults to
+// tautulli.min_watch_percent; Users overrides it for individual users.
+type WatchConfig struct {
+	MinPercent float64            `mapstructure:"min_percent"`
+	Users      map[string]float64 `mapstructure:"users"`
+}
+
 // OverseerrConfig holds Overseerr API connection details
 type OverseerrConfig struct {
 	URL    string `mapstructure:"url"`
 	APIKey string `mapstructure:"api_key"`
 }
 
+// UsersConfig links the names one person has across services. Aliases maps a
+// user to the other names, usernames and emails they are known by, for
+// accounts that cannot be linked automatically through their Plex account.
+// Groups names sets of users, such as households, for the group helpers.
+type UsersConfig struct {
+	Aliases map[string][]string `mapstructure:"aliases"`
+	Groups  map[string][]string `mapstructure:"groups"`
+}
+
 // QBittorrentConfig holds qBittorrent API connection details
 type QBittorrentConfig struct {
 	URL      string `mapstructure:"url"`
@@ -60,3 +192,32 @@ type UpgradeConfig struct {
 	MatchMode     string   `mapstructure:"match_mode"`
 	AutoMonitor   bool     `mapstructure:"auto_monitor"`
 }
+
+// QuarantineConfig holds soft-delete settings. When enabled, delete actions move
+// movie folders into Path instead of removing them, and purge removes them later.
+type QuarantineConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Path          string `mapstructure:"path"`
+	RetentionDays int    `mapstructure:"retention_days"`
+}
+
+// StagingConfig holds "leaving soon" settings. When enabled, delete filters first
+// tag matching movies and only delete them once they have matched for GraceDays.
+type StagingConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Tag       string `mapstructure:"tag"`
+	GraceDays int    `mapstructure:"grace_days"`
+}
+
+// TargetConfig holds settings for deleting only until a disk-space target is met.
+// Sort expressions rank delete candidates; the first ones are deleted first.
+type TargetConfig struct {
+	Sort       []string `mapstructure:"sort"`
+	RootFolder string   `mapstructure:"root_folder"` // Root folder for free-space targets with several root folders
+}
+
+// ReviewConfig holds settings for reviewing delete candidates with delete --review.
+// Protecting a movie there tags it with ProtectTag, which a protection rule should match.
+type ReviewConfig struct {
+	ProtectTag string `mapstructure:"protect_tag"`
+}
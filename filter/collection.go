@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"time"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// collectionKey identifies a collection within one Radarr instance, so a 4K
+// copy of a movie doesn't count towards the collection in the HD instance
+type collectionKey struct {
+	instance string
+	tmdbID   int64
+}
+
+// collectionOf returns the key of the collection a movie belongs to
+func collectionOf(movie radarr.MovieInfo) collectionKey {
+	return collectionKey{instance: movie.Instance, tmdbID: movie.CollectionTMDBID}
+}
+
+// AnnotateCollections fills in the collection summary of every movie from all
+// movies in the same TMDB collection and Radarr instance, so expressions can look
+// past a single movie. Movies are updated in place; movies outside a collection are reset.
+func AnnotateCollections(movies []radarr.MovieInfo) {
+	type summary struct {
+		size         int
+		lastWatched  time.Time
+		anyRequested bool
+	}
+
+	collections := make(map[collectionKey]*summary)
+	for _, movie := range movies {
+		if movie.CollectionTMDBID == 0 {
+			continue
+		}
+		s, ok := collections[collectionOf(movie)]
+		if !ok {
+			s = &summary{}
+			collections[collectionOf(movie)] = s
+		}
+		s.size++
+		if movie.LastWatched.After(s.lastWatched) {
+			s.lastWatched = movie.LastWatched
+		}
+		if movie.IsRequested {
+			s.anyRequested = true
+		}
+	}
+
+	for i := range movies {
+		s, ok := collections[collectionOf(movies[i])]
+		if !ok {
+			s = &summary{}
+		}
+		movies[i].CollectionSize = s.size
+		movies[i].CollectionLastWatched = s.lastWatched
+		movies[i].CollectionAnyRequested = s.anyRequested
+	}
+}
+
+// CompleteCollections splits matches into movies that may be acted on and movies
+// held back because another movie from their collection in the library didn't
+// match. Movies outside a collection are always kept.
+func CompleteCollections(matches, library []radarr.MovieInfo) (kept, held []radarr.MovieInfo) {
+	matched := make(map[radarr.MovieKey]bool, len(matches))
+	for _, movie := range matches {
+		matched[movie.Key()] = true
+	}
+
+	incomplete := make(map[collectionKey]bool)
+	for _, movie := range library {
+		if movie.CollectionTMDBID != 0 && !matched[movie.Key()] {
+			incomplete[collectionOf(movie)] = true
+		}
+	}
+
+	for _, movie := range matches {
+		if incomplete[collectionOf(movie)] {
+			held = append(held, movie)
+		} else {
+			kept = append(kept, movie)
+		}
+	}
+	return kept, held
+}
+
+func createCollectionWatchedRecentlyFunc(lastWatched time.Time) func(int) bool {
+	return func(days int) bool {
+		return !lastWatched.IsZero() && lastWatched.After(time.Now().AddDate(0, 0, -days))
+	}
+}
+
+func createCollectionAnyRequestedFunc(anyRequested bool) func() bool {
+	return func() bool {
+		return anyRequested
+	}
+}
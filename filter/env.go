@@ -0,0 +1,301 @@
+package filter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// Env is the typed environment filter expressions are compiled and evaluated against.
+// Every identifier usable in an expression is a field of this struct, so unknown
+// names, wrong argument types and misspelled helpers are rejected at compile time.
+// Helper functions are func-typed fields renamed via the expr struct tag.
+type Env struct {
+	// Full movie record for fields not promoted below
+	Movie radarr.MovieInfo
+
+	// Direct movie properties for convenience
+	Title          string
+	Year           int
+	Tags           []string
+	TagNames       []string
+	Added          time.Time
+	MonitoredSince time.Time
+	FileImported   time.Time
+	Watched        bool
+	WatchCount     int
+	LastWatched    time.Time
+	WatchProgress  float64
+	HasFile        bool
+	Path           string
+	IMDBID         string
+	TMDBID         int64
+	Ratings        map[string]float64
+	Popularity     float64
+	UserWatchData  map[string]*radarr.UserWatchInfo
+
+	// Radarr metadata
+	Genres           []string
+	Studio           string
+	Certification    string
+	OriginalLanguage string
+	Runtime          int // Minutes
+	Collection       string
+	CollectionTMDBID int64
+	CollectionSize   int // Movies from the collection in the library, including this one
+	InCinemas        time.Time
+	DigitalRelease   time.Time
+	PhysicalRelease  time.Time
+	Monitored        bool
+	QualityProfile   string
+	RootFolder       string
+	Instance         string   // Radarr instance the movie is in
+	Instances        []string // Instances that have this movie with a file, including this one if it has a file
+
+	// File, quality and media info properties, zero when the movie has no file
+	SizeBytes         int64
+	SizeGB            float64
+	Quality           string // Quality name, e.g. "Bluray-2160p"
+	QualitySource     string // e.g. "bluray", "webdl"
+	Resolution        int    // Vertical resolution class: 2160, 1080, 720, 480
+	VideoCodec        string
+	VideoBitDepth     int
+	HDR               string // Dynamic range type, e.g. "HDR10", "DV HDR10"; empty for SDR
+	IsHDR             bool
+	AudioCodec        string
+	AudioChannels     float64
+	AudioLanguages    string
+	FileRuntime       int // Minutes, from the file's media info
+	ReleaseGroup      string
+	Edition           string
+	CustomFormats     []string
+	CustomFormatScore int
+
+	// Request properties
+	RequestedBy      string
+	RequestedByEmail string
+	RequestDate      time.Time
+	RequestStatus    string
+	ApprovedBy       string
+	IsAutoRequest    bool
+	IsRequested      bool
+
+	// Date helpers
+	DaysSinceFn func(time.Time) int    `expr:"daysSince"`
+	DaysAgoFn   func(int) time.Time    `expr:"daysAgo"`
+	MonthsAgoFn func(int) time.Time    `expr:"monthsAgo"`
+	YearsAgoFn  func(int) time.Time    `expr:"yearsAgo"`
+	ParseDateFn func(string) time.Time `expr:"parseDate"`
+	NowFn       func() time.Time       `expr:"now"`
+
+	// String helpers
+	ContainsFn   func(string, string) bool `expr:"contains"`
+	StartsWithFn func(string, string) bool `expr:"startsWith"`
+	EndsWithFn   func(string, string) bool `expr:"endsWith"`
+	LowerFn      func(string) string       `expr:"lower"`
+	UpperFn      func(string) string       `expr:"upper"`
+
+	// Metadata helpers
+	HasGenreFn     func(string) bool `expr:"hasGenre"`
+	InCollectionFn func() bool       `expr:"inCollection"`
+
+	// Collection helpers, looking at every movie in the same TMDB collection
+	CollectionWatchedRecentlyFn func(int) bool `expr:"collectionWatchedRecently"`
+	CollectionAnyRequestedFn    func() bool    `expr:"collectionAnyRequested"`
+
+	// Instance helpers, looking at the same movie in other Radarr instances
+	ExistsInInstanceFn func(string) bool `expr:"existsInInstance"`
+
+	// File helpers
+	HasCustomFormatFn func(string) bool `expr:"hasCustomFormat"`
+
+	// Tag and watch helpers
+	HasTagFn          func(string) bool          `expr:"hasTag"`
+	WatchedByFn       func(string) bool          `expr:"watchedBy"`
+	WatchedAtLeastFn  func(string, float64) bool `expr:"watchedAtLeast"`
+	WatchCountByFn    func(string) int           `expr:"watchCountBy"`
+	WatchProgressByFn func(string) float64       `expr:"watchProgressBy"`
+
+	// Rating helpers
+	IMDBRatingFn           func() float64       `expr:"imdbRating"`
+	TMDBRatingFn           func() float64       `expr:"tmdbRating"`
+	RottenTomatoesRatingFn func() float64       `expr:"rottenTomatoesRating"`
+	MetacriticRatingFn     func() float64       `expr:"metacriticRating"`
+	HasRatingFn            func(string) bool    `expr:"hasRating"`
+	GetRatingFn            func(string) float64 `expr:"getRating"`
+
+	// Request helpers
+	RequestedByFn           func(string) bool    `expr:"requestedBy"`
+	RequestedAfterFn        func(time.Time) bool `expr:"requestedAfter"`
+	RequestedBeforeFn       func(time.Time) bool `expr:"requestedBefore"`
+	RequestStatusFn         func(string) bool    `expr:"requestStatus"`
+	ApprovedByFn            func(string) bool    `expr:"approvedBy"`
+	IsRequestedFn           func() bool          `expr:"isRequested"`
+	NotRequestedFn          func() bool          `expr:"notRequested"`
+	NotWatchedByRequesterFn func() bool          `expr:"notWatchedByRequester"`
+	WatchedByRequesterFn    func() bool          `expr:"watchedByRequester"`
+
+	// Group helpers, for the user groups in the users section of the config
+	RequestedByGroupFn   func(string) bool      `expr:"requestedByGroup"`
+	WatchedByAnyOfFn     func(string) bool      `expr:"watchedByAnyOf"`
+	WatchedByAllOfFn     func(string) bool      `expr:"watchedByAllOf"`
+	LastWatchedByAnyOfFn func(string) time.Time `expr:"lastWatchedByAnyOf"`
+}
+
+// Static helpers shared by every environment
+func daysSince(t time.Time) int {
+	return int(time.Since(t).Hours() / 24)
+}
+
+func daysAgo(days int) time.Time {
+	return time.Now().AddDate(0, 0, -days)
+}
+
+func monthsAgo(months int) time.Time {
+	return time.Now().AddDate(0, -months, 0)
+}
+
+func yearsAgo(years int) time.Time {
+	return time.Now().AddDate(-years, 0, 0)
+}
+
+func parseDate(dateStr string) time.Time {
+	t, _ := time.Parse("2006-01-02", dateStr)
+	return t
+}
+
+func containsFold(str, substr string) bool {
+	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
+}
+
+func startsWithFold(str, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(str), strings.ToLower(prefix))
+}
+
+func endsWithFold(str, suffix string) bool {
+	return strings.HasSuffix(strings.ToLower(str), strings.ToLower(suffix))
+}
+
+// addHelperFunctions sets the static helper functions on the environment
+func addHelperFunctions(env *Env) {
+	// Date helpers
+	env.DaysSinceFn = daysSince
+	env.DaysAgoFn = daysAgo
+	env.MonthsAgoFn = monthsAgo
+	env.YearsAgoFn = yearsAgo
+	env.ParseDateFn = parseDate
+	// String helpers
+	env.ContainsFn = containsFold
+	env.StartsWithFn = startsWithFold
+	env.EndsWithFn = endsWithFold
+	env.LowerFn = strings.ToLower
+	env.UpperFn = strings.ToUpper
+	// Current time
+	env.NowFn = time.Now
+}
+
+// createRuntimeEnvironment creates the runtime environment for filter evaluation.
+// A non-zero minWatchPercent overrides the configured watch threshold.
+func createRuntimeEnvironment(movie radarr.MovieInfo, minWatchPercent float64) *Env {
+	env := &Env{
+		Movie: movie,
+
+		// Direct movie properties for convenience
+		Title:          movie.Title,
+		Year:           movie.Year,
+		Tags:           movie.TagNames,
+		TagNames:       movie.TagNames,
+		Added:          movie.Added,
+		MonitoredSince: movie.MonitoredSince,
+		FileImported:   movie.FileImported,
+		Watched:        movie.Watched,
+		WatchCount:     movie.WatchCount,
+		LastWatched:    movie.LastWatched,
+		WatchProgress:  movie.WatchProgress,
+		HasFile:        movie.HasFile,
+		Path:           movie.Path,
+		IMDBID:         movie.IMDBID,
+		TMDBID:         movie.TMDBID,
+		Ratings:        movie.Ratings,
+		Popularity:     movie.Popularity,
+		UserWatchData:  movie.UserWatchData,
+		// Radarr metadata
+		Genres:           movie.Genres,
+		Studio:           movie.Studio,
+		Certification:    movie.Certification,
+		OriginalLanguage: movie.OriginalLanguage,
+		Runtime:          movie.Runtime,
+		Collection:       movie.CollectionName,
+		CollectionTMDBID: movie.CollectionTMDBID,
+		CollectionSize:   movie.CollectionSize,
+		InCinemas:        movie.InCinemas,
+		DigitalRelease:   movie.DigitalRelease,
+		PhysicalRelease:  movie.PhysicalRelease,
+		Monitored:        movie.Monitored,
+		QualityProfile:   movie.QualityProfile,
+		RootFolder:       movie.RootFolder,
+		Instance:         movie.Instance,
+		Instances:        movie.Instances,
+		// Request properties
+		RequestedBy:      movie.RequestedBy,
+		RequestedByEmail: movie.RequestedByEmail,
+		RequestDate:      movie.RequestDate,
+		RequestStatus:    movie.RequestStatus,
+		ApprovedBy:       movie.ApprovedBy,
+		IsAutoRequest:    movie.IsAutoRequest,
+		IsRequested:      movie.IsRequested,
+	}
+
+	// File properties come from the movie file's quality and media info
+	addFileProperties(env, movie.MovieFile)
+
+	// A filter with its own threshold decides for itself what counts as watched
+	if minWatchPercent > 0 {
+		env.Watched = anyUserWatched(movie.UserWatchData, minWatchPercent)
+	}
+
+	// Add helper functions
+	addHelperFunctions(env)
+
+	// Add movie-specific helper functions using closures for efficiency
+	env.HasTagFn = createHasTagFunc(movie.TagNames)
+	env.HasCustomFormatFn = createHasTagFunc(env.CustomFormats)
+	env.HasGenreFn = createHasTagFunc(movie.Genres)
+	env.InCollectionFn = createInCollectionFunc(movie.CollectionTMDBID)
+	env.CollectionWatchedRecentlyFn = createCollectionWatchedRecentlyFunc(movie.CollectionLastWatched)
+	env.CollectionAnyRequestedFn = createCollectionAnyRequestedFunc(movie.CollectionAnyRequested)
+	env.ExistsInInstanceFn = createExistsInInstanceFunc(movie.Instances)
+	env.WatchedByFn = createWatchedByFunc(movie.UserWatchData, minWatchPercent)
+	env.WatchedAtLeastFn = createWatchedAtLeastFunc(movie.UserWatchData)
+	env.WatchCountByFn = createWatchCountByFunc(movie.UserWatchData)
+	env.WatchProgressByFn = createWatchProgressByFunc(movie.UserWatchData)
+
+	// Rating helpers using closures
+	ratings := movie.Ratings
+	env.IMDBRatingFn = createRatingFunc(ratings, "imdb")
+	env.TMDBRatingFn = createRatingFunc(ratings, "tmdb")
+	env.RottenTomatoesRatingFn = createRatingFunc(ratings, "rottenTomatoes")
+	env.MetacriticRatingFn = createRatingFunc(ratings, "metacritic")
+	env.HasRatingFn = createHasRatingFunc(ratings)
+	env.GetRatingFn = createGetRatingFunc(ratings)
+
+	// Request helpers using closures
+	env.RequestedByFn = createRequestedByFunc(movie.IsRequested, movie.RequestedBy)
+	env.RequestedAfterFn = createRequestedAfterFunc(movie.IsRequested, movie.RequestDate)
+	env.RequestedBeforeFn = createRequestedBeforeFunc(movie.IsRequested, movie.RequestDate)
+	env.RequestStatusFn = createRequestStatusFunc(movie.IsRequested, movie.RequestStatus)
+	env.ApprovedByFn = createApprovedByFunc(movie.IsRequested, movie.ApprovedBy)
+	env.IsRequestedFn = createIsRequestedFunc(movie.IsRequested)
+	env.NotRequestedFn = createNotRequestedFunc(movie.IsRequested)
+	env.NotWatchedByRequesterFn = createNotWatchedByRequesterFunc(movie.IsRequested, movie.RequestedBy, movie.UserWatchData, minWatchPercent)
+	env.WatchedByRequesterFn = createWatchedByRequesterFunc(movie.IsRequested, movie.RequestedBy, movie.UserWatchData, minWatchPercent)
+
+	// Group helpers using closures
+	env.RequestedByGroupFn = createRequestedByGroupFunc(movie.IsRequested, movie.RequestedBy)
+	env.WatchedByAnyOfFn = createWatchedByAnyOfFunc(movie.UserWatchData, minWatchPercent)
+	env.WatchedByAllOfFn = createWatchedByAllOfFunc(movie.UserWatchData, minWatchPercent)
+	env.LastWatchedByAnyOfFn = createLastWatchedByAnyOfFunc(movie.UserWatchData)
+
+	return env
+}
@@ -11,6 +11,8 @@ type (
 		Expression string
 		Reason     string
 		Position   int // -1 if position is unknown
+		Line       int // 1-based, 0 if unknown
+		Column     int // 1-based, 0 if unknown
 		Err        error
 	}
 
@@ -24,6 +26,9 @@ type (
 )
 
 func (e *CompilationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("compilation error at line %d, column %d in '%s': %s", e.Line, e.Column, e.Expression, e.Reason)
+	}
 	if e.Position >= 0 {
 		return fmt.Sprintf("compilation error at position %d in '%s': %s", e.Position, e.Expression, e.Reason)
 	}
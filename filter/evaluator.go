@@ -91,11 +91,11 @@ func (e *ConcurrentEvaluator) EvaluateBatch(ctx context.Context, filters map[str
 			default:
 			}
 
-			matches, err := e.Evaluate(ctx, filter, movies)
+			// Evaluated sequentially: chunking through the pool from inside
+			// a worker would wait on workers that are all busy with filters
 			resultChan <- BatchResult{
 				FilterName: name,
-				Matches:    matches,
-				Error:      err,
+				Matches:    e.evaluateSequential(filter, movies),
 			}
 		})
 
@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"reflect"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// ExplainStep is a single sub-expression of a filter and the value it evaluated to
+type ExplainStep struct {
+	Expression string
+	Depth      int // 0 for the whole expression, increasing for nested operands
+	Value      any
+	Err        error
+}
+
+// Explain evaluates the filter and each of its sub-expressions against a movie.
+// Steps are returned in pre-order so the whole expression comes first.
+func (f *exprFilter) Explain(movie radarr.MovieInfo) ([]ExplainStep, error) {
+	tree, err := parser.Parse(f.expression)
+	if err != nil {
+		return nil, newCompilationError(f.expression, err)
+	}
+
+	env := createRuntimeEnvironment(movie, f.minWatchPercent)
+
+	var steps []ExplainStep
+	f.explainNode(tree.Node, 0, env, &steps)
+	return steps, nil
+}
+
+// explainNode records the value of a node and then descends into its operands
+func (f *exprFilter) explainNode(node ast.Node, depth int, env *Env, steps *[]ExplainStep) {
+	switch node.(type) {
+	case *ast.NilNode, *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.StringNode, *ast.ConstantNode:
+		// Literals explain themselves
+		return
+	}
+
+	expression := node.String()
+	program, err := expr.Compile(expression, f.options...)
+	if err != nil {
+		// Fragments such as predicate bodies can't be compiled on their own
+		if depth == 0 {
+			*steps = append(*steps, ExplainStep{Expression: expression, Depth: depth, Err: err})
+		}
+		return
+	}
+
+	value, err := expr.Run(program, env)
+	if err == nil && value != nil && reflect.TypeOf(value).Kind() == reflect.Func {
+		return
+	}
+	*steps = append(*steps, ExplainStep{
+		Expression: expression,
+		Depth:      depth,
+		Value:      value,
+		Err:        err,
+	})
+
+	for _, child := range explainChildren(node) {
+		f.explainNode(child, depth+1, env, steps)
+	}
+}
+
+// explainChildren returns the operands worth explaining for a node
+func explainChildren(node ast.Node) []ast.Node {
+	switch n := node.(type) {
+	case *ast.UnaryNode:
+		return []ast.Node{n.Node}
+	case *ast.BinaryNode:
+		return []ast.Node{n.Left, n.Right}
+	case *ast.ConditionalNode:
+		return []ast.Node{n.Cond, n.Exp1, n.Exp2}
+	case *ast.ChainNode:
+		return []ast.Node{n.Node}
+	case *ast.CallNode:
+		return n.Arguments
+	case *ast.BuiltinNode:
+		return n.Arguments
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package filter
 
 import (
+	"errors"
+	"fmt"
 	"maps"
+	"reflect"
 	"slices"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/file"
 	"github.com/expr-lang/expr/vm"
 	"github.com/s0up4200/arrbiter/radarr"
 )
@@ -16,6 +19,9 @@ import (
 type exprFilter struct {
 	expression string
 	program    *vm.Program
+	options    []expr.Option // Environment options, reused by Explain
+
+	minWatchPercent float64 // Overrides the configured watch threshold when set
 }
 
 // ExprCompilerOption configures an expr compiler
@@ -30,37 +36,31 @@ func WithCache(size int) ExprCompilerOption {
 	}
 }
 
-// WithCustomFunctions adds custom helper functions
+// WithCustomFunctions adds custom helper functions.
+// Each value must be a Go function; its signature is used for type checking.
 func WithCustomFunctions(funcs map[string]any) ExprCompilerOption {
 	return func(c *exprCompiler) {
-		maps.Copy(c.helperFuncs, funcs)
+		maps.Copy(c.customFuncs, funcs)
 	}
 }
 
 // NewExprCompiler creates a new expr-based filter compiler
 func NewExprCompiler(opts ...ExprCompilerOption) Compiler {
 	c := &exprCompiler{
-		helperFuncs: createHelperFunctions(),
-		envPool:     &sync.Pool{},
+		customFuncs: make(map[string]any),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	// Initialize environment pool
-	c.envPool.New = func() any {
-		return make(map[string]any, 64) // Pre-size for typical use
-	}
-
 	return c
 }
 
 // exprCompiler implements Compiler for expr-based filters
 type exprCompiler struct {
-	helperFuncs map[string]any
+	customFuncs map[string]any
 	cache       *lruCache
-	envPool     *sync.Pool // Pool for environment maps
 }
 
 // Compile compiles an expression into an executable filter
@@ -70,6 +70,7 @@ func (c *exprCompiler) Compile(expression string) (CompiledFilter, error) {
 		return nil, &CompilationError{
 			Expression: expression,
 			Reason:     "empty expression",
+			Position:   -1,
 		}
 	}
 
@@ -80,23 +81,15 @@ func (c *exprCompiler) Compile(expression string) (CompiledFilter, error) {
 		}
 	}
 
-	// Compile with static environment for validation
-	program, err := expr.Compile(expression,
-		expr.Env(c.helperFuncs),
-		expr.AllowUndefinedVariables(), // Allow movie properties
-		expr.AsBool(),                  // Ensure boolean result
-	)
+	program, options, err := c.compileProgram(expression, expr.AsBool())
 	if err != nil {
-		return nil, &CompilationError{
-			Expression: expression,
-			Reason:     "failed to compile expression",
-			Err:        err,
-		}
+		return nil, err
 	}
 
 	filter := &exprFilter{
 		expression: expression,
 		program:    program,
+		options:    options,
 	}
 
 	// Cache if enabled
@@ -107,6 +100,100 @@ func (c *exprCompiler) Compile(expression string) (CompiledFilter, error) {
 	return filter, nil
 }
 
+// compileProgram compiles an expression against the typed environment so
+// unknown identifiers are rejected. Extra options constrain the result type.
+func (c *exprCompiler) compileProgram(expression string, extra ...expr.Option) (*vm.Program, []expr.Option, error) {
+	options, err := c.compileOptions()
+	if err != nil {
+		return nil, nil, &CompilationError{
+			Expression: expression,
+			Reason:     err.Error(),
+			Position:   -1,
+			Err:        err,
+		}
+	}
+
+	program, err := expr.Compile(expression, append(slices.Clip(options), extra...)...)
+	if err != nil {
+		return nil, nil, newCompilationError(expression, err)
+	}
+
+	return program, options, nil
+}
+
+// compileOptions builds the environment options shared by every compilation
+func (c *exprCompiler) compileOptions() ([]expr.Option, error) {
+	options := []expr.Option{
+		expr.Env(Env{}),
+	}
+
+	for name, fn := range c.customFuncs {
+		option, err := customFunction(name, fn)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// customFunction wraps an arbitrary Go function as a typed expr function
+func customFunction(name string, fn any) (expr.Option, error) {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return nil, fmt.Errorf("custom function '%s' is not a function", name)
+	}
+	fnType := fnValue.Type()
+
+	call := func(params ...any) (any, error) {
+		args := make([]reflect.Value, len(params))
+		for i, param := range params {
+			if param == nil {
+				args[i] = reflect.Zero(fnType.In(min(i, fnType.NumIn()-1)))
+				continue
+			}
+			args[i] = reflect.ValueOf(param)
+		}
+
+		out := fnValue.Call(args)
+		if len(out) == 0 {
+			return nil, nil
+		}
+		if last := out[len(out)-1]; last.Type().Implements(reflect.TypeFor[error]()) {
+			if !last.IsNil() {
+				return nil, last.Interface().(error)
+			}
+			if len(out) == 1 {
+				return nil, nil
+			}
+		}
+		return out[0].Interface(), nil
+	}
+
+	return expr.Function(name, call, fn), nil
+}
+
+// newCompilationError converts an expr error into a CompilationError with source position
+func newCompilationError(expression string, err error) *CompilationError {
+	compErr := &CompilationError{
+		Expression: expression,
+		Reason:     err.Error(),
+		Position:   -1,
+		Err:        err,
+	}
+
+	var fileErr *file.Error
+	if errors.As(err, &fileErr) {
+		compErr.Reason = fileErr.Message
+		compErr.Position = fileErr.From
+		compErr.Line = fileErr.Line
+		compErr.Column = fileErr.Column + 1 // expr columns are 0-based
+	}
+
+	return compErr
+}
+
 // Clear removes all cached filters
 func (c *exprCompiler) Clear() {
 	if c.cache != nil {
@@ -124,18 +211,23 @@ func (c *exprCompiler) Size() int {
 
 // Evaluate evaluates the filter against a movie
 func (f *exprFilter) Evaluate(movie radarr.MovieInfo) bool {
+	// Movies that cause errors are skipped
+	matched, err := f.Match(movie)
+	return err == nil && matched
+}
+
+// Match evaluates the filter against a movie, returning any runtime error
+func (f *exprFilter) Match(movie radarr.MovieInfo) (bool, error) {
 	// Create runtime environment with movie data and dynamic helpers
-	env := createRuntimeEnvironment(movie)
+	env := createRuntimeEnvironment(movie, f.minWatchPercent)
 
 	result, err := expr.Run(f.program, env)
 	if err != nil {
-		// In production, we might want to log this error
-		// For now, we return false to skip movies that cause errors
-		return false
+		return false, err
 	}
 
 	// Result is guaranteed to be bool due to AsBool() option during compilation
-	return result.(bool)
+	return result.(bool), nil
 }
 
 // Expression returns the original expression
@@ -148,114 +240,6 @@ func (f *exprFilter) IsThreadSafe() bool {
 	return true
 }
 
-// createHelperFunctions creates the static helper functions used during compilation
-func createHelperFunctions() map[string]any {
-	funcs := make(map[string]any, 32)
-	addHelperFunctions(funcs)
-	return funcs
-}
-
-// addHelperFunctions adds all helper functions to the provided map
-func addHelperFunctions(env map[string]any) {
-	// Date helpers
-	env["daysSince"] = func(t time.Time) int {
-		return int(time.Since(t).Hours() / 24)
-	}
-	env["daysAgo"] = func(days int) time.Time {
-		return time.Now().AddDate(0, 0, -days)
-	}
-	env["monthsAgo"] = func(months int) time.Time {
-		return time.Now().AddDate(0, -months, 0)
-	}
-	env["yearsAgo"] = func(years int) time.Time {
-		return time.Now().AddDate(-years, 0, 0)
-	}
-	env["parseDate"] = func(dateStr string) time.Time {
-		t, _ := time.Parse("2006-01-02", dateStr)
-		return t
-	}
-	// String helpers
-	env["contains"] = func(str, substr string) bool {
-		return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
-	}
-	env["startsWith"] = func(str, prefix string) bool {
-		return strings.HasPrefix(strings.ToLower(str), strings.ToLower(prefix))
-	}
-	env["endsWith"] = func(str, suffix string) bool {
-		return strings.HasSuffix(strings.ToLower(str), strings.ToLower(suffix))
-	}
-	env["lower"] = strings.ToLower
-	env["upper"] = strings.ToUpper
-	// Current time
-	env["now"] = time.Now
-}
-
-// createRuntimeEnvironment creates the runtime environment for filter evaluation
-func createRuntimeEnvironment(movie radarr.MovieInfo) map[string]any {
-	// Pre-allocate with expected size
-	env := make(map[string]any, 64)
-
-	// Add helper functions
-	addHelperFunctions(env)
-
-	// Add movie data
-	env["Movie"] = movie
-
-	// Add movie-specific helper functions using closures for efficiency
-	env["hasTag"] = createHasTagFunc(movie.TagNames)
-	env["watchedBy"] = createWatchedByFunc(movie.UserWatchData)
-	env["watchCountBy"] = createWatchCountByFunc(movie.UserWatchData)
-	env["watchProgressBy"] = createWatchProgressByFunc(movie.UserWatchData)
-
-	// Rating helpers using closures
-	ratings := movie.Ratings
-	env["imdbRating"] = createRatingFunc(ratings, "imdb")
-	env["tmdbRating"] = createRatingFunc(ratings, "tmdb")
-	env["rottenTomatoesRating"] = createRatingFunc(ratings, "rottenTomatoes")
-	env["metacriticRating"] = createRatingFunc(ratings, "metacritic")
-	env["hasRating"] = createHasRatingFunc(ratings)
-	env["getRating"] = createGetRatingFunc(ratings)
-
-	// Request helpers using closures
-	env["requestedBy"] = createRequestedByFunc(movie.IsRequested, movie.RequestedBy)
-	env["requestedAfter"] = createRequestedAfterFunc(movie.IsRequested, movie.RequestDate)
-	env["requestedBefore"] = createRequestedBeforeFunc(movie.IsRequested, movie.RequestDate)
-	env["requestStatus"] = createRequestStatusFunc(movie.IsRequested, movie.RequestStatus)
-	env["approvedBy"] = createApprovedByFunc(movie.IsRequested, movie.ApprovedBy)
-	env["isRequested"] = createIsRequestedFunc(movie.IsRequested)
-	env["notRequested"] = createNotRequestedFunc(movie.IsRequested)
-	env["notWatchedByRequester"] = createNotWatchedByRequesterFunc(movie.IsRequested, movie.RequestedBy, movie.UserWatchData)
-	env["watchedByRequester"] = createWatchedByRequesterFunc(movie.IsRequested, movie.RequestedBy, movie.UserWatchData)
-
-	// Direct movie properties for convenience
-	env["Title"] = movie.Title
-	env["Year"] = movie.Year
-	env["Tags"] = movie.TagNames
-	env["Added"] = movie.Added
-	env["MonitoredSince"] = movie.MonitoredSince
-	env["FileImported"] = movie.FileImported
-	env["Watched"] = movie.Watched
-	env["WatchCount"] = movie.WatchCount
-	env["LastWatched"] = movie.LastWatched
-	env["WatchProgress"] = movie.WatchProgress
-	env["HasFile"] = movie.HasFile
-	env["Path"] = movie.Path
-	env["IMDBID"] = movie.IMDBID
-	env["TMDBID"] = movie.TMDBID
-	env["Ratings"] = movie.Ratings
-	env["Popularity"] = movie.Popularity
-	// Request properties
-	env["RequestedBy"] = movie.RequestedBy
-	env["RequestedByEmail"] = movie.RequestedByEmail
-	env["RequestDate"] = movie.RequestDate
-	env["RequestStatus"] = movie.RequestStatus
-	env["ApprovedBy"] = movie.ApprovedBy
-	env["IsAutoRequest"] = movie.IsAutoRequest
-	env["IsRequested"] = movie.IsRequested
-
-	return env
-}
-
 // Helper factory functions for better performance through closures
 
 func createHasTagFunc(tags []string) func(string) bool {
@@ -270,10 +254,16 @@ func createHasTagFunc(tags []string) func(string) bool {
 	}
 }
 
-func createWatchedByFunc(watchData map[string]*radarr.UserWatchInfo) func(string) bool {
+func createInCollectionFunc(collectionTMDBID int64) func() bool {
+	return func() bool {
+		return collectionTMDBID != 0
+	}
+}
+
+func createWatchedByFunc(watchData map[string]*radarr.UserWatchInfo, minWatchPercent float64) func(string) bool {
 	return func(username string) bool {
-		if userData, exists := watchData[username]; exists {
-			return userData.Watched
+		if userData, exists := userWatch(watchData, username); exists {
+			return userWatched(userData, username, minWatchPercent)
 		}
 		return false
 	}
@@ -281,7 +271,7 @@ func createWatchedByFunc(watchData map[string]*radarr.UserWatchInfo) func(string
 
 func createWatchCountByFunc(watchData map[string]*radarr.UserWatchInfo) func(string) int {
 	return func(username string) int {
-		if userData, exists := watchData[username]; exists {
+		if userData, exists := userWatch(watchData, username); exists {
 			return userData.WatchCount
 		}
 		return 0
@@ -290,7 +280,7 @@ func createWatchCountByFunc(watchData map[string]*radarr.UserWatchInfo) func(str
 
 func createWatchProgressByFunc(watchData map[string]*radarr.UserWatchInfo) func(string) float64 {
 	return func(username string) float64 {
-		if userData, exists := watchData[username]; exists {
+		if userData, exists := userWatch(watchData, username); exists {
 			return userData.MaxProgress
 		}
 		return 0
@@ -325,7 +315,7 @@ func createGetRatingFunc(ratings map[string]float64) func(string) float64 {
 
 func createRequestedByFunc(isRequested bool, requestedBy string) func(string) bool {
 	return func(username string) bool {
-		return isRequested && strings.EqualFold(requestedBy, username)
+		return isRequested && sameUser(requestedBy, username)
 	}
 }
 
@@ -350,7 +340,7 @@ func createRequestStatusFunc(isRequested bool, requestStatus string) func(string
 
 func createApprovedByFunc(isRequested bool, approvedBy string) func(string) bool {
 	return func(username string) bool {
-		return isRequested && strings.EqualFold(approvedBy, username)
+		return isRequested && sameUser(approvedBy, username)
 	}
 }
 
@@ -366,29 +356,27 @@ func createNotRequestedFunc(isRequested bool) func() bool {
 	}
 }
 
-func createNotWatchedByRequesterFunc(isRequested bool, requestedBy string, watchData map[string]*radarr.UserWatchInfo) func() bool {
+func createNotWatchedByRequesterFunc(isRequested bool, requestedBy string, watchData map[string]*radarr.UserWatchInfo, minWatchPercent float64) func() bool {
 	return func() bool {
 		if !isRequested || requestedBy == "" {
 			return false
 		}
 		// Check if the requester has watched it
-		const minWatchPercent = 85.0 // Default watch threshold
-		if userData, exists := watchData[requestedBy]; exists {
-			return userData.MaxProgress < minWatchPercent
+		if userData, exists := userWatch(watchData, requestedBy); exists {
+			return !userWatched(userData, requestedBy, minWatchPercent)
 		}
 		return true // Not watched if no watch data
 	}
 }
 
-func createWatchedByRequesterFunc(isRequested bool, requestedBy string, watchData map[string]*radarr.UserWatchInfo) func() bool {
+func createWatchedByRequesterFunc(isRequested bool, requestedBy string, watchData map[string]*radarr.UserWatchInfo, minWatchPercent float64) func() bool {
 	return func() bool {
 		if !isRequested || requestedBy == "" {
 			return false
 		}
 		// Check if the requester has watched it
-		const minWatchPercent = 85.0 // Default watch threshold
-		if userData, exists := watchData[requestedBy]; exists {
-			return userData.MaxProgress >= minWatchPercent
+		if userData, exists := userWatch(watchData, requestedBy); exists {
+			return userWatched(userData, requestedBy, minWatchPercent)
 		}
 		return false // Not watched if no watch data
 	}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+
+	"golift.io/starr/radarr"
+)
+
+// addFileProperties fills the file, quality and media info fields of the environment
+func addFileProperties(env *Env, file *radarr.MovieFile) {
+	if file == nil {
+		return
+	}
+
+	env.SizeBytes = file.Size
+	env.SizeGB = float64(file.Size) / (1 << 30)
+	env.ReleaseGroup = file.ReleaseGroup
+	env.Edition = file.Edition
+	env.CustomFormatScore = file.CustomFormatScore
+
+	for _, cf := range file.CustomFormats {
+		if cf != nil && cf.Name != "" {
+			env.CustomFormats = append(env.CustomFormats, cf.Name)
+		}
+	}
+
+	if file.Quality != nil && file.Quality.Quality != nil {
+		env.Quality = file.Quality.Quality.Name
+		env.QualitySource = file.Quality.Quality.Source
+		env.Resolution = file.Quality.Quality.Resolution
+	}
+
+	if info := file.MediaInfo; info != nil {
+		env.VideoCodec = info.VideoCodec
+		env.VideoBitDepth = info.VideoBitDepth
+		env.HDR = info.VideoDynamicRangeType
+		env.IsHDR = info.VideoDynamicRangeType != ""
+		env.AudioCodec = info.AudioCodec
+		env.AudioChannels = info.AudioChannels
+		env.AudioLanguages = info.AudioLanguages
+		env.FileRuntime = parseRuntimeMinutes(info.RunTime)
+		if env.Resolution == 0 {
+			env.Resolution = resolutionClass(info.Resolution)
+		}
+	}
+}
+
+// parseRuntimeMinutes converts a media info runtime such as "1:58:23" or "58:23" to minutes
+func parseRuntimeMinutes(runtime string) int {
+	parts := strings.Split(strings.TrimSpace(runtime), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + value
+	}
+	return int(seconds / 60)
+}
+
+// resolutionClass maps a media info resolution such as "3840x1600" to 2160, 1080, 720 or 480.
+// The width is used so that letterboxed widescreen files land in the right class.
+func resolutionClass(resolution string) int {
+	width, _, ok := strings.Cut(strings.ToLower(resolution), "x")
+	if !ok {
+		return 0
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(width))
+	if err != nil {
+		return 0
+	}
+
+	switch {
+	case w >= 3200:
+		return 2160
+	case w >= 1600:
+		return 1080
+	case w >= 1100:
+		return 720
+	case w > 0:
+		return 480
+	}
+	return 0
+}
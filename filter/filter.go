@@ -2,6 +2,7 @@ package filter
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -56,12 +57,28 @@ func CompileFilter(expression string) (CompiledFilter, error) {
 		return nil, &CompilationError{
 			Expression: expression,
 			Reason:     "empty expression",
+			Position:   -1,
 		}
 	}
 
 	return defaultCompiler.Compile(expression)
 }
 
+// ExplainFilter evaluates a filter expression and all of its sub-expressions against a movie
+func ExplainFilter(expression string, movie radarr.MovieInfo) ([]ExplainStep, error) {
+	compiled, err := CompileFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	explainer, ok := compiled.(Explainer)
+	if !ok {
+		return nil, fmt.Errorf("filter does not support explain")
+	}
+
+	return explainer.Explain(movie)
+}
+
 // EvaluateFilters evaluates multiple filters against movies concurrently
 func EvaluateFilters(ctx context.Context, filters map[string]string, movies []radarr.MovieInfo) (map[string][]radarr.MovieInfo, error) {
 	initDefaults()
@@ -74,6 +91,7 @@ func EvaluateFilters(ctx context.Context, filters map[string]string, movies []ra
 			return nil, &CompilationError{
 				Expression: expr,
 				Reason:     "failed to compile filter '" + name + "'",
+				Position:   -1,
 				Err:        err,
 			}
 		}
@@ -185,7 +185,7 @@ func BenchmarkHelperFunctions(b *testing.B) {
 	})
 
 	b.Run("watchedBy", func(b *testing.B) {
-		watchedBy := createWatchedByFunc(movie.UserWatchData)
+		watchedBy := createWatchedByFunc(movie.UserWatchData, 0)
 		b.ReportAllocs()
 		b.ResetTimer()
 
@@ -2,10 +2,18 @@ package filter
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"golift.io/starr"
+	starr_radarr "golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/identity"
 	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/sonarr"
+	"github.com/s0up4200/arrbiter/watch"
 )
 
 func TestCompileFilter(t *testing.T) {
@@ -60,6 +68,117 @@ func TestCompileFilter(t *testing.T) {
 	}
 }
 
+func TestCompileFilterRejectsInvalidExpressions(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantLine   int
+		wantColumn int
+		wantReason string
+	}{
+		{
+			name:       "misspelled variable",
+			expression: `not Wacthed`,
+			wantLine:   1,
+			wantColumn: 5,
+			wantReason: "unknown name Wacthed",
+		},
+		{
+			name:       "misspelled helper",
+			expression: `imbdRating() > 7`,
+			wantLine:   1,
+			wantColumn: 1,
+		},
+		{
+			name:       "wrong argument type",
+			expression: `hasTag(1)`,
+			wantLine:   1,
+		},
+		{
+			name:       "non-bool result",
+			expression: `Year + 1`,
+			wantReason: "expected bool",
+		},
+		{
+			name:       "error on second line",
+			expression: "Year > 2020 and\nhasTagg(\"keep\")",
+			wantLine:   2,
+			wantColumn: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileFilter(tt.expression)
+			if err == nil {
+				t.Fatalf("expected error for %q but got none", tt.expression)
+			}
+
+			var compErr *CompilationError
+			if !errors.As(err, &compErr) {
+				t.Fatalf("expected CompilationError, got %T", err)
+			}
+			if compErr.Line != tt.wantLine {
+				t.Errorf("expected line %d, got %d", tt.wantLine, compErr.Line)
+			}
+			if tt.wantColumn > 0 && compErr.Column != tt.wantColumn {
+				t.Errorf("expected column %d, got %d", tt.wantColumn, compErr.Column)
+			}
+			if tt.wantReason != "" && !contains(compErr.Reason, tt.wantReason) {
+				t.Errorf("reason %q does not contain %q", compErr.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCustomFunctions(t *testing.T) {
+	compiler := NewExprCompiler(WithCustomFunctions(map[string]any{
+		"isLong": func(title string) bool { return len(title) > 8 },
+	}))
+
+	filter, err := compiler.Compile(`isLong(Title)`)
+	if err != nil {
+		t.Fatalf("failed to compile filter: %v", err)
+	}
+	if !filter.Evaluate(radarr.MovieInfo{Title: "A Long Movie Title"}) {
+		t.Error("expected custom function to match long title")
+	}
+
+	if _, err := compiler.Compile(`isLong(42)`); err == nil {
+		t.Error("expected type error for custom function argument")
+	}
+}
+
+func TestExplainFilter(t *testing.T) {
+	movie := radarr.MovieInfo{
+		Year:     2022,
+		TagNames: []string{"action"},
+		Ratings:  map[string]float64{"imdb": 5.1},
+	}
+
+	steps, err := ExplainFilter(`imdbRating() < 6 and not hasTag("keep")`, movie)
+	if err != nil {
+		t.Fatalf("failed to explain filter: %v", err)
+	}
+	if len(steps) == 0 || steps[0].Depth != 0 || steps[0].Value != true {
+		t.Fatalf("expected whole expression to evaluate to true first, got %+v", steps)
+	}
+
+	values := make(map[string]any)
+	for _, step := range steps {
+		if step.Err != nil {
+			t.Errorf("unexpected error for %q: %v", step.Expression, step.Err)
+		}
+		values[step.Expression] = step.Value
+	}
+	if values["imdbRating()"] != 5.1 {
+		t.Errorf("expected imdbRating() = 5.1, got %v", values["imdbRating()"])
+	}
+	if values[`hasTag("keep")`] != false {
+		t.Errorf(`expected hasTag("keep") = false, got %v`, values[`hasTag("keep")`])
+	}
+}
+
 func TestFilterEvaluation(t *testing.T) {
 	// Create test movie
 	movie := radarr.MovieInfo{
@@ -176,6 +295,422 @@ func TestFilterEvaluation(t *testing.T) {
 	}
 }
 
+func TestFileProperties(t *testing.T) {
+	movie := radarr.MovieInfo{
+		ID:      1,
+		Title:   "Big Remux",
+		HasFile: true,
+		MovieFile: &starr_radarr.MovieFile{
+			Size: 60 << 30,
+			Quality: &starr.Quality{
+				Quality: &starr.BaseQuality{Name: "Remux-2160p", Source: "bluray", Resolution: 2160},
+			},
+			MediaInfo: &starr_radarr.MediaInfo{
+				VideoCodec:            "HEVC",
+				VideoBitDepth:         10,
+				VideoDynamicRangeType: "DV HDR10",
+				AudioCodec:            "TrueHD Atmos",
+				AudioChannels:         7.1,
+				RunTime:               "2:49:03",
+				Resolution:            "3840x1600",
+			},
+			ReleaseGroup:      "FraMeSToR",
+			Edition:           "Extended",
+			CustomFormatScore: 1500,
+			CustomFormats: []*starr_radarr.CustomFormatOutput{
+				{Name: "DV HDR10"},
+				{Name: "Remux Tier 01"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		movie      radarr.MovieInfo
+		expected   bool
+	}{
+		{"size", `SizeGB > 40 and SizeBytes == 60 * 1024 * 1024 * 1024`, movie, true},
+		{"quality", `Quality == "Remux-2160p" and QualitySource == "bluray" and Resolution == 2160`, movie, true},
+		{"video", `VideoCodec == "HEVC" and VideoBitDepth == 10 and IsHDR and HDR contains "DV"`, movie, true},
+		{"audio", `AudioCodec == "TrueHD Atmos" and AudioChannels > 6`, movie, true},
+		{"runtime", `FileRuntime == 169`, movie, true},
+		{"release", `ReleaseGroup == "FraMeSToR" and Edition == "Extended"`, movie, true},
+		{"custom formats", `hasCustomFormat("remux tier 01") and "DV HDR10" in CustomFormats and CustomFormatScore >= 1000`, movie, true},
+		{"no file", `SizeGB == 0 and Resolution == 0 and not IsHDR and len(CustomFormats) == 0`, radarr.MovieInfo{ID: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := CompileFilter(tt.expression)
+			if err != nil {
+				t.Fatalf("failed to compile filter: %v", err)
+			}
+
+			if result := filter.Evaluate(tt.movie); result != tt.expected {
+				t.Errorf("expected %v but got %v for expression %q", tt.expected, result, tt.expression)
+			}
+		})
+	}
+
+	// Resolution falls back to media info, classified by width
+	movie.MovieFile.Quality = nil
+	if env := createRuntimeEnvironment(movie, 0); env.Resolution != 2160 {
+		t.Errorf("expected resolution 2160 from media info, got %d", env.Resolution)
+	}
+}
+
+func TestMetadataProperties(t *testing.T) {
+	movie := radarr.MovieInfo{
+		ID:               1,
+		Title:            "Halloween",
+		Genres:           []string{"Horror", "Thriller"},
+		Certification:    "R",
+		OriginalLanguage: "English",
+		Runtime:          91,
+		CollectionName:   "Halloween Collection",
+		CollectionTMDBID: 91361,
+		DigitalRelease:   time.Now().AddDate(-1, 0, 0),
+		QualityProfile:   "HD-1080p",
+		RootFolder:       "/movies",
+	}
+
+	tests := []struct {
+		expression string
+		movie      radarr.MovieInfo
+		expected   bool
+	}{
+		{`hasGenre("horror") and not hasGenre("Comedy")`, movie, true},
+		{`inCollection() and Collection == "Halloween Collection" and CollectionTMDBID == 91361`, movie, true},
+		{`Certification == "R" and OriginalLanguage == "English" and Runtime < 100`, movie, true},
+		{`DigitalRelease < monthsAgo(6) and QualityProfile == "HD-1080p" and RootFolder == "/movies"`, movie, true},
+		{`inCollection()`, radarr.MovieInfo{ID: 2}, false},
+	}
+
+	for _, tt := range tests {
+		filter, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		if result := filter.Evaluate(tt.movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q", tt.expected, result, tt.expression)
+		}
+	}
+}
+
+func TestCollections(t *testing.T) {
+	library := []radarr.MovieInfo{
+		{ID: 1, Title: "Part 1", CollectionTMDBID: 10, Added: time.Now().AddDate(-2, 0, 0)},
+		{ID: 2, Title: "Part 2", CollectionTMDBID: 10, Added: time.Now().AddDate(-2, 0, 0)},
+		{ID: 3, Title: "Part 3", CollectionTMDBID: 10, LastWatched: time.Now().AddDate(0, 0, -3)},
+		{ID: 4, Title: "Other 1", CollectionTMDBID: 20, IsRequested: true},
+		{ID: 5, Title: "Other 2", CollectionTMDBID: 20},
+		{ID: 6, Title: "Standalone"},
+	}
+
+	AnnotateCollections(library)
+
+	tests := []struct {
+		expression string
+		movie      radarr.MovieInfo
+		expected   bool
+	}{
+		{`collectionWatchedRecently(7) and CollectionSize == 3`, library[0], true},
+		{`collectionWatchedRecently(2)`, library[0], false},
+		{`collectionAnyRequested()`, library[4], true},
+		{`collectionAnyRequested()`, library[0], false},
+		{`collectionWatchedRecently(365) or collectionAnyRequested() or CollectionSize > 0`, library[5], false},
+	}
+
+	for _, tt := range tests {
+		filter, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		if result := filter.Evaluate(tt.movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q on %s", tt.expected, result, tt.expression, tt.movie.Title)
+		}
+	}
+
+	// Parts 1 and 2 match but part 3 doesn't, so the collection is held back
+	matches := []radarr.MovieInfo{library[0], library[1], library[3], library[4], library[5]}
+	kept, held := CompleteCollections(matches, library)
+	if len(kept) != 3 || kept[0].ID != 4 || kept[1].ID != 5 || kept[2].ID != 6 {
+		t.Errorf("expected movies 4, 5 and 6 to be kept, got %+v", kept)
+	}
+	if len(held) != 2 || held[0].ID != 1 || held[1].ID != 2 {
+		t.Errorf("expected movies 1 and 2 to be held, got %+v", held)
+	}
+}
+
+func TestInstances(t *testing.T) {
+	library := []radarr.MovieInfo{
+		{ID: 1, Instance: "hd", Title: "Dune", TMDBID: 438631, CollectionTMDBID: 10, HasFile: true},
+		{ID: 1, Instance: "4k", Title: "Dune", TMDBID: 438631, CollectionTMDBID: 10, HasFile: true},
+		{ID: 2, Instance: "hd", Title: "Dune: Part Two", TMDBID: 693134, CollectionTMDBID: 10, HasFile: true},
+		{ID: 3, Instance: "hd", Title: "Arrival", TMDBID: 329865, HasFile: true},
+		// Still downloading in 4k
+		{ID: 2, Instance: "4k", Title: "Arrival", TMDBID: 329865},
+	}
+
+	AnnotateInstances(library)
+	AnnotateCollections(library)
+
+	tests := []struct {
+		expression string
+		movie      radarr.MovieInfo
+		expected   bool
+	}{
+		{`Instance == "hd" and existsInInstance("4k")`, library[0], true},
+		{`existsInInstance("4k")`, library[2], false},
+		{`existsInInstance("hd") and len(Instances) == 2`, library[1], true},
+		{`len(Instances) == 1 and Instances[0] == "hd"`, library[3], true},
+		// A copy without a file doesn't count, for itself or for others
+		{`existsInInstance("4k")`, library[3], false},
+		{`existsInInstance("hd") and not existsInInstance("4k")`, library[4], true},
+		// Collections are counted per instance
+		{`CollectionSize == 2`, library[0], true},
+		{`CollectionSize == 1`, library[1], true},
+	}
+
+	for _, tt := range tests {
+		filter, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		if result := filter.Evaluate(tt.movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q on %s [%s]", tt.expected, result, tt.expression, tt.movie.Title, tt.movie.Instance)
+		}
+	}
+
+	// The 4k copy of Dune is complete on its own, the HD collection is not
+	matches := []radarr.MovieInfo{library[0], library[1]}
+	kept, held := CompleteCollections(matches, library)
+	if len(kept) != 1 || kept[0].Instance != "4k" {
+		t.Errorf("expected only the 4k copy to be kept, got %+v", kept)
+	}
+	if len(held) != 1 || held[0].Instance != "hd" {
+		t.Errorf("expected the hd copy to be held, got %+v", held)
+	}
+}
+
+func TestIdentities(t *testing.T) {
+	resolver := identity.NewResolver()
+	resolver.Link("bob", "Bobby Tables")
+	// Overseerr and Tautulli know alice under different names, linked by her Plex account
+	resolver.Add(identity.Account{Names: []string{"Alice Smith", "asmith"}, Email: "alice@example.com", PlexID: 42})
+	resolver.Add(identity.Account{Names: []string{"Alice", "alice_plex"}, PlexID: 42})
+	SetIdentities(resolver)
+	defer SetIdentities(nil)
+
+	movie := radarr.MovieInfo{
+		Title:       "Heat",
+		IsRequested: true,
+		RequestedBy: "Alice Smith",
+		ApprovedBy:  "Bobby Tables",
+		UserWatchData: map[string]*radarr.UserWatchInfo{
+			"Alice":    {Username: "Alice", Watched: true, WatchCount: 1, MaxProgress: 95},
+			"alice_jf": {Username: "alice_jf", WatchCount: 2, MaxProgress: 40},
+		},
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`watchedByRequester()`, true},
+		{`notWatchedByRequester()`, false},
+		{`requestedBy("alice_plex") and requestedBy("alice@example.com")`, true},
+		{`approvedBy("bob")`, true},
+		{`watchedBy("asmith") and watchCountBy("Alice Smith") == 1`, true},
+		{`requestedBy("bob")`, false},
+	}
+
+	for _, tt := range tests {
+		filter, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		if result := filter.Evaluate(movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q", tt.expected, result, tt.expression)
+		}
+	}
+
+	// Watching under several names adds up once the names are linked
+	resolver.Link("alice_jf", "alice@example.com")
+	filter, err := CompileFilter(`watchCountBy("alice") == 3 and watchProgressBy("alice") == 95`)
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	if !filter.Evaluate(movie) {
+		t.Error("expected watch data under both names to be combined")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	resolver := identity.NewResolver()
+	resolver.Link("alice", "Alice Smith")
+	resolver.AddGroup("family", "alice", "bob")
+	resolver.AddGroup("roommates", "carol")
+	SetIdentities(resolver)
+	defer SetIdentities(nil)
+
+	lastWatched := time.Now().AddDate(0, 0, -90)
+	movie := radarr.MovieInfo{
+		Title:       "Heat",
+		IsRequested: true,
+		RequestedBy: "Alice Smith",
+		UserWatchData: map[string]*radarr.UserWatchInfo{
+			"alice": {Username: "alice", Watched: true, WatchCount: 1, MaxProgress: 100, LastWatched: lastWatched},
+			"bob":   {Username: "bob", WatchCount: 1, MaxProgress: 30, LastWatched: lastWatched.AddDate(0, 0, -5)},
+		},
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`requestedByGroup("family")`, true},
+		{`requestedByGroup("Family")`, true},
+		{`requestedByGroup("roommates")`, false},
+		{`watchedByAnyOf("family")`, true},
+		{`watchedByAllOf("family")`, false},
+		{`watchedByAnyOf("roommates") or watchedByAllOf("roommates")`, false},
+		{`watchedByAnyOf("nobody") or watchedByAllOf("nobody")`, false},
+		{`requestedByGroup("family") and lastWatchedByAnyOf("family") < daysAgo(60)`, true},
+		{`lastWatchedByAnyOf("roommates").IsZero()`, true},
+	}
+
+	for _, tt := range tests {
+		filter, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		if result := filter.Evaluate(movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q", tt.expected, result, tt.expression)
+		}
+	}
+
+	series := sonarr.SeriesInfo{
+		Title:       "The Wire",
+		IsRequested: true,
+		RequestedBy: "carol",
+		UserWatchData: map[string]*sonarr.UserWatchInfo{
+			"carol": {Username: "carol", Watched: true},
+		},
+	}
+	compiled, err := CompileSeriesFilter(`requestedByGroup("roommates") and watchedByAllOf("roommates") and not watchedByAnyOf("family")`)
+	if err != nil {
+		t.Fatalf("failed to compile series filter: %v", err)
+	}
+	if !compiled.EvaluateSeries(series) {
+		t.Error("expected series group helpers to match")
+	}
+}
+
+func TestWatchThreshold(t *testing.T) {
+	resolver := identity.NewResolver()
+	resolver.Link("alice", "Alice Smith")
+	SetIdentities(resolver)
+	defer SetIdentities(nil)
+	SetWatchThreshold(watch.Threshold{MinPercent: 80, Users: map[string]float64{"alice": 95}})
+	defer watchThreshold.Store(nil)
+
+	movie := radarr.MovieInfo{
+		Title:       "Heat",
+		Watched:     true,
+		IsRequested: true,
+		RequestedBy: "Alice Smith",
+		UserWatchData: map[string]*radarr.UserWatchInfo{
+			"alice": {Username: "alice", Watched: true, WatchCount: 1, MaxProgress: 90},
+			"bob":   {Username: "bob", Watched: true, WatchCount: 1, MaxProgress: 85},
+		},
+	}
+
+	tests := []struct {
+		expression      string
+		minWatchPercent float64
+		expected        bool
+	}{
+		{`watchedBy("bob")`, 0, true},
+		{`watchedBy("alice")`, 0, false},
+		{`watchedByRequester()`, 0, false},
+		{`notWatchedByRequester()`, 0, true},
+		{`watchedAtLeast("alice", 90) and not watchedAtLeast("alice", 91)`, 0, true},
+		{`watchedAtLeast("Alice Smith", 50)`, 0, true},
+		{`watchedAtLeast("carol", 0)`, 0, false},
+		// A filter's own threshold applies to every user
+		{`watchedByRequester()`, 90, true},
+		{`watchedBy("bob")`, 90, false},
+		{`Watched`, 90, true},
+		{`Watched`, 95, false},
+	}
+
+	for _, tt := range tests {
+		compiled, err := CompileFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		filter := WithMinWatchPercent(compiled, tt.minWatchPercent)
+		if result := filter.Evaluate(movie); result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q at %v%%", tt.expected, result, tt.expression, tt.minWatchPercent)
+		}
+	}
+}
+
+func TestSeriesFilter(t *testing.T) {
+	series := sonarr.SeriesInfo{
+		Title:            "The Wire",
+		TagNames:         []string{"hbo"},
+		Genres:           []string{"Drama"},
+		Ended:            true,
+		SizeBytes:        30 << 30,
+		EpisodeFileCount: 23,
+		Added:            time.Now().AddDate(-2, 0, 0),
+		IsRequested:      true,
+		RequestedBy:      "alice",
+		UserWatchData: map[string]*sonarr.UserWatchInfo{
+			"alice": {Username: "alice", EpisodesWatched: 13},
+		},
+	}
+	watched := sonarr.SeasonInfo{Number: 1, SizeBytes: 18 << 30, EpisodeFileCount: 13, Watched: true, EpisodesWatched: 13}
+	unwatched := sonarr.SeasonInfo{Number: 2, SizeBytes: 12 << 30, EpisodeFileCount: 10}
+
+	tests := []struct {
+		expression string
+		season     *sonarr.SeasonInfo
+		expected   bool
+	}{
+		{`Ended and SizeGB > 25 and hasGenre("drama") and hasTag("HBO")`, nil, true},
+		{`notWatchedByRequester() and episodesWatchedBy("alice") == 13`, nil, true},
+		{`Added < yearsAgo(1) and SeasonNumber == 0`, nil, true},
+		{`SeasonWatched and SeasonSizeGB > 15`, &watched, true},
+		{`SeasonWatched`, &unwatched, false},
+		{`SeasonEpisodesWatched == 0 and SeasonNumber == 2`, &unwatched, true},
+	}
+
+	for _, tt := range tests {
+		compiled, err := CompileSeriesFilter(tt.expression)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", tt.expression, err)
+		}
+		var result bool
+		if tt.season != nil {
+			result = compiled.EvaluateSeason(series, *tt.season)
+		} else {
+			result = compiled.EvaluateSeries(series)
+		}
+		if result != tt.expected {
+			t.Errorf("expected %v but got %v for expression %q", tt.expected, result, tt.expression)
+		}
+	}
+
+	// Movie-only identifiers are rejected at compile time
+	if _, err := CompileSeriesFilter(`imdbRating() > 5`); err == nil {
+		t.Error("expected error for movie helper in series filter")
+	}
+}
+
 func TestConcurrentEvaluation(t *testing.T) {
 	// Generate test data
 	movies := generateTestMovies(1000)
@@ -235,6 +770,57 @@ func TestBatchEvaluation(t *testing.T) {
 	}
 }
 
+func TestBatchEvaluationMoreFiltersThanWorkers(t *testing.T) {
+	// Enough movies that a single filter would be chunked through the pool
+	movies := generateTestMovies(1000)
+
+	filters := make(map[string]CompiledFilter)
+	for i := range 16 {
+		compiled, err := CompileFilter(fmt.Sprintf(`Year > %d`, 2000+i))
+		if err != nil {
+			t.Fatalf("failed to compile filter: %v", err)
+		}
+		filters[fmt.Sprintf("filter%d", i)] = compiled
+	}
+
+	evaluator := NewConcurrentEvaluator(WithWorkers(2))
+	defer evaluator.Stop(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	var results map[string][]radarr.MovieInfo
+	var err error
+	go func() {
+		results, err = evaluator.EvaluateBatch(ctx, filters, movies)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("batch evaluation did not finish with more filters than workers")
+	}
+	if err != nil {
+		t.Fatalf("batch evaluation failed: %v", err)
+	}
+	if len(results) != len(filters) {
+		t.Fatalf("expected %d filter results but got %d", len(filters), len(results))
+	}
+	for name, compiled := range filters {
+		var expected int
+		for _, movie := range movies {
+			if compiled.Evaluate(movie) {
+				expected++
+			}
+		}
+		if len(results[name]) != expected {
+			t.Errorf("filter %q: expected %d matches but got %d", name, expected, len(results[name]))
+		}
+	}
+}
+
 func TestFilterManager(t *testing.T) {
 	manager := NewManager()
 	ctx := context.Background()
@@ -284,6 +870,142 @@ func TestFilterManager(t *testing.T) {
 	}
 }
 
+func TestFilterManagerProtections(t *testing.T) {
+	manager := NewManager()
+	defer manager.Close(context.Background())
+
+	err := manager.RegisterProtections(map[string]string{
+		"favourites": `hasTag("keep")`,
+		"classics":   `imdbRating() >= 8`,
+	})
+	if err != nil {
+		t.Fatalf("failed to register protections: %v", err)
+	}
+
+	movies := []radarr.MovieInfo{
+		{ID: 1, Title: "Kept", TagNames: []string{"keep"}, Ratings: map[string]float64{"imdb": 9}},
+		{ID: 2, Title: "Classic", Ratings: map[string]float64{"imdb": 8.2}},
+		{ID: 3, Title: "Forgettable", Ratings: map[string]float64{"imdb": 5}},
+	}
+
+	allowed, protected := manager.ApplyProtections(movies)
+	if len(allowed) != 1 || allowed[0].ID != 3 {
+		t.Errorf("expected only movie 3 to remain, got %+v", allowed)
+	}
+	if len(protected) != 2 {
+		t.Fatalf("expected 2 protected movies, got %d", len(protected))
+	}
+	// Rules are checked in name order, so the first match is reported
+	if protected[0].Rule != "classics" || protected[1].Rule != "classics" {
+		t.Errorf("expected both movies protected by 'classics', got %q and %q", protected[0].Rule, protected[1].Rule)
+	}
+
+	if err := manager.RegisterProtections(map[string]string{"broken": `hasTagg("keep")`}); err == nil {
+		t.Error("expected error for invalid protection rule")
+	}
+
+	// A rule that fails at runtime protects the movie rather than letting it through
+	if err := manager.RegisterProtections(map[string]string{"first_tag": `Tags[0] == "keep"`}); err != nil {
+		t.Fatalf("failed to register protections: %v", err)
+	}
+	allowed, protected = manager.ApplyProtections([]radarr.MovieInfo{
+		{ID: 3, Title: "Forgettable", Ratings: map[string]float64{"imdb": 5}},
+		{ID: 4, Title: "Tagged", TagNames: []string{"other"}, Ratings: map[string]float64{"imdb": 5}},
+	})
+	if len(allowed) != 1 || allowed[0].ID != 4 {
+		t.Errorf("expected only movie 4 to remain, got %+v", allowed)
+	}
+	if len(protected) != 1 || protected[0].Rule != "first_tag" || protected[0].Err == nil {
+		t.Errorf("expected movie 3 protected by the failing rule with its error, got %+v", protected)
+	}
+}
+
+func TestCompileSort(t *testing.T) {
+	now := time.Now()
+	movies := []radarr.MovieInfo{
+		{ID: 1, Title: "Watched Old", Watched: true, Added: now.AddDate(-3, 0, 0)},
+		{ID: 2, Title: "Unwatched New", Added: now.AddDate(0, -1, 0)},
+		{ID: 3, Title: "Unwatched Old", Added: now.AddDate(-2, 0, 0)},
+		{ID: 4, Title: "Watched New", Watched: true, Added: now},
+	}
+
+	sorter, err := CompileSort([]string{"Watched", "Added"})
+	if err != nil {
+		t.Fatalf("failed to compile sort: %v", err)
+	}
+	sorter.Sort(movies)
+
+	want := []int64{3, 2, 1, 4}
+	for i, movie := range movies {
+		if movie.ID != want[i] {
+			t.Fatalf("position %d: expected movie %d, got %d (%s)", i, want[i], movie.ID, movie.Title)
+		}
+	}
+
+	sorter, err = CompileSort([]string{"imdbRating() DESC"})
+	if err != nil {
+		t.Fatalf("failed to compile descending sort: %v", err)
+	}
+	rated := []radarr.MovieInfo{
+		{ID: 1, Ratings: map[string]float64{"imdb": 5}},
+		{ID: 2, Ratings: map[string]float64{"imdb": 8}},
+	}
+	sorter.Sort(rated)
+	if rated[0].ID != 2 {
+		t.Errorf("expected highest rating first, got movie %d", rated[0].ID)
+	}
+
+	if _, err := CompileSort([]string{"Tags"}); err == nil {
+		t.Error("expected error for a sort expression returning a list")
+	}
+	if _, err := CompileSort([]string{"Unknown"}); err == nil {
+		t.Error("expected error for an unknown identifier")
+	}
+}
+
+func TestRank(t *testing.T) {
+	unwatched, err := CompileScore(`daysSince(Added) / 10`)
+	if err != nil {
+		t.Fatalf("failed to compile score: %v", err)
+	}
+	lowRated, err := CompileScore(`10 - imdbRating()`)
+	if err != nil {
+		t.Fatalf("failed to compile score: %v", err)
+	}
+
+	now := time.Now()
+	old := radarr.MovieInfo{ID: 1, Title: "Old", Added: now.AddDate(0, 0, -100), Ratings: map[string]float64{"imdb": 7}}
+	bad := radarr.MovieInfo{ID: 2, Title: "Bad", Added: now.AddDate(0, 0, -20), Ratings: map[string]float64{"imdb": 3}}
+	plain := radarr.MovieInfo{ID: 3, Title: "Plain", Added: now}
+
+	ranked := Rank(
+		map[string]CompiledScore{"unwatched": unwatched, "low_rated": lowRated},
+		map[string][]radarr.MovieInfo{
+			"unwatched": {old, bad},
+			"low_rated": {bad},
+			"unscored":  {plain},
+		},
+	)
+
+	if len(ranked) != 3 {
+		t.Fatalf("expected 3 ranked movies, got %d", len(ranked))
+	}
+	// Old: 10, Bad: 2 + 7 = 9, Plain: no scored filter
+	if ranked[0].Movie.ID != 1 || ranked[0].Score != 10 {
+		t.Errorf("expected Old first with score 10, got %s with %v", ranked[0].Movie.Title, ranked[0].Score)
+	}
+	if ranked[1].Movie.ID != 2 || ranked[1].Score != 9 || len(ranked[1].Scores) != 2 {
+		t.Errorf("expected Bad second with combined score 9, got %s with %v (%v)", ranked[1].Movie.Title, ranked[1].Score, ranked[1].Scores)
+	}
+	if ranked[2].Movie.ID != 3 || ranked[2].Score != 0 {
+		t.Errorf("expected Plain last with score 0, got %s with %v", ranked[2].Movie.Title, ranked[2].Score)
+	}
+
+	if _, err := CompileScore(`Title`); err == nil {
+		t.Error("expected error for a non-numeric score")
+	}
+}
+
 
 func TestCacheEffectiveness(t *testing.T) {
 	compiler := NewExprCompiler(WithCache(10))
@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"time"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// groupMembers returns the members of a configured user group
+func groupMembers(group string) []string {
+	return identities.Load().Group(group)
+}
+
+func createRequestedByGroupFunc(isRequested bool, requestedBy string) func(string) bool {
+	return func(group string) bool {
+		if !isRequested || requestedBy == "" {
+			return false
+		}
+		for _, member := range groupMembers(group) {
+			if sameUser(requestedBy, member) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func createWatchedByAnyOfFunc(watchData map[string]*radarr.UserWatchInfo, minWatchPercent float64) func(string) bool {
+	return func(group string) bool {
+		for _, member := range groupMembers(group) {
+			if userData, exists := userWatch(watchData, member); exists && userWatched(userData, member, minWatchPercent) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func createWatchedByAllOfFunc(watchData map[string]*radarr.UserWatchInfo, minWatchPercent float64) func(string) bool {
+	return func(group string) bool {
+		members := groupMembers(group)
+		for _, member := range members {
+			if userData, exists := userWatch(watchData, member); !exists || !userWatched(userData, member, minWatchPercent) {
+				return false
+			}
+		}
+		return len(members) > 0
+	}
+}
+
+func createLastWatchedByAnyOfFunc(watchData map[string]*radarr.UserWatchInfo) func(string) time.Time {
+	return func(group string) time.Time {
+		var last time.Time
+		for _, member := range groupMembers(group) {
+			if userData, exists := userWatch(watchData, member); exists && userData.LastWatched.After(last) {
+				last = userData.LastWatched
+			}
+		}
+		return last
+	}
+}
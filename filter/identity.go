@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"sync/atomic"
+
+	"github.com/s0up4200/arrbiter/identity"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// identities links the names users have in Overseerr, Tautulli and the
+// media servers. Without one, user names are compared case-insensitively.
+var identities atomic.Pointer[identity.Resolver]
+
+// SetIdentities sets the resolver every user-aware helper compares names with
+func SetIdentities(resolver *identity.Resolver) {
+	identities.Store(resolver)
+}
+
+// sameUser reports whether two names belong to the same user
+func sameUser(a, b string) bool {
+	return identities.Load().Same(a, b)
+}
+
+// userWatch returns a user's watch data for a movie. A user watching on
+// several media servers has an entry under each name, which are combined.
+func userWatch(watchData map[string]*radarr.UserWatchInfo, username string) (*radarr.UserWatchInfo, bool) {
+	var merged *radarr.UserWatchInfo
+	for name, userData := range watchData {
+		if !sameUser(name, username) {
+			continue
+		}
+		if merged == nil {
+			copied := *userData
+			merged = &copied
+			continue
+		}
+		merged.Watched = merged.Watched || userData.Watched
+		merged.WatchCount += userData.WatchCount
+		merged.MaxProgress = max(merged.MaxProgress, userData.MaxProgress)
+		if userData.LastWatched.After(merged.LastWatched) {
+			merged.LastWatched = userData.LastWatched
+		}
+	}
+	return merged, merged != nil
+}
+
+// lookupUser finds the watch data of a user, whichever of their names the
+// watch history provider knows them by
+func lookupUser[T any](data map[string]T, username string) (T, bool) {
+	if userData, exists := data[username]; exists {
+		return userData, true
+	}
+	for name, userData := range data {
+		if sameUser(name, username) {
+			return userData, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// AnnotateInstances records on every movie which Radarr instances have the same
+// movie with a file, matched by TMDB ID, so expressions can compare copies across
+// instances. Copies that are missing or still downloading don't count. Movies
+// are updated in place.
+func AnnotateInstances(movies []radarr.MovieInfo) {
+	instances := make(map[int64]map[string]bool)
+	for _, movie := range movies {
+		if movie.TMDBID == 0 || !movie.HasFile {
+			continue
+		}
+		if instances[movie.TMDBID] == nil {
+			instances[movie.TMDBID] = make(map[string]bool)
+		}
+		instances[movie.TMDBID][movie.Instance] = true
+	}
+
+	for i := range movies {
+		switch names, ok := instances[movies[i].TMDBID]; {
+		case ok:
+			movies[i].Instances = slices.Sorted(maps.Keys(names))
+		case movies[i].HasFile:
+			movies[i].Instances = []string{movies[i].Instance}
+		default:
+			movies[i].Instances = nil
+		}
+	}
+}
+
+func createExistsInInstanceFunc(instances []string) func(string) bool {
+	return func(name string) bool {
+		return slices.Contains(instances, name)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/sonarr"
 )
 
 // Filter defines the basic interface for movie filters
@@ -23,12 +24,52 @@ type CompiledFilter interface {
 	IsThreadSafe() bool
 }
 
+// CompiledSeriesFilter is a pre-compiled filter over Sonarr series or single seasons
+type CompiledSeriesFilter interface {
+	// EvaluateSeries checks if a whole series matches the filter criteria
+	EvaluateSeries(series sonarr.SeriesInfo) bool
+
+	// EvaluateSeason checks if one season of a series matches the filter criteria
+	EvaluateSeason(series sonarr.SeriesInfo, season sonarr.SeasonInfo) bool
+
+	// Expression returns the original filter expression
+	Expression() string
+}
+
+// Matcher evaluates a filter and reports runtime errors rather than treating
+// them as no match
+type Matcher interface {
+	// Match checks if a movie matches the filter criteria
+	Match(movie radarr.MovieInfo) (bool, error)
+}
+
+// Explainer breaks a filter down into its evaluated sub-expressions
+type Explainer interface {
+	// Explain evaluates every sub-expression of the filter against a movie
+	Explain(movie radarr.MovieInfo) ([]ExplainStep, error)
+}
+
 // Compiler compiles filter expressions into executable filters
 type Compiler interface {
 	// Compile parses and compiles a filter expression
 	Compile(expression string) (CompiledFilter, error)
 }
 
+// CompiledScore is a pre-compiled numeric expression rating how deletable a movie is
+type CompiledScore interface {
+	// Score evaluates the expression against a movie
+	Score(movie radarr.MovieInfo) (float64, error)
+
+	// Expression returns the original score expression
+	Expression() string
+}
+
+// ScoreCompiler compiles numeric score expressions
+type ScoreCompiler interface {
+	// CompileScore parses and compiles a score expression
+	CompileScore(expression string) (CompiledScore, error)
+}
+
 // Evaluator evaluates filters against movies
 type Evaluator interface {
 	// Evaluate evaluates a filter against all movies
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"sync"
 
 	"github.com/s0up4200/arrbiter/radarr"
@@ -14,9 +15,17 @@ type Manager struct {
 	compiler  Compiler
 	evaluator *ConcurrentEvaluator
 	filters   map[string]CompiledFilter
+	protect   map[string]CompiledFilter
 	mu        sync.RWMutex
 }
 
+// ProtectedMovie is a movie that was removed from a result set by a protection rule
+type ProtectedMovie struct {
+	Movie radarr.MovieInfo
+	Rule  string
+	Err   error // Set when the rule failed to evaluate, which protects the movie to be safe
+}
+
 // ManagerOption configures a filter manager
 type ManagerOption func(*Manager)
 
@@ -40,6 +49,7 @@ func NewManager(opts ...ManagerOption) *Manager {
 		compiler:  NewExprCompiler(WithCache(100)),
 		evaluator: NewConcurrentEvaluator(),
 		filters:   make(map[string]CompiledFilter),
+		protect:   make(map[string]CompiledFilter),
 	}
 
 	for _, opt := range opts {
@@ -84,6 +94,19 @@ func (m *Manager) RegisterFilters(filters map[string]string) error {
 	return nil
 }
 
+// SetMinWatchPercent makes a registered filter judge watches by its own threshold
+func (m *Manager) SetMinWatchPercent(name string, percent float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	filter, exists := m.filters[name]
+	if !exists {
+		return fmt.Errorf("filter '%s' not found", name)
+	}
+	m.filters[name] = WithMinWatchPercent(filter, percent)
+	return nil
+}
+
 // UnregisterFilter removes a filter
 func (m *Manager) UnregisterFilter(name string) {
 	m.mu.Lock()
@@ -121,8 +144,12 @@ func (m *Manager) EvaluateFilter(ctx context.Context, name string, movies []rada
 	return m.evaluator.Evaluate(ctx, filter, movies)
 }
 
-// EvaluateAll evaluates all registered filters
+// EvaluateAll evaluates all registered filters. Movies are first annotated in
+// place with their collection summary, so movies should be the whole library.
 func (m *Manager) EvaluateAll(ctx context.Context, movies []radarr.MovieInfo) (map[string][]radarr.MovieInfo, error) {
+	AnnotateCollections(movies)
+	AnnotateInstances(movies)
+
 	m.mu.RLock()
 	filters := make(map[string]CompiledFilter, len(m.filters))
 	maps.Copy(filters, m.filters)
@@ -148,6 +175,83 @@ func (m *Manager) EvaluateSelected(ctx context.Context, filterNames []string, mo
 	return m.evaluator.EvaluateBatch(ctx, filters, movies)
 }
 
+// RegisterProtections registers protection rules. A movie matching any rule is
+// removed by ApplyProtections no matter which filters matched it.
+func (m *Manager) RegisterProtections(rules map[string]string) error {
+	compiled := make(map[string]CompiledFilter, len(rules))
+
+	for name, expr := range rules {
+		rule, err := m.compiler.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("failed to compile protection rule '%s': %w", name, err)
+		}
+		compiled[name] = rule
+	}
+
+	m.mu.Lock()
+	maps.Copy(m.protect, compiled)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// ApplyProtections splits movies into those not covered by any protection rule
+// and those that are, reporting the first matching rule by name for each. A
+// rule that fails to evaluate protects the movie when no other rule matches.
+func (m *Manager) ApplyProtections(movies []radarr.MovieInfo) ([]radarr.MovieInfo, []ProtectedMovie) {
+	m.mu.RLock()
+	rules := make(map[string]CompiledFilter, len(m.protect))
+	maps.Copy(rules, m.protect)
+	m.mu.RUnlock()
+
+	if len(rules) == 0 {
+		return movies, nil
+	}
+
+	ruleNames := slices.Sorted(maps.Keys(rules))
+
+	var allowed []radarr.MovieInfo
+	var protected []ProtectedMovie
+	for _, movie := range movies {
+		rule, ok, err := m.protectedBy(movie, ruleNames, rules)
+		if ok {
+			protected = append(protected, ProtectedMovie{Movie: movie, Rule: rule, Err: err})
+		} else {
+			allowed = append(allowed, movie)
+		}
+	}
+
+	return allowed, protected
+}
+
+// protectedBy returns the first rule that protects a movie, falling back to
+// the first rule that failed to evaluate along with its error
+func (m *Manager) protectedBy(movie radarr.MovieInfo, ruleNames []string, rules map[string]CompiledFilter) (string, bool, error) {
+	var failedRule string
+	var failedErr error
+	for _, name := range ruleNames {
+		matcher, ok := rules[name].(Matcher)
+		if !ok {
+			if rules[name].Evaluate(movie) {
+				return name, true, nil
+			}
+			continue
+		}
+
+		matched, err := matcher.Match(movie)
+		if err != nil {
+			if failedErr == nil {
+				failedRule, failedErr = name, err
+			}
+			continue
+		}
+		if matched {
+			return name, true, nil
+		}
+	}
+	return failedRule, failedErr != nil, failedErr
+}
+
 // Close gracefully shuts down the manager
 func (m *Manager) Close(ctx context.Context) error {
 	return m.evaluator.Stop(ctx)
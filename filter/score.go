@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// exprScore implements CompiledScore using the expr language
+type exprScore struct {
+	expression string
+	program    *vm.Program
+}
+
+// RankedMovie is a movie with the combined score of every filter that matched it
+type RankedMovie struct {
+	Movie  radarr.MovieInfo
+	Score  float64
+	Scores map[string]float64 // Score per matching filter
+}
+
+// CompileScore compiles a score expression, which must evaluate to a number
+func (c *exprCompiler) CompileScore(expression string) (CompiledScore, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, &CompilationError{
+			Expression: expression,
+			Reason:     "empty expression",
+			Position:   -1,
+		}
+	}
+
+	program, _, err := c.compileProgram(expression, expr.AsFloat64())
+	if err != nil {
+		return nil, err
+	}
+
+	return &exprScore{expression: expression, program: program}, nil
+}
+
+// Score evaluates the score expression against a movie
+func (s *exprScore) Score(movie radarr.MovieInfo) (float64, error) {
+	result, err := expr.Run(s.program, createRuntimeEnvironment(movie, 0))
+	if err != nil {
+		return 0, err
+	}
+
+	// Result is guaranteed to be float64 due to AsFloat64() option during compilation
+	return result.(float64), nil
+}
+
+// Expression returns the original expression
+func (s *exprScore) Expression() string {
+	return s.expression
+}
+
+// CompileScore compiles a score expression using the default compiler
+func CompileScore(expression string) (CompiledScore, error) {
+	initDefaults()
+
+	compiler, ok := defaultCompiler.(ScoreCompiler)
+	if !ok {
+		return nil, fmt.Errorf("compiler does not support score expressions")
+	}
+
+	return compiler.CompileScore(expression)
+}
+
+// Rank combines the scores of every filter that matched each movie and orders
+// the movies from highest to lowest score. Filters without a score expression
+// contribute nothing; a score that fails to evaluate counts as 0.
+func Rank(scores map[string]CompiledScore, matchesByFilter map[string][]radarr.MovieInfo) []RankedMovie {
+	byKey := make(map[radarr.MovieKey]*RankedMovie)
+	var order []radarr.MovieKey
+
+	for _, filterName := range slices.Sorted(maps.Keys(matchesByFilter)) {
+		score := scores[filterName]
+		for _, movie := range matchesByFilter[filterName] {
+			ranked, ok := byKey[movie.Key()]
+			if !ok {
+				ranked = &RankedMovie{Movie: movie, Scores: make(map[string]float64)}
+				byKey[movie.Key()] = ranked
+				order = append(order, movie.Key())
+			}
+			if score == nil {
+				continue
+			}
+			value, _ := score.Score(movie)
+			ranked.Scores[filterName] = value
+			ranked.Score += value
+		}
+	}
+
+	ranked := make([]RankedMovie, 0, len(order))
+	for _, key := range order {
+		ranked = append(ranked, *byKey[key])
+	}
+	slices.SortStableFunc(ranked, func(a, b RankedMovie) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Movie.Title, b.Movie.Title)
+	})
+
+	return ranked
+}
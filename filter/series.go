@@ -0,0 +1,298 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+
+	"github.com/s0up4200/arrbiter/sonarr"
+)
+
+// SeriesEnv is the typed environment series filter expressions are compiled and
+// evaluated against. Season fields are only set when a filter is evaluated
+// against a single season; for whole series they are zero.
+type SeriesEnv struct {
+	// Full series record for fields not promoted below
+	Series sonarr.SeriesInfo
+
+	// Series properties
+	Title          string
+	Year           int
+	Tags           []string
+	TagNames       []string
+	Added          time.Time
+	Path           string
+	RootFolder     string
+	TVDBID         int64
+	IMDBID         string
+	Monitored      bool
+	QualityProfile string
+
+	// Sonarr metadata
+	Status         string // "continuing", "ended", "upcoming"
+	Ended          bool
+	Network        string
+	SeriesType     string // "standard", "daily", "anime"
+	Genres         []string
+	Certification  string
+	Runtime        int // Minutes per episode
+	Rating         float64
+	FirstAired     time.Time
+	PreviousAiring time.Time
+	NextAiring     time.Time
+
+	// File statistics
+	SizeBytes         int64
+	SizeGB            float64
+	SeasonCount       int
+	EpisodeCount      int
+	EpisodeFileCount  int
+	TotalEpisodeCount int
+
+	// Watch properties, episodes on disk count as watched once any play passes the threshold
+	Watched         bool
+	WatchCount      int
+	LastWatched     time.Time
+	EpisodesWatched int
+	UserWatchData   map[string]*sonarr.UserWatchInfo
+
+	// Request properties
+	RequestedBy      string
+	RequestedByEmail string
+	RequestDate      time.Time
+	RequestStatus    string
+	ApprovedBy       string
+	IsAutoRequest    bool
+	IsRequested      bool
+
+	// Season properties, only set for season-level filters
+	SeasonNumber           int
+	SeasonMonitored        bool
+	SeasonSizeBytes        int64
+	SeasonSizeGB           float64
+	SeasonEpisodeCount     int
+	SeasonEpisodeFileCount int
+	SeasonPreviousAiring   time.Time
+	SeasonWatched          bool
+	SeasonWatchCount       int
+	SeasonLastWatched      time.Time
+	SeasonEpisodesWatched  int
+	SeasonRequested        bool
+
+	// Date helpers
+	DaysSinceFn func(time.Time) int    `expr:"daysSince"`
+	DaysAgoFn   func(int) time.Time    `expr:"daysAgo"`
+	MonthsAgoFn func(int) time.Time    `expr:"monthsAgo"`
+	YearsAgoFn  func(int) time.Time    `expr:"yearsAgo"`
+	ParseDateFn func(string) time.Time `expr:"parseDate"`
+	NowFn       func() time.Time       `expr:"now"`
+
+	// String helpers
+	ContainsFn   func(string, string) bool `expr:"contains"`
+	StartsWithFn func(string, string) bool `expr:"startsWith"`
+	EndsWithFn   func(string, string) bool `expr:"endsWith"`
+	LowerFn      func(string) string       `expr:"lower"`
+	UpperFn      func(string) string       `expr:"upper"`
+
+	// Metadata helpers
+	HasTagFn   func(string) bool `expr:"hasTag"`
+	HasGenreFn func(string) bool `expr:"hasGenre"`
+
+	// Watch helpers
+	WatchedByFn         func(string) bool `expr:"watchedBy"`
+	EpisodesWatchedByFn func(string) int  `expr:"episodesWatchedBy"`
+
+	// Request helpers
+	RequestedByFn           func(string) bool `expr:"requestedBy"`
+	IsRequestedFn           func() bool       `expr:"isRequested"`
+	NotRequestedFn          func() bool       `expr:"notRequested"`
+	NotWatchedByRequesterFn func() bool       `expr:"notWatchedByRequester"`
+	WatchedByRequesterFn    func() bool       `expr:"watchedByRequester"`
+
+	// Group helpers
+	RequestedByGroupFn func(string) bool `expr:"requestedByGroup"`
+	WatchedByAnyOfFn   func(string) bool `expr:"watchedByAnyOf"`
+	WatchedByAllOfFn   func(string) bool `expr:"watchedByAllOf"`
+}
+
+// seriesFilter implements CompiledSeriesFilter using the expr language
+type seriesFilter struct {
+	expression string
+	program    *vm.Program
+}
+
+// CompileSeriesFilter compiles a series filter expression against the series environment
+func CompileSeriesFilter(expression string) (CompiledSeriesFilter, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, &CompilationError{
+			Expression: expression,
+			Reason:     "empty expression",
+			Position:   -1,
+		}
+	}
+
+	program, err := expr.Compile(expression, expr.Env(SeriesEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, newCompilationError(expression, err)
+	}
+
+	return &seriesFilter{expression: expression, program: program}, nil
+}
+
+// EvaluateSeries evaluates the filter against a whole series
+func (f *seriesFilter) EvaluateSeries(series sonarr.SeriesInfo) bool {
+	return f.run(createSeriesEnvironment(series, nil))
+}
+
+// EvaluateSeason evaluates the filter against one season of a series
+func (f *seriesFilter) EvaluateSeason(series sonarr.SeriesInfo, season sonarr.SeasonInfo) bool {
+	return f.run(createSeriesEnvironment(series, &season))
+}
+
+// run evaluates the program, treating runtime errors as no match
+func (f *seriesFilter) run(env *SeriesEnv) bool {
+	result, err := expr.Run(f.program, env)
+	if err != nil {
+		return false
+	}
+
+	// Result is guaranteed to be bool due to AsBool() option during compilation
+	return result.(bool)
+}
+
+// Expression returns the original expression
+func (f *seriesFilter) Expression() string {
+	return f.expression
+}
+
+// createSeriesEnvironment creates the runtime environment for a series, or for
+// one of its seasons when season is not nil
+func createSeriesEnvironment(series sonarr.SeriesInfo, season *sonarr.SeasonInfo) *SeriesEnv {
+	env := &SeriesEnv{
+		Series: series,
+
+		// Series properties
+		Title:          series.Title,
+		Year:           series.Year,
+		Tags:           series.TagNames,
+		TagNames:       series.TagNames,
+		Added:          series.Added,
+		Path:           series.Path,
+		RootFolder:     series.RootFolder,
+		TVDBID:         series.TVDBID,
+		IMDBID:         series.IMDBID,
+		Monitored:      series.Monitored,
+		QualityProfile: series.QualityProfile,
+		// Sonarr metadata
+		Status:         series.Status,
+		Ended:          series.Ended,
+		Network:        series.Network,
+		SeriesType:     series.SeriesType,
+		Genres:         series.Genres,
+		Certification:  series.Certification,
+		Runtime:        series.Runtime,
+		Rating:         series.Rating,
+		FirstAired:     series.FirstAired,
+		PreviousAiring: series.PreviousAiring,
+		NextAiring:     series.NextAiring,
+		// File statistics
+		SizeBytes:         series.SizeBytes,
+		SizeGB:            float64(series.SizeBytes) / (1 << 30),
+		SeasonCount:       len(series.Seasons),
+		EpisodeCount:      series.EpisodeCount,
+		EpisodeFileCount:  series.EpisodeFileCount,
+		TotalEpisodeCount: series.TotalEpisodeCount,
+		// Watch properties
+		Watched:         series.Watched,
+		WatchCount:      series.WatchCount,
+		LastWatched:     series.LastWatched,
+		EpisodesWatched: series.EpisodesWatched,
+		UserWatchData:   series.UserWatchData,
+		// Request properties
+		RequestedBy:      series.RequestedBy,
+		RequestedByEmail: series.RequestedByEmail,
+		RequestDate:      series.RequestDate,
+		RequestStatus:    series.RequestStatus,
+		ApprovedBy:       series.ApprovedBy,
+		IsAutoRequest:    series.IsAutoRequest,
+		IsRequested:      series.IsRequested,
+	}
+
+	if season != nil {
+		env.SeasonNumber = season.Number
+		env.SeasonMonitored = season.Monitored
+		env.SeasonSizeBytes = season.SizeBytes
+		env.SeasonSizeGB = float64(season.SizeBytes) / (1 << 30)
+		env.SeasonEpisodeCount = season.EpisodeCount
+		env.SeasonEpisodeFileCount = season.EpisodeFileCount
+		env.SeasonPreviousAiring = season.PreviousAiring
+		env.SeasonWatched = season.Watched
+		env.SeasonWatchCount = season.WatchCount
+		env.SeasonLastWatched = season.LastWatched
+		env.SeasonEpisodesWatched = season.EpisodesWatched
+		env.SeasonRequested = season.IsRequested
+	}
+
+	// Date helpers
+	env.DaysSinceFn = daysSince
+	env.DaysAgoFn = daysAgo
+	env.MonthsAgoFn = monthsAgo
+	env.YearsAgoFn = yearsAgo
+	env.ParseDateFn = parseDate
+	env.NowFn = time.Now
+	// String helpers
+	env.ContainsFn = containsFold
+	env.StartsWithFn = startsWithFold
+	env.EndsWithFn = endsWithFold
+	env.LowerFn = strings.ToLower
+	env.UpperFn = strings.ToUpper
+
+	// Series-specific helpers using closures
+	env.HasTagFn = createHasTagFunc(series.TagNames)
+	env.HasGenreFn = createHasTagFunc(series.Genres)
+	env.WatchedByFn = createSeriesWatchedByFunc(series.UserWatchData)
+	env.EpisodesWatchedByFn = createEpisodesWatchedByFunc(series.UserWatchData)
+	env.RequestedByFn = createRequestedByFunc(series.IsRequested, series.RequestedBy)
+	env.IsRequestedFn = createIsRequestedFunc(series.IsRequested)
+	env.NotRequestedFn = createNotRequestedFunc(series.IsRequested)
+	env.WatchedByRequesterFn = func() bool {
+		return series.IsRequested && series.RequestedBy != "" && env.WatchedByFn(series.RequestedBy)
+	}
+	env.NotWatchedByRequesterFn = func() bool {
+		return series.IsRequested && series.RequestedBy != "" && !env.WatchedByFn(series.RequestedBy)
+	}
+	env.RequestedByGroupFn = createRequestedByGroupFunc(series.IsRequested, series.RequestedBy)
+	env.WatchedByAnyOfFn = func(group string) bool {
+		return slices.ContainsFunc(groupMembers(group), env.WatchedByFn)
+	}
+	env.WatchedByAllOfFn = func(group string) bool {
+		members := groupMembers(group)
+		return len(members) > 0 && !slices.ContainsFunc(members, func(member string) bool {
+			return !env.WatchedByFn(member)
+		})
+	}
+
+	return env
+}
+
+func createSeriesWatchedByFunc(watchData map[string]*sonarr.UserWatchInfo) func(string) bool {
+	return func(username string) bool {
+		if userData, exists := lookupUser(watchData, username); exists {
+			return userData.Watched
+		}
+		return false
+	}
+}
+
+func createEpisodesWatchedByFunc(watchData map[string]*sonarr.UserWatchInfo) func(string) int {
+	return func(username string) int {
+		if userData, exists := lookupUser(watchData, username); exists {
+			return userData.EpisodesWatched
+		}
+		return 0
+	}
+}
@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// Sorter orders movies by one or more expressions, each ascending unless
+// suffixed with " desc", e.g. "Watched", "LastWatched", "imdbRating() desc"
+type Sorter struct {
+	keys []sortKey
+}
+
+// sortKey is a single compiled sort expression
+type sortKey struct {
+	expression string
+	program    *vm.Program
+	descending bool
+}
+
+// CompileSort compiles sort expressions. Each must evaluate to a bool, number,
+// string, date or duration; false sorts before true.
+func CompileSort(expressions []string) (*Sorter, error) {
+	initDefaults()
+
+	compiler, ok := defaultCompiler.(*exprCompiler)
+	if !ok {
+		return nil, fmt.Errorf("sorting requires the expr compiler")
+	}
+
+	sorter := &Sorter{}
+	for _, expression := range expressions {
+		key := sortKey{expression: strings.TrimSpace(expression)}
+		if rest, found := cutSuffixFold(key.expression, " desc"); found {
+			key.expression, key.descending = strings.TrimSpace(rest), true
+		} else if rest, found := cutSuffixFold(key.expression, " asc"); found {
+			key.expression = strings.TrimSpace(rest)
+		}
+		if key.expression == "" {
+			return nil, &CompilationError{Expression: expression, Reason: "empty sort expression", Position: -1}
+		}
+
+		program, _, err := compiler.compileProgram(key.expression)
+		if err != nil {
+			return nil, err
+		}
+		if t := program.Node().Type(); t != nil && !sortable(t) {
+			return nil, &CompilationError{
+				Expression: key.expression,
+				Reason:     fmt.Sprintf("sort expression returns %s, expected a bool, number, string, date or duration", t),
+				Position:   -1,
+			}
+		}
+		key.program = program
+
+		sorter.keys = append(sorter.keys, key)
+	}
+
+	return sorter, nil
+}
+
+// Sort stably orders movies in place. Movies an expression fails on sort last for that key.
+func (s *Sorter) Sort(movies []radarr.MovieInfo) {
+	values := make(map[radarr.MovieKey][]any, len(movies))
+	for _, movie := range movies {
+		env := createRuntimeEnvironment(movie, 0)
+		row := make([]any, len(s.keys))
+		for i, key := range s.keys {
+			if value, err := expr.Run(key.program, env); err == nil {
+				row[i] = value
+			}
+		}
+		values[movie.Key()] = row
+	}
+
+	slices.SortStableFunc(movies, func(a, b radarr.MovieInfo) int {
+		rowA, rowB := values[a.Key()], values[b.Key()]
+		for i, key := range s.keys {
+			c := compareSortValues(rowA[i], rowB[i], key.descending)
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// compareSortValues compares two values of the same sort key. Nil values
+// always sort last, regardless of direction.
+func compareSortValues(a, b any, descending bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	var c int
+	switch x := a.(type) {
+	case bool:
+		y, _ := b.(bool)
+		switch {
+		case x == y:
+			c = 0
+		case !x:
+			c = -1
+		default:
+			c = 1
+		}
+	case string:
+		y, _ := b.(string)
+		c = strings.Compare(x, y)
+	case time.Time:
+		y, _ := b.(time.Time)
+		c = x.Compare(y)
+	default:
+		c = cmp.Compare(toFloat(a), toFloat(b))
+	}
+
+	if descending {
+		return -c
+	}
+	return c
+}
+
+// sortable reports whether values of a type can be used as a sort key
+func sortable(t reflect.Type) bool {
+	if t == reflect.TypeFor[time.Time]() {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// toFloat converts any numeric value to a float64 for comparison
+func toFloat(value any) float64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return 0
+}
+
+// cutSuffixFold is strings.CutSuffix ignoring case
+func cutSuffixFold(s, suffix string) (string, bool) {
+	if len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix) {
+		return s[:len(s)-len(suffix)], true
+	}
+	return s, false
+}
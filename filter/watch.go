@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"sync/atomic"
+
+	"github.com/s0up4200/arrbiter/radarr"
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// watchThreshold is the configured watch threshold. Without one, movies count
+// as watched at watch.DefaultMinPercent.
+var watchThreshold atomic.Pointer[watch.Threshold]
+
+// SetWatchThreshold sets the threshold every watch helper judges users by
+func SetWatchThreshold(threshold watch.Threshold) {
+	watchThreshold.Store(&threshold)
+}
+
+// WithMinWatchPercent returns a copy of a filter whose watch helpers use
+// percent as the threshold for every user. Zero keeps the configured threshold.
+func WithMinWatchPercent(compiled CompiledFilter, percent float64) CompiledFilter {
+	f, ok := compiled.(*exprFilter)
+	if !ok || percent <= 0 {
+		return compiled
+	}
+	copied := *f
+	copied.minWatchPercent = percent
+	return &copied
+}
+
+// minWatchPercentFor returns how much of a movie a user must watch for it to
+// count: the filter's override if it has one, else the user's own threshold,
+// else the default
+func minWatchPercentFor(username string, override float64) float64 {
+	if override > 0 {
+		return override
+	}
+	threshold := watchThreshold.Load()
+	if threshold == nil {
+		return watch.DefaultMinPercent
+	}
+	for name, percent := range threshold.Users {
+		if sameUser(name, username) {
+			return percent
+		}
+	}
+	return threshold.MinPercent
+}
+
+// userWatched reports whether a user's progress on a movie counts as watched
+func userWatched(userData *radarr.UserWatchInfo, username string, override float64) bool {
+	return userData.MaxProgress > 0 && userData.MaxProgress >= minWatchPercentFor(username, override)
+}
+
+// anyUserWatched reports whether any user watched a movie at the override
+func anyUserWatched(watchData map[string]*radarr.UserWatchInfo, override float64) bool {
+	for username, userData := range watchData {
+		if userWatched(userData, username, override) {
+			return true
+		}
+	}
+	return false
+}
+
+func createWatchedAtLeastFunc(watchData map[string]*radarr.UserWatchInfo) func(string, float64) bool {
+	return func(username string, percent float64) bool {
+		if userData, exists := userWatch(watchData, username); exists {
+			return userData.MaxProgress > 0 && userData.MaxProgress >= percent
+		}
+		return false
+	}
+}
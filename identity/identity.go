@@ -0,0 +1,218 @@
+// Package identity links the accounts one person has across services. An
+// Overseerr display name, a Tautulli friendly name, a Plex username and an
+// email address can all belong to the same user; the resolver treats them as
+// one so requester-aware filters compare people rather than strings.
+package identity
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Account is one service's view of a user. Accounts that share an email or a
+// Plex ID are linked to the same identity. Names alone never link accounts,
+// since different people can share one, unless they are configured aliases.
+type Account struct {
+	Names  []string `json:"names"` // Usernames and display names the account is known by
+	Email  string   `json:"email,omitempty"`
+	PlexID int64    `json:"plex_id,omitempty"`
+}
+
+// Resolver maps the names, emails and Plex IDs of accounts to identities.
+// Build it with Link and Add, then share it read-only.
+type Resolver struct {
+	parent    map[string]string   // Union-find over alias keys
+	canonical map[string]nameInfo // Root key to the identity's display name
+	names     map[string][]string // Name key to the accounts known by it, when not configured
+	groups    map[string][]string // Lowercased group name to member names
+	accounts  int                 // Accounts added without an email, Plex ID or alias
+}
+
+// nameInfo is the display name of an identity and whether it was configured
+type nameInfo struct {
+	name       string
+	configured bool
+}
+
+// NewResolver creates an empty resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		parent:    make(map[string]string),
+		canonical: make(map[string]nameInfo),
+		names:     make(map[string][]string),
+		groups:    make(map[string][]string),
+	}
+}
+
+// Link declares that aliases all belong to the user called name. The name
+// stays the identity's display name whatever accounts are linked to it later.
+func (r *Resolver) Link(name string, aliases ...string) {
+	keys := []string{nameKey(name)}
+	for _, alias := range aliases {
+		if strings.Contains(alias, "@") {
+			keys = append(keys, emailKey(alias))
+		}
+		keys = append(keys, nameKey(alias))
+	}
+	// Accounts already added under these names are linked too
+	for _, key := range keys {
+		keys = append(keys, r.names[key]...)
+		delete(r.names, key)
+	}
+	r.union(nameInfo{name: name, configured: true}, keys)
+}
+
+// Add links an account to the identity with the same email, Plex ID or
+// configured alias. Its other names point to that identity only as long as
+// no other identity shares them.
+func (r *Resolver) Add(account Account) {
+	var keys, loose []string
+	display := ""
+	for _, name := range account.Names {
+		if name == "" {
+			continue
+		}
+		if display == "" {
+			display = name
+		}
+		if _, configured := r.parent[nameKey(name)]; configured {
+			keys = append(keys, nameKey(name))
+		} else {
+			loose = append(loose, nameKey(name))
+		}
+	}
+	if account.Email != "" {
+		if display == "" {
+			display = account.Email
+		}
+		keys = append(keys, emailKey(account.Email))
+		loose = append(loose, nameKey(account.Email))
+	}
+	if account.PlexID != 0 {
+		keys = append(keys, "plex:"+strconv.FormatInt(account.PlexID, 10))
+	}
+	if display == "" && len(keys) == 0 {
+		return
+	}
+	if len(keys) == 0 {
+		r.accounts++
+		keys = append(keys, "account:"+strconv.Itoa(r.accounts))
+	}
+	r.union(nameInfo{name: display}, keys)
+
+	for _, key := range loose {
+		r.names[key] = append(r.names[key], keys[0])
+	}
+}
+
+// AddGroup defines a named group of users, such as a household. Members can
+// be given by any of their names.
+func (r *Resolver) AddGroup(name string, members ...string) {
+	r.groups[strings.ToLower(name)] = members
+}
+
+// Group returns the members of a group, or nil when it is not defined
+func (r *Resolver) Group(name string) []string {
+	if r == nil {
+		return nil
+	}
+	return r.groups[strings.ToLower(name)]
+}
+
+// Canonical returns the display name of the identity a name belongs to, or
+// the name itself when it is unknown
+func (r *Resolver) Canonical(name string) string {
+	if r == nil {
+		return name
+	}
+	if root, ok := r.lookup(name); ok {
+		return r.canonical[root].name
+	}
+	return name
+}
+
+// Same reports whether two names belong to the same user. Names are
+// compared case-insensitively even when neither is known.
+func (r *Resolver) Same(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if r == nil || a == "" || b == "" {
+		return false
+	}
+	rootA, okA := r.lookup(a)
+	rootB, okB := r.lookup(b)
+	return okA && okB && rootA == rootB
+}
+
+// lookup returns the root key of the identity a name belongs to. A name that
+// accounts of several identities share belongs to none of them.
+func (r *Resolver) lookup(name string) (string, bool) {
+	key := nameKey(name)
+	if root, ok := r.find(key); ok {
+		return root, true
+	}
+
+	var root string
+	for _, account := range r.names[key] {
+		accountRoot, _ := r.find(account)
+		if root != "" && accountRoot != root {
+			return "", false
+		}
+		root = accountRoot
+	}
+	return root, root != ""
+}
+
+// union merges the identities of keys. A configured display name always
+// wins, otherwise an identity keeps the display name it already has.
+func (r *Resolver) union(display nameInfo, keys []string) {
+	var root string
+	for _, key := range keys {
+		if _, ok := r.parent[key]; !ok {
+			r.parent[key] = key
+		}
+		keyRoot, _ := r.find(key)
+		if root == "" {
+			root = keyRoot
+			continue
+		}
+		if keyRoot == root {
+			continue
+		}
+		r.parent[keyRoot] = root
+		r.canonical[root] = preferName(r.canonical[root], r.canonical[keyRoot])
+		delete(r.canonical, keyRoot)
+	}
+	r.canonical[root] = preferName(r.canonical[root], display)
+}
+
+// preferName picks the display name of a merged identity
+func preferName(current, other nameInfo) nameInfo {
+	if current.name == "" || (other.configured && !current.configured) {
+		return other
+	}
+	return current
+}
+
+// find returns the root key of an identity. It does not modify the resolver,
+// so lookups are safe from several goroutines once building is done.
+func (r *Resolver) find(key string) (string, bool) {
+	parent, ok := r.parent[key]
+	if !ok {
+		return "", false
+	}
+	for parent != key {
+		key = parent
+		parent = r.parent[key]
+	}
+	return key, true
+}
+
+func nameKey(name string) string {
+	return "name:" + strings.ToLower(strings.TrimSpace(name))
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,91 @@
+package identity
+
+import "testing"
+
+func TestResolver(t *testing.T) {
+	r := NewResolver()
+	r.Link("alice", "Alice Smith", "alice@example.com")
+	r.Add(Account{Names: []string{"Ally", "ally_plex"}, PlexID: 7})
+	// Tautulli knows the same Plex account by email only
+	r.Add(Account{Names: []string{"ally_plex"}, Email: "ALICE@example.com", PlexID: 7})
+	r.Add(Account{Names: []string{"bob"}, PlexID: 8})
+
+	same := []struct{ a, b string }{
+		{"alice", "Alice Smith"},
+		{"Ally", "alice"},
+		{"alice@example.com", "ally_plex"},
+		{"carol", "CAROL"}, // Unknown names still compare case-insensitively
+	}
+	for _, tt := range same {
+		if !r.Same(tt.a, tt.b) {
+			t.Errorf("expected %q and %q to be the same user", tt.a, tt.b)
+		}
+	}
+
+	different := []struct{ a, b string }{
+		{"alice", "bob"},
+		{"carol", "dave"},
+		{"", "alice"},
+	}
+	for _, tt := range different {
+		if r.Same(tt.a, tt.b) {
+			t.Errorf("expected %q and %q to be different users", tt.a, tt.b)
+		}
+	}
+
+	// The configured name wins over names from services
+	if got := r.Canonical("ally_plex"); got != "alice" {
+		t.Errorf("expected canonical name alice, got %q", got)
+	}
+	if got := r.Canonical("carol"); got != "carol" {
+		t.Errorf("expected unknown names to be returned as is, got %q", got)
+	}
+
+	var nilResolver *Resolver
+	if !nilResolver.Same("Bob", "bob") || nilResolver.Same("bob", "alice") {
+		t.Error("expected a nil resolver to compare names case-insensitively")
+	}
+}
+
+func TestResolverSharedNames(t *testing.T) {
+	r := NewResolver()
+	r.Link("dave", "dave_jellyfin")
+	// Two people called John on Plex, and a Jellyfin account with no email
+	r.Add(Account{Names: []string{"John", "john_smith"}, Email: "smith@example.com", PlexID: 1})
+	r.Add(Account{Names: []string{"John", "jdoe"}, Email: "doe@example.com", PlexID: 2})
+	r.Add(Account{Names: []string{"john", "John Jellyfin"}})
+	// A generic admin account on two servers
+	r.Add(Account{Names: []string{"admin", "dave_jellyfin"}})
+	r.Add(Account{Names: []string{"admin", "erin"}, PlexID: 3})
+
+	different := []struct{ a, b string }{
+		{"john_smith", "jdoe"},
+		{"smith@example.com", "John Jellyfin"},
+		{"jdoe", "John Jellyfin"},
+		{"dave", "erin"},
+		{"admin", "dave"}, // A shared name belongs to nobody in particular
+	}
+	for _, tt := range different {
+		if r.Same(tt.a, tt.b) {
+			t.Errorf("expected %q and %q to be different users", tt.a, tt.b)
+		}
+	}
+
+	same := []struct{ a, b string }{
+		{"john_smith", "smith@example.com"},
+		{"dave", "dave_jellyfin"},
+		{"jdoe", "doe@example.com"},
+	}
+	for _, tt := range same {
+		if !r.Same(tt.a, tt.b) {
+			t.Errorf("expected %q and %q to be the same user", tt.a, tt.b)
+		}
+	}
+
+	if got := r.Canonical("John"); got != "John" {
+		t.Errorf("expected a shared name to be returned as is, got %q", got)
+	}
+	if got := r.Canonical("jdoe"); got != "John" {
+		t.Errorf("expected the account's display name, got %q", got)
+	}
+}
@@ -0,0 +1,151 @@
+// Package jellyfin reads watch history from Jellyfin and Emby media servers.
+// Both expose the same users and items API, so one client serves either.
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// defaultTimeout is the default HTTP client timeout.
+	defaultTimeout = 30 * time.Second
+
+	// defaultPageSize is the number of items fetched per request.
+	defaultPageSize = 500
+)
+
+// Server identifies which media server a client talks to.
+type Server string
+
+// Supported media servers.
+const (
+	ServerJellyfin Server = "jellyfin"
+	ServerEmby     Server = "emby"
+)
+
+// ErrUnauthorized indicates the API key was rejected.
+var ErrUnauthorized = errors.New("unauthorized: invalid API key")
+
+// Client provides access to the Jellyfin or Emby API for retrieving watch history.
+type Client struct {
+	server     Server
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	pageSize   int
+	logger     zerolog.Logger
+}
+
+// NewClient creates a new client for a Jellyfin or Emby server and validates
+// the connection. It returns an error if the API key is invalid or the server
+// is unreachable.
+func NewClient(server Server, baseURL, apiKey string, logger zerolog.Logger) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("baseURL cannot be empty")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("apiKey cannot be empty")
+	}
+
+	// Ensure base URL ends without slash
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	client := &Client{
+		server:     server,
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		pageSize:   defaultPageSize,
+		logger:     logger,
+	}
+
+	// Test the connection with a short context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.GetUsers(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+
+	return client, nil
+}
+
+// GetUsers returns every user account on the server.
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	if err := c.get(ctx, "/Users", nil, &users); err != nil {
+		return nil, fmt.Errorf("fetching users: %w", err)
+	}
+	return users, nil
+}
+
+// GetUserMovies returns every movie in the libraries a user can see, along
+// with that user's playback state.
+func (c *Client) GetUserMovies(ctx context.Context, userID string) ([]Item, error) {
+	var items []Item
+	for start := 0; ; start += c.pageSize {
+		params := url.Values{}
+		params.Set("IncludeItemTypes", "Movie")
+		params.Set("Recursive", "true")
+		params.Set("Fields", "ProviderIds")
+		params.Set("EnableUserData", "true")
+		params.Set("StartIndex", strconv.Itoa(start))
+		params.Set("Limit", strconv.Itoa(c.pageSize))
+
+		var page itemsResponse
+		if err := c.get(ctx, "/Users/"+url.PathEscape(userID)+"/Items", params, &page); err != nil {
+			return nil, fmt.Errorf("fetching movies: %w", err)
+		}
+		items = append(items, page.Items...)
+
+		if len(page.Items) == 0 || start+len(page.Items) >= page.TotalRecordCount {
+			return items, nil
+		}
+	}
+}
+
+// get performs a GET request against the API and decodes the JSON response.
+func (c *Client) get(ctx context.Context, path string, params url.Values, result any) error {
+	requestURL := c.baseURL + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("X-Emby-Token", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package jellyfin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Emby-Token") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/Users":
+			w.Write([]byte(`[{"Id":"u1","Name":"alice"},{"Id":"u2","Name":"bob"}]`))
+		case "/Users/u1/Items":
+			assert.Equal(t, "Movie", r.URL.Query().Get("IncludeItemTypes"))
+			if r.URL.Query().Get("StartIndex") == "0" {
+				w.Write([]byte(`{"TotalRecordCount":2,"Items":[
+					{"Name":"Heat","ProviderIds":{"Tmdb":"949","Imdb":"tt0113277"},
+					 "UserData":{"Played":true,"PlayCount":2,"LastPlayedDate":"2024-05-01T20:00:00Z"}}]}`))
+				return
+			}
+			w.Write([]byte(`{"TotalRecordCount":2,"Items":[
+				{"Name":"Alien","ProviderIds":{"imdb":"tt0078748"},
+				 "UserData":{"Played":false,"PlayCount":0,"PlayedPercentage":40}}]}`))
+		case "/Users/u2/Items":
+			w.Write([]byte(`{"TotalRecordCount":1,"Items":[
+				{"Name":"Heat","ProviderIds":{"Tmdb":"949"},
+				 "UserData":{"Played":false,"PlayCount":0,"PlayedPercentage":90,"LastPlayedDate":"2024-06-01T20:00:00Z"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNewClient(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	_, err := NewClient(ServerJellyfin, server.URL, "wrong", zerolog.Nop())
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = NewClient(ServerJellyfin, "", "key", zerolog.Nop())
+	require.Error(t, err)
+
+	client, err := NewClient(ServerEmby, server.URL+"/", "key", zerolog.Nop())
+	require.NoError(t, err)
+	assert.Equal(t, "emby", client.Name())
+}
+
+func TestMovieWatchStatus(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client, err := NewClient(ServerJellyfin, server.URL, "key", zerolog.Nop())
+	require.NoError(t, err)
+	client.pageSize = 1
+
+	statuses, err := client.MovieWatchStatus(context.Background(), []watch.MovieIdentifier{
+		{TMDbID: 949, IMDbID: "tt0113277", Title: "Heat"},
+		{TMDbID: 348, IMDbID: "tt0078748", Title: "Alien"},
+		{TMDbID: 603, IMDbID: "tt0133093", Title: "The Matrix"},
+	}, watch.Threshold{MinPercent: 85})
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+
+	heat := statuses[949]
+	assert.True(t, heat.Watched)
+	assert.Equal(t, 2, heat.WatchCount)
+	assert.Equal(t, float64(100), heat.MaxProgress)
+	assert.Equal(t, time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC), heat.LastWatched)
+	require.Contains(t, heat.UserData, "alice")
+	require.Contains(t, heat.UserData, "bob")
+	assert.True(t, heat.UserData["alice"].Watched)
+	assert.True(t, heat.UserData["bob"].Watched, "90% counts as watched at an 85% threshold")
+
+	// Matched by IMDB ID, partially watched
+	alien := statuses[348]
+	assert.False(t, alien.Watched)
+	assert.Equal(t, float64(40), alien.MaxProgress)
+	assert.Contains(t, alien.UserData, "alice")
+
+	matrix := statuses[603]
+	assert.False(t, matrix.Watched)
+	assert.Empty(t, matrix.UserData)
+}
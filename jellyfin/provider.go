@@ -0,0 +1,102 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// Name identifies the media server the client talks to.
+func (c *Client) Name() string {
+	return string(c.server)
+}
+
+// MovieWatchStatus returns the watch status of movies keyed by TMDB ID,
+// built from the playback state of every user on the server. Movies are
+// matched by TMDB ID, falling back to IMDB ID.
+func (c *Client) MovieWatchStatus(ctx context.Context, movies []watch.MovieIdentifier, threshold watch.Threshold) (map[int64]*watch.MovieStatus, error) {
+	byIMDb := make(map[string]int64, len(movies))
+	results := make(map[int64]*watch.MovieStatus, len(movies))
+	for _, movie := range movies {
+		if movie.TMDbID == 0 {
+			continue
+		}
+		results[movie.TMDbID] = &watch.MovieStatus{UserData: make(map[string]*watch.UserStatus)}
+		if movie.IMDbID != "" {
+			byIMDb[movie.IMDbID] = movie.TMDbID
+		}
+	}
+
+	users, err := c.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		items, err := c.GetUserMovies(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", user.Name, err)
+		}
+
+		for _, item := range items {
+			status := results[c.matchItem(item, byIMDb)]
+			if status == nil || item.UserData == nil {
+				continue
+			}
+
+			userStatus := newUserStatus(user.Name, item.UserData, threshold)
+			if userStatus.WatchCount == 0 && userStatus.MaxProgress == 0 && !userStatus.Watched {
+				continue
+			}
+
+			status.UserData[user.Name] = userStatus
+			status.Watched = status.Watched || userStatus.Watched
+			status.WatchCount += userStatus.WatchCount
+			status.MaxProgress = max(status.MaxProgress, userStatus.MaxProgress)
+			if userStatus.LastWatched.After(status.LastWatched) {
+				status.LastWatched = userStatus.LastWatched
+			}
+		}
+	}
+
+	c.logger.Debug().
+		Str("server", c.Name()).
+		Int("users", len(users)).
+		Int("movies", len(movies)).
+		Msg("Fetched watch history")
+
+	return results, nil
+}
+
+// matchItem returns the TMDB ID of the requested movie an item is, or 0
+func (c *Client) matchItem(item Item, byIMDb map[string]int64) int64 {
+	if id, err := strconv.ParseInt(item.providerID("Tmdb"), 10, 64); err == nil && id > 0 {
+		return id
+	}
+	if imdbID := item.providerID("Imdb"); imdbID != "" {
+		return byIMDb[imdbID]
+	}
+	return 0
+}
+
+// newUserStatus converts a user's playback state to a watch status. Played
+// items count as fully watched, since the servers reset the progress of
+// played items to zero.
+func newUserStatus(username string, data *UserData, threshold watch.Threshold) *watch.UserStatus {
+	status := &watch.UserStatus{
+		Username:    username,
+		Watched:     data.Played || threshold.Watched(username, data.PlayedPercentage),
+		WatchCount:  data.PlayCount,
+		MaxProgress: data.PlayedPercentage,
+	}
+	if data.Played {
+		status.MaxProgress = 100
+		status.WatchCount = max(status.WatchCount, 1)
+	}
+	if data.LastPlayedDate != nil {
+		status.LastWatched = *data.LastPlayedDate
+	}
+	return status
+}
@@ -0,0 +1,47 @@
+package jellyfin
+
+import (
+	"strings"
+	"time"
+)
+
+// User is a Jellyfin or Emby user account.
+type User struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+// itemsResponse is the paged response of the user items endpoint.
+type itemsResponse struct {
+	Items            []Item `json:"Items"`
+	TotalRecordCount int    `json:"TotalRecordCount"`
+}
+
+// Item is a library item as seen by one user.
+type Item struct {
+	Name        string            `json:"Name"`
+	ProviderIDs map[string]string `json:"ProviderIds"`
+	UserData    *UserData         `json:"UserData"`
+}
+
+// UserData is the playback state of an item for one user.
+type UserData struct {
+	Played           bool       `json:"Played"`
+	PlayCount        int        `json:"PlayCount"`
+	LastPlayedDate   *time.Time `json:"LastPlayedDate"`
+	PlayedPercentage float64    `json:"PlayedPercentage"`
+}
+
+// providerID returns an external ID of the item. Jellyfin and Emby are not
+// consistent about the case of provider names, so the lookup ignores it.
+func (i Item) providerID(name string) string {
+	if id, ok := i.ProviderIDs[name]; ok {
+		return id
+	}
+	for key, id := range i.ProviderIDs {
+		if strings.EqualFold(key, name) {
+			return id
+		}
+	}
+	return ""
+}
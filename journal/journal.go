@@ -0,0 +1,200 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the journal
+const (
+	ActionDelete            = "delete"
+	ActionQuarantine        = "quarantine"
+	ActionPurge             = "purge"
+	ActionUpgrade           = "upgrade"
+	ActionReimport          = "reimport"
+	ActionDeleteAndResearch = "delete_and_research"
+	ActionUndo              = "undo"
+)
+
+// Outcomes recorded in the journal
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+// Record is a single action taken on a movie
+type Record struct {
+	Time       time.Time        `json:"time"`
+	Action     string           `json:"action"`
+	Outcome    string           `json:"outcome"`
+	Error      string           `json:"error,omitempty"`
+	Details    string           `json:"details,omitempty"`
+	MovieID    int64            `json:"movie_id"`
+	Instance   string           `json:"instance,omitempty"` // Radarr instance, empty for the only one
+	TMDBID     int64            `json:"tmdb_id"`
+	IMDBID     string           `json:"imdb_id,omitempty"`
+	Title      string           `json:"title"`
+	Year       int              `json:"year"`
+	Path       string           `json:"path,omitempty"`
+	SizeBytes  int64            `json:"size_bytes,omitempty"`
+	Filter     string           `json:"filter,omitempty"`
+	Expression string           `json:"expression,omitempty"`
+	Watch      *WatchSnapshot   `json:"watch,omitempty"`
+	Request    *RequestSnapshot `json:"request,omitempty"`
+	Radarr     *RadarrSnapshot  `json:"radarr,omitempty"`
+}
+
+// RadarrSnapshot is the Radarr configuration a movie had when the action was
+// taken, enough to add it back the way it was
+type RadarrSnapshot struct {
+	QualityProfileID int64    `json:"quality_profile_id"`
+	RootFolder       string   `json:"root_folder"` // Radarr root folder, the movie's parent folder in older records
+	Availability     string   `json:"minimum_availability,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Monitored        bool     `json:"monitored"`
+	AddedExclusion   bool     `json:"added_exclusion,omitempty"`
+}
+
+// WatchSnapshot is the watch data a movie had when the action was taken
+type WatchSnapshot struct {
+	Watched     bool      `json:"watched"`
+	WatchCount  int       `json:"watch_count"`
+	LastWatched time.Time `json:"last_watched,omitzero"`
+	Progress    float64   `json:"progress"`
+	WatchedBy   []string  `json:"watched_by,omitempty"`
+}
+
+// RequestSnapshot is the request data a movie had when the action was taken
+type RequestSnapshot struct {
+	RequestedBy string    `json:"requested_by"`
+	RequestDate time.Time `json:"request_date,omitzero"`
+	Status      string    `json:"status,omitempty"`
+	ApprovedBy  string    `json:"approved_by,omitempty"`
+}
+
+// Query selects journal records. Zero fields match everything.
+type Query struct {
+	Since  time.Time
+	Until  time.Time
+	Action string
+	Filter string
+	Title  string // Case-insensitive substring
+	Limit  int    // Keep only the most recent records
+}
+
+// Matches reports whether a record satisfies the query
+func (q Query) Matches(r Record) bool {
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && r.Time.After(q.Until) {
+		return false
+	}
+	if q.Action != "" && r.Action != q.Action {
+		return false
+	}
+	if q.Filter != "" && r.Filter != q.Filter {
+		return false
+	}
+	if q.Title != "" && !strings.Contains(strings.ToLower(r.Title), strings.ToLower(q.Title)) {
+		return false
+	}
+	return true
+}
+
+// Journal appends records to a JSONL file
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a journal writing to path
+func New(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Path returns the location of the journal file
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Append writes records to the end of the journal, stamping any without a time
+func (j *Journal) Append(records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("unable to create journal directory: %w", err)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	now := time.Now()
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		if record.Time.IsZero() {
+			record.Time = now
+		}
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write journal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Read returns records matching the query in the order they were written
+func (j *Journal) Read(q Query) ([]Record, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("corrupt journal entry on line %d: %w", line, err)
+		}
+		if q.Matches(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[len(records)-q.Limit:]
+	}
+
+	return records, nil
+}
@@ -0,0 +1,66 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "nested", "history.jsonl"))
+
+	now := time.Now()
+	err := j.Append(
+		Record{Time: now.AddDate(0, 0, -10), Action: ActionDelete, Outcome: OutcomeSuccess, Title: "Heat", Filter: "old_unwatched", TMDBID: 949},
+		Record{Time: now.AddDate(0, 0, -2), Action: ActionUpgrade, Outcome: OutcomeSuccess, Title: "Ronin"},
+	)
+	if err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	// A second append must not clobber the first
+	if err := j.Append(Record{Action: ActionDelete, Outcome: OutcomeFailed, Title: "Heat 2", Filter: "old_unwatched", Error: "boom"}); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all", Query{}, []string{"Heat", "Ronin", "Heat 2"}},
+		{"by filter", Query{Filter: "old_unwatched"}, []string{"Heat", "Heat 2"}},
+		{"by title", Query{Title: "heat"}, []string{"Heat", "Heat 2"}},
+		{"by action", Query{Action: ActionUpgrade}, []string{"Ronin"}},
+		{"since", Query{Since: now.AddDate(0, 0, -5)}, []string{"Ronin", "Heat 2"}},
+		{"limit keeps most recent", Query{Limit: 1}, []string{"Heat 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := j.Read(tt.query)
+			if err != nil {
+				t.Fatalf("failed to read: %v", err)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("expected %d records, got %d", len(tt.want), len(records))
+			}
+			for i, title := range tt.want {
+				if records[i].Title != title {
+					t.Errorf("record %d: expected %q, got %q", i, title, records[i].Title)
+				}
+			}
+		})
+	}
+
+	records, _ := j.Read(Query{Title: "Heat 2"})
+	if len(records) != 1 || records[0].Time.IsZero() || records[0].Error != "boom" {
+		t.Errorf("expected stamped failed record, got %+v", records)
+	}
+}
+
+func TestReadMissingJournal(t *testing.T) {
+	records, err := New(filepath.Join(t.TempDir(), "missing.jsonl")).Read(Query{})
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected empty result for missing journal, got %v (err %v)", records, err)
+	}
+}
@@ -12,6 +12,9 @@ type API interface {
 	// GetMovieRequests retrieves all movie requests
 	GetMovieRequests(ctx context.Context) ([]MediaRequest, error)
 	
+	// GetTVRequests retrieves all TV requests
+	GetTVRequests(ctx context.Context) ([]MediaRequest, error)
+	
 	// GetMovieRequestsByTMDBID retrieves movie requests for a specific TMDB ID
 	GetMovieRequestsByTMDBID(ctx context.Context, tmdbID int64) ([]MediaRequest, error)
 }
@@ -225,8 +225,13 @@ func (c *Client) FetchPage(ctx context.Context, page, pageSize int) (*RequestsRe
 	return &response, nil
 }
 
-// FetchAll fetches all requests using pagination
+// FetchAll fetches all movie requests using pagination
 func (c *Client) FetchAll(ctx context.Context) ([]MediaRequest, error) {
+	return c.fetchAllOfType(ctx, MediaTypeMovie)
+}
+
+// fetchAllOfType fetches all requests of one media type using pagination
+func (c *Client) fetchAllOfType(ctx context.Context, mediaType MediaType) ([]MediaRequest, error) {
 	var allRequests []MediaRequest
 	page := 1
 
@@ -243,9 +248,8 @@ func (c *Client) FetchAll(ctx context.Context) ([]MediaRequest, error) {
 			return nil, err
 		}
 
-		// Filter for movie requests only
 		for _, req := range response.Results {
-			if req.IsMovieRequest() {
+			if req.Type == mediaType {
 				allRequests = append(allRequests, req)
 			}
 		}
@@ -253,7 +257,7 @@ func (c *Client) FetchAll(ctx context.Context) ([]MediaRequest, error) {
 		c.logger.Debug().
 			Int("page", page).
 			Int("fetched", len(response.Results)).
-			Int("movies", len(allRequests)).
+			Int(string(mediaType), len(allRequests)).
 			Msg("Fetched request page")
 
 		// Check if we've retrieved all pages
@@ -267,6 +271,26 @@ func (c *Client) FetchAll(ctx context.Context) ([]MediaRequest, error) {
 	return allRequests, nil
 }
 
+// GetUsers retrieves every Overseerr user, including the Plex account each is linked to
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	for skip := 0; ; skip += c.pageSize {
+		params := url.Values{}
+		params.Set("take", strconv.Itoa(c.pageSize))
+		params.Set("skip", strconv.Itoa(skip))
+
+		var response UsersResponse
+		if err := c.get(ctx, "/user", params, &response); err != nil {
+			return nil, fmt.Errorf("fetching users: %w", err)
+		}
+		users = append(users, response.Results...)
+
+		if len(response.Results) == 0 || len(users) >= response.PageInfo.Results {
+			return users, nil
+		}
+	}
+}
+
 // GetMovieRequests retrieves all movie requests from Overseerr
 func (c *Client) GetMovieRequests(ctx context.Context) ([]MediaRequest, error) {
 	requests, err := c.FetchAll(ctx)
@@ -281,6 +305,20 @@ func (c *Client) GetMovieRequests(ctx context.Context) ([]MediaRequest, error) {
 	return requests, nil
 }
 
+// GetTVRequests retrieves all TV requests from Overseerr
+func (c *Client) GetTVRequests(ctx context.Context) ([]MediaRequest, error) {
+	requests, err := c.fetchAllOfType(ctx, MediaTypeTV)
+	if err != nil {
+		return nil, fmt.Errorf("fetching TV requests: %w", err)
+	}
+
+	c.logger.Info().
+		Int("count", len(requests)).
+		Msg("Retrieved TV requests from Overseerr")
+
+	return requests, nil
+}
+
 // GetMovieRequestsByTMDBID retrieves movie requests for a specific TMDB ID
 func (c *Client) GetMovieRequestsByTMDBID(ctx context.Context, tmdbID int64) ([]MediaRequest, error) {
 	// Unfortunately, Overseerr doesn't provide a direct way to filter requests by TMDB ID
@@ -1,6 +1,7 @@
 package overseerr
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -108,6 +109,31 @@ func TestClientOptions(t *testing.T) {
 	})
 }
 
+func TestGetUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/me" {
+			w.Write([]byte(`{"id":1,"displayName":"admin"}`))
+			return
+		}
+		assert.Equal(t, "/api/v1/user", r.URL.Path)
+		if r.URL.Query().Get("skip") == "0" {
+			w.Write([]byte(`{"pageInfo":{"results":2},"results":[{"id":1,"displayName":"Alice Smith","email":"alice@example.com","plexId":42}]}`))
+			return
+		}
+		w.Write([]byte(`{"pageInfo":{"results":2},"results":[{"id":2,"plexUsername":"bob"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "key", zerolog.Nop(), WithPageSize(1))
+	require.NoError(t, err)
+
+	users, err := client.GetUsers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, int64(42), users[0].PlexID)
+	assert.Equal(t, "bob", users[1].GetDisplayName())
+}
+
 func TestRequestStatus(t *testing.T) {
 	tests := []struct {
 		status   RequestStatus
@@ -70,6 +70,7 @@ type User struct {
 	Email        string `json:"email"`
 	Username     string `json:"username,omitempty"`
 	PlexUsername string `json:"plexUsername,omitempty"`
+	PlexID       int64  `json:"plexId,omitempty"`
 	DisplayName  string `json:"displayName"`
 	Avatar       string `json:"avatar,omitempty"`
 }
@@ -142,6 +143,20 @@ func (mr *MediaRequest) IsMovieRequest() bool {
 	return mr.Type.IsMovie()
 }
 
+// IsTVRequest checks if this is a TV request
+func (mr *MediaRequest) IsTVRequest() bool {
+	return mr.Type == MediaTypeTV
+}
+
+// SeasonNumbers returns the season numbers covered by a TV request
+func (mr *MediaRequest) SeasonNumbers() []int {
+	numbers := make([]int, 0, len(mr.Seasons))
+	for _, season := range mr.Seasons {
+		numbers = append(numbers, season.SeasonNumber)
+	}
+	return numbers
+}
+
 // GetApprover returns the user who approved the request, if available
 func (mr *MediaRequest) GetApprover() *User {
 	if mr.ModifiedBy != nil && (mr.Status == RequestStatusApproved || mr.Status == RequestStatusAvailable) {
@@ -187,6 +202,12 @@ func (rr *RequestsResponse) HasMorePages() bool {
 	return rr.PageInfo.Page < rr.PageInfo.Pages
 }
 
+// UsersResponse represents the paginated response from the users endpoint
+type UsersResponse struct {
+	PageInfo PageInfo `json:"pageInfo"`
+	Results  []User   `json:"results"`
+}
+
 // PageInfo contains pagination information
 type PageInfo struct {
 	Pages    int `json:"pages"`
@@ -0,0 +1,199 @@
+// Package plex reads watch state directly from a Plex Media Server. Unlike
+// Tautulli history it includes everything watched before Tautulli was
+// installed, because Plex keeps each account's view count on the item itself.
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// defaultTimeout is the default HTTP client timeout.
+	defaultTimeout = 30 * time.Second
+
+	// defaultPageSize is the number of items fetched per request.
+	defaultPageSize = 500
+
+	// defaultPlexTVURL is where the accounts the server is shared with are listed.
+	defaultPlexTVURL = "https://plex.tv"
+
+	// Library section and item type of movies.
+	sectionTypeMovie = "movie"
+	itemTypeMovie    = "1"
+)
+
+// ErrUnauthorized indicates the token was rejected.
+var ErrUnauthorized = errors.New("unauthorized: invalid Plex token")
+
+// Account is a Plex account with access to the server, and the token its
+// watch state is read with.
+type Account struct {
+	Username string
+	token    string
+}
+
+// Client provides access to a Plex Media Server for reading watch state.
+type Client struct {
+	baseURL           string
+	plexTVURL         string
+	token             string
+	machineIdentifier string
+	httpClient        *http.Client
+	pageSize          int
+	logger            zerolog.Logger
+}
+
+// NewClient creates a new Plex Media Server client and validates the
+// connection. The token must belong to the server owner so the accounts the
+// server is shared with can be listed.
+func NewClient(baseURL, token string, logger zerolog.Logger) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("baseURL cannot be empty")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("token cannot be empty")
+	}
+
+	// Ensure base URL ends without slash
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	client := &Client{
+		baseURL:    baseURL,
+		plexTVURL:  defaultPlexTVURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		pageSize:   defaultPageSize,
+		logger:     logger,
+	}
+
+	// Test the connection with a short context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var result mediaContainer[identity]
+	if err := client.get(ctx, client.baseURL, "/identity", token, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to connect to Plex: %w", err)
+	}
+	client.machineIdentifier = result.MediaContainer.MachineIdentifier
+
+	return client, nil
+}
+
+// GetAccounts returns the server owner and every account the server is
+// shared with. When the shared accounts cannot be listed only the owner is
+// returned, with a warning.
+func (c *Client) GetAccounts(ctx context.Context) ([]Account, error) {
+	var owner myPlexAccount
+	if err := c.get(ctx, c.baseURL, "/myplex/account", c.token, nil, &owner); err != nil {
+		return nil, fmt.Errorf("fetching owner account: %w", err)
+	}
+	accounts := []Account{{Username: owner.MyPlex.Username, token: c.token}}
+
+	var shared sharedServers
+	path := "/api/servers/" + url.PathEscape(c.machineIdentifier) + "/shared_servers"
+	if err := c.get(ctx, c.plexTVURL, path, c.token, nil, &shared); err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to list shared Plex accounts, using the owner's watch state only")
+		return accounts, nil
+	}
+	for _, server := range shared.SharedServers {
+		if server.AccessToken == "" {
+			continue
+		}
+		accounts = append(accounts, Account{Username: server.Username, token: server.AccessToken})
+	}
+
+	return accounts, nil
+}
+
+// GetMovieSections returns the movie libraries an account can see.
+func (c *Client) GetMovieSections(ctx context.Context, account Account) ([]Section, error) {
+	var result mediaContainer[sections]
+	if err := c.get(ctx, c.baseURL, "/library/sections", account.token, nil, &result); err != nil {
+		return nil, fmt.Errorf("fetching library sections: %w", err)
+	}
+
+	var movieSections []Section
+	for _, section := range result.MediaContainer.Directory {
+		if section.Type == sectionTypeMovie {
+			movieSections = append(movieSections, section)
+		}
+	}
+	return movieSections, nil
+}
+
+// GetSectionMovies returns every movie in a library section along with the
+// account's watch state.
+func (c *Client) GetSectionMovies(ctx context.Context, account Account, section Section) ([]Metadata, error) {
+	var movies []Metadata
+	for start := 0; ; start += c.pageSize {
+		params := url.Values{}
+		params.Set("type", itemTypeMovie)
+		params.Set("includeGuids", "1")
+		params.Set("X-Plex-Container-Start", strconv.Itoa(start))
+		params.Set("X-Plex-Container-Size", strconv.Itoa(c.pageSize))
+
+		var page mediaContainer[items]
+		path := "/library/sections/" + url.PathEscape(section.Key) + "/all"
+		if err := c.get(ctx, c.baseURL, path, account.token, params, &page); err != nil {
+			return nil, fmt.Errorf("fetching movies of %s: %w", section.Title, err)
+		}
+		movies = append(movies, page.MediaContainer.Metadata...)
+
+		if len(page.MediaContainer.Metadata) == 0 || start+len(page.MediaContainer.Metadata) >= page.MediaContainer.TotalSize {
+			return movies, nil
+		}
+	}
+}
+
+// get performs a GET request and decodes the response. The Plex Media Server
+// answers in JSON, plex.tv only in XML.
+func (c *Client) get(ctx context.Context, baseURL, path, token string, params url.Values, result any) error {
+	requestURL := baseURL + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "xml") {
+		err = xml.NewDecoder(resp.Body).Decode(result)
+	} else {
+		err = json.NewDecoder(resp.Body).Decode(result)
+	}
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// newTestServer serves both the Plex Media Server and the plex.tv endpoints.
+// The owner's token is "owner", the account the server is shared with uses "friend".
+// The share with "carol" has been revoked, so her token is rejected.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Plex-Token")
+		if token != "owner" && token != "friend" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/identity":
+			w.Write([]byte(`{"MediaContainer":{"machineIdentifier":"abc123"}}`))
+		case "/myplex/account":
+			w.Write([]byte(`{"MyPlex":{"username":"alice"}}`))
+		case "/api/servers/abc123/shared_servers":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<MediaContainer><SharedServer username="bob" accessToken="friend"/><SharedServer username="carol" accessToken="revoked"/><SharedServer username="pending"/></MediaContainer>`))
+		case "/library/sections":
+			w.Write([]byte(`{"MediaContainer":{"Directory":[
+				{"key":"1","type":"movie","title":"Movies"},
+				{"key":"2","type":"show","title":"TV Shows"}]}}`))
+		case "/library/sections/1/all":
+			assert.Equal(t, "1", r.URL.Query().Get("includeGuids"))
+			if token == "owner" {
+				if r.URL.Query().Get("X-Plex-Container-Start") == "0" {
+					w.Write([]byte(`{"MediaContainer":{"size":1,"totalSize":2,"Metadata":[
+						{"title":"Heat","guid":"plex://movie/1","Guid":[{"id":"imdb://tt0113277"},{"id":"tmdb://949"}],
+						 "viewCount":2,"lastViewedAt":1714593600,"duration":10000}]}}`))
+					return
+				}
+				w.Write([]byte(`{"MediaContainer":{"size":1,"totalSize":2,"Metadata":[
+					{"title":"Alien","guid":"com.plexapp.agents.imdb://tt0078748?lang=en","viewOffset":4000,"duration":10000}]}}`))
+				return
+			}
+			w.Write([]byte(`{"MediaContainer":{"size":1,"totalSize":1,"Metadata":[
+				{"title":"Heat","guid":"com.plexapp.agents.themoviedb://949?lang=en","viewOffset":9000,"duration":10000,"lastViewedAt":1717243200}]}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient(server.URL, "owner", zerolog.Nop())
+	require.NoError(t, err)
+	client.plexTVURL = server.URL
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "wrong", zerolog.Nop())
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = NewClient("", "owner", zerolog.Nop())
+	require.Error(t, err)
+
+	client := newTestClient(t, server)
+	assert.Equal(t, "abc123", client.machineIdentifier)
+
+	accounts, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	assert.Equal(t, "alice", accounts[0].Username)
+	assert.Equal(t, "bob", accounts[1].Username)
+	assert.Equal(t, "carol", accounts[2].Username)
+}
+
+func TestMovieWatchStatus(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	client.pageSize = 1
+
+	statuses, err := client.MovieWatchStatus(context.Background(), []watch.MovieIdentifier{
+		{TMDbID: 949, IMDbID: "tt0113277", Title: "Heat"},
+		{TMDbID: 348, IMDbID: "tt0078748", Title: "Alien"},
+		{TMDbID: 603, IMDbID: "tt0133093", Title: "The Matrix"},
+	}, watch.Threshold{MinPercent: 85})
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+
+	heat := statuses[949]
+	assert.True(t, heat.Watched)
+	assert.Equal(t, 2, heat.WatchCount)
+	assert.Equal(t, float64(100), heat.MaxProgress)
+	assert.Equal(t, time.Unix(1717243200, 0), heat.LastWatched)
+	require.Contains(t, heat.UserData, "alice")
+	require.Contains(t, heat.UserData, "bob")
+	assert.Equal(t, 2, heat.UserData["alice"].WatchCount)
+	assert.True(t, heat.UserData["bob"].Watched, "90% counts as watched at an 85% threshold")
+	assert.InDelta(t, 90, heat.UserData["bob"].MaxProgress, 0.01)
+
+	// Legacy IMDB agent, partially watched
+	alien := statuses[348]
+	assert.False(t, alien.Watched)
+	assert.InDelta(t, 40, alien.MaxProgress, 0.01)
+	assert.Contains(t, alien.UserData, "alice")
+
+	assert.Empty(t, statuses[603].UserData)
+
+	// carol's account fails, the others still count
+	assert.NotContains(t, heat.UserData, "carol")
+}
+
+func TestMetadataIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		item Metadata
+		imdb string
+		tmdb int64
+	}{
+		{
+			name: "plex agent",
+			item: Metadata{GUID: "plex://movie/5d776", Guids: []GUID{{ID: "imdb://tt0113277"}, {ID: "tmdb://949"}, {ID: "tvdb://1"}}},
+			imdb: "tt0113277",
+			tmdb: 949,
+		},
+		{
+			name: "legacy imdb agent",
+			item: Metadata{GUID: "com.plexapp.agents.imdb://tt0113277?lang=en"},
+			imdb: "tt0113277",
+		},
+		{
+			name: "legacy tmdb agent",
+			item: Metadata{GUID: "com.plexapp.agents.themoviedb://949?lang=en"},
+			tmdb: 949,
+		},
+		{
+			name: "local media",
+			item: Metadata{GUID: "local://123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.imdb, tt.item.IMDbID())
+			assert.Equal(t, tt.tmdb, tt.item.TMDbID())
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// Name identifies the provider in logs and errors.
+func (c *Client) Name() string {
+	return "plex"
+}
+
+// MovieWatchStatus returns the watch status of movies keyed by TMDB ID, built
+// from the view count, last view and resume point every account has on the
+// server. Movies are matched by TMDB ID, falling back to IMDB ID.
+func (c *Client) MovieWatchStatus(ctx context.Context, movies []watch.MovieIdentifier, threshold watch.Threshold) (map[int64]*watch.MovieStatus, error) {
+	byIMDb := make(map[string]int64, len(movies))
+	results := make(map[int64]*watch.MovieStatus, len(movies))
+	for _, movie := range movies {
+		if movie.TMDbID == 0 {
+			continue
+		}
+		results[movie.TMDbID] = &watch.MovieStatus{UserData: make(map[string]*watch.UserStatus)}
+		if movie.IMDbID != "" {
+			byIMDb[movie.IMDbID] = movie.TMDbID
+		}
+	}
+
+	accounts, err := c.GetAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// One account failing, e.g. a revoked share, shouldn't lose everyone else's watch state
+	var failed []error
+	for _, account := range accounts {
+		items, err := c.accountMovies(ctx, account)
+		if err != nil {
+			failed = append(failed, fmt.Errorf("account %s: %w", account.Username, err))
+			c.logger.Warn().Err(err).Str("account", account.Username).Msg("Failed to fetch Plex watch state, skipping account")
+			continue
+		}
+
+		for _, item := range items {
+			status := results[matchItem(item, byIMDb)]
+			if status == nil {
+				continue
+			}
+
+			userStatus := newUserStatus(account.Username, item, threshold)
+			if userStatus.WatchCount == 0 && userStatus.MaxProgress == 0 {
+				continue
+			}
+
+			// The same movie can be in several sections
+			status.UserData[account.Username] = watch.MergeUser(status.UserData[account.Username], userStatus)
+			status.Watched = status.Watched || userStatus.Watched
+			status.WatchCount += userStatus.WatchCount
+			status.MaxProgress = max(status.MaxProgress, userStatus.MaxProgress)
+			if userStatus.LastWatched.After(status.LastWatched) {
+				status.LastWatched = userStatus.LastWatched
+			}
+		}
+	}
+	if len(accounts) > 0 && len(failed) == len(accounts) {
+		return nil, errors.Join(failed...)
+	}
+
+	c.logger.Debug().
+		Int("accounts", len(accounts)-len(failed)).
+		Int("failed_accounts", len(failed)).
+		Int("movies", len(movies)).
+		Msg("Fetched Plex watch state")
+
+	return results, nil
+}
+
+// accountMovies returns the movies of every movie section as one account sees them
+func (c *Client) accountMovies(ctx context.Context, account Account) ([]Metadata, error) {
+	sections, err := c.GetMovieSections(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Metadata
+	for _, section := range sections {
+		sectionItems, err := c.GetSectionMovies(ctx, account, section)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, sectionItems...)
+	}
+	return items, nil
+}
+
+// matchItem returns the TMDB ID of the requested movie an item is, or 0
+func matchItem(item Metadata, byIMDb map[string]int64) int64 {
+	if id := item.TMDbID(); id > 0 {
+		return id
+	}
+	if imdbID := item.IMDbID(); imdbID != "" {
+		return byIMDb[imdbID]
+	}
+	return 0
+}
+
+// newUserStatus converts an account's watch state of an item. Plex only
+// keeps a resume point for unfinished movies, so viewed movies count as
+// fully watched.
+func newUserStatus(username string, item Metadata, threshold watch.Threshold) *watch.UserStatus {
+	status := &watch.UserStatus{
+		Username:   username,
+		WatchCount: item.ViewCount,
+	}
+	if item.ViewOffset > 0 && item.Duration > 0 {
+		status.MaxProgress = float64(item.ViewOffset) / float64(item.Duration) * 100
+	}
+	if item.ViewCount > 0 {
+		status.MaxProgress = 100
+	}
+	status.Watched = item.ViewCount > 0 || threshold.Watched(username, status.MaxProgress)
+	if item.LastViewedAt > 0 {
+		status.LastWatched = time.Unix(item.LastViewedAt, 0)
+	}
+	return status
+}
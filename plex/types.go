@@ -0,0 +1,116 @@
+package plex
+
+import (
+	"strconv"
+	"strings"
+)
+
+// mediaContainer is the envelope of every Plex Media Server JSON response.
+type mediaContainer[T any] struct {
+	MediaContainer T `json:"MediaContainer"`
+}
+
+// identity is the response of /identity.
+type identity struct {
+	MachineIdentifier string `json:"machineIdentifier"`
+}
+
+// sections is the response of /library/sections.
+type sections struct {
+	Directory []Section `json:"Directory"`
+}
+
+// Section is a library section on the server.
+type Section struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+}
+
+// items is a page of library items.
+type items struct {
+	Size      int        `json:"size"`
+	TotalSize int        `json:"totalSize"`
+	Metadata  []Metadata `json:"Metadata"`
+}
+
+// Metadata is a library item with the watch state of the account that asked.
+type Metadata struct {
+	Title        string `json:"title"`
+	GUID         string `json:"guid"`
+	Guids        []GUID `json:"Guid"`
+	ViewCount    int    `json:"viewCount"`
+	LastViewedAt int64  `json:"lastViewedAt"` // Unix timestamp
+	ViewOffset   int64  `json:"viewOffset"`   // Milliseconds into the movie
+	Duration     int64  `json:"duration"`     // Milliseconds
+}
+
+// GUID is an external ID of an item, such as "imdb://tt0113277" or "tmdb://949".
+type GUID struct {
+	ID string `json:"id"`
+}
+
+// myPlexAccount is the response of /myplex/account.
+type myPlexAccount struct {
+	MyPlex struct {
+		Username string `json:"username"`
+	} `json:"MyPlex"`
+}
+
+// sharedServers is the plex.tv list of users the server is shared with.
+type sharedServers struct {
+	SharedServers []sharedServer `xml:"SharedServer"`
+}
+
+// sharedServer carries the access token of a user the server is shared with.
+type sharedServer struct {
+	Username    string `xml:"username,attr"`
+	AccessToken string `xml:"accessToken,attr"`
+}
+
+// IMDbID returns the IMDB ID of the item, from the Plex agent GUIDs or the
+// legacy IMDB agent.
+func (m Metadata) IMDbID() string {
+	for _, guid := range m.Guids {
+		if id, ok := strings.CutPrefix(guid.ID, "imdb://"); ok {
+			return id
+		}
+	}
+	if id, ok := legacyGUID(m.GUID, "com.plexapp.agents.imdb://"); ok {
+		return id
+	}
+	return ""
+}
+
+// TMDbID returns the TMDB ID of the item, from the Plex agent GUIDs or the
+// legacy TMDB agent, or 0 when it has none.
+func (m Metadata) TMDbID() int64 {
+	id, ok := "", false
+	for _, guid := range m.Guids {
+		if id, ok = strings.CutPrefix(guid.ID, "tmdb://"); ok {
+			break
+		}
+	}
+	if !ok {
+		id, ok = legacyGUID(m.GUID, "com.plexapp.agents.themoviedb://")
+	}
+	if !ok {
+		return 0
+	}
+	tmdbID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return tmdbID
+}
+
+// legacyGUID extracts the ID from a legacy agent GUID such as
+// "com.plexapp.agents.imdb://tt0113277?lang=en".
+func legacyGUID(guid, prefix string) (string, bool) {
+	id, ok := strings.CutPrefix(guid, prefix)
+	if !ok {
+		return "", false
+	}
+	id, _, _ = strings.Cut(id, "?")
+	return id, id != ""
+}
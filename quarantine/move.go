@@ -0,0 +1,87 @@
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// moveDir moves a directory, renaming it when source and destination share a
+// filesystem and falling back to copy and delete when they don't
+func moveDir(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("destination %s already exists", dst)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("unable to create %s: %w", filepath.Dir(dst), err)
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("failed to move %s to %s: %w", src, dst, err)
+	}
+
+	// Different filesystems, copy then remove the source
+	if err := copyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("copied %s but failed to remove it: %w", src, err)
+	}
+
+	return nil
+}
+
+// copyDir recursively copies a directory tree, preserving file modes
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single file
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
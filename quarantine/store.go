@@ -0,0 +1,211 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// journalFile is the name of the journal kept inside the quarantine directory
+const journalFile = "journal.json"
+
+// ErrAlreadyQuarantined is returned by Add for a movie that is already in quarantine
+var ErrAlreadyQuarantined = errors.New("movie is already quarantined")
+
+// Entry records a movie folder that was moved into quarantine
+type Entry struct {
+	MovieID            int64     `json:"movie_id"`
+	Instance           string    `json:"instance,omitempty"` // Radarr instance the movie came from
+	TMDBID             int64     `json:"tmdb_id"`
+	Title              string    `json:"title"`
+	Year               int       `json:"year"`
+	OriginalPath       string    `json:"original_path"`
+	QuarantinePath     string    `json:"quarantine_path"`
+	AddImportExclusion bool      `json:"add_import_exclusion"`
+	QuarantinedAt      time.Time `json:"quarantined_at"`
+}
+
+// Age returns how long the entry has been in quarantine
+func (e Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.QuarantinedAt)
+}
+
+// Store manages the quarantine directory and its journal
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore creates a store rooted at dir, creating the directory if needed
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("quarantine directory is not set")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create quarantine directory: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Entries returns all quarantined movies, oldest first
+func (s *Store) Entries() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// Expired returns entries that have been quarantined for longer than retention
+func (s *Store) Expired(retention time.Duration, now time.Time) ([]Entry, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []Entry
+	for _, entry := range entries {
+		if entry.Age(now) >= retention {
+			expired = append(expired, entry)
+		}
+	}
+	return expired, nil
+}
+
+// Contains reports whether a movie of a Radarr instance is in quarantine
+func (s *Store) Contains(instance string, movieID int64) (bool, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(entries, func(e Entry) bool {
+		return e.Instance == instance && e.MovieID == movieID
+	}), nil
+}
+
+// Add moves the movie folder at entry.OriginalPath into quarantine and records it.
+// The returned entry has QuarantinePath and QuarantinedAt filled in. A movie
+// that is already recorded, or whose quarantine folder already exists, is left
+// alone and ErrAlreadyQuarantined returned.
+func (s *Store) Add(entry Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return entry, err
+	}
+
+	if slices.ContainsFunc(entries, func(e Entry) bool {
+		return e.Instance == entry.Instance && e.MovieID == entry.MovieID
+	}) {
+		return entry, fmt.Errorf("%s: %w", entry.Title, ErrAlreadyQuarantined)
+	}
+
+	entry.QuarantinePath = filepath.Join(s.dir, fmt.Sprintf("%d-%s", entry.MovieID, filepath.Base(entry.OriginalPath)))
+	entry.QuarantinedAt = time.Now()
+
+	if _, err := os.Stat(entry.QuarantinePath); err == nil {
+		return entry, fmt.Errorf("%s: %s exists: %w", entry.Title, entry.QuarantinePath, ErrAlreadyQuarantined)
+	}
+
+	if err := moveDir(entry.OriginalPath, entry.QuarantinePath); err != nil {
+		return entry, err
+	}
+
+	entries = append(entries, entry)
+	if err := s.save(entries); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+// Restore moves a quarantined folder back to its original location and forgets it
+func (s *Store) Restore(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(entry.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore %s: %s already exists", entry.Title, entry.OriginalPath)
+	}
+
+	if err := moveDir(entry.QuarantinePath, entry.OriginalPath); err != nil {
+		return err
+	}
+
+	return s.remove(entry)
+}
+
+// Purge permanently deletes a quarantined folder and forgets it
+func (s *Store) Purge(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.RemoveAll(entry.QuarantinePath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", entry.QuarantinePath, err)
+	}
+
+	return s.remove(entry)
+}
+
+// remove drops an entry from the journal. Callers must hold the lock.
+func (s *Store) remove(entry Entry) error {
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	entries = slices.DeleteFunc(entries, func(e Entry) bool {
+		return e.QuarantinePath == entry.QuarantinePath
+	})
+
+	return s.save(entries)
+}
+
+// load reads the journal. Callers must hold the lock.
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, journalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine journal: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse quarantine journal: %w", err)
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return a.QuarantinedAt.Compare(b.QuarantinedAt)
+	})
+	return entries, nil
+}
+
+// save writes the journal atomically. Callers must hold the lock.
+func (s *Store) save(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode quarantine journal: %w", err)
+	}
+
+	path := filepath.Join(s.dir, journalFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write quarantine journal: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write quarantine journal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package quarantine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreLifecycle(t *testing.T) {
+	library := t.TempDir()
+	store, err := NewStore(filepath.Join(t.TempDir(), "quarantine"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	moviePath := filepath.Join(library, "Heat (1995)")
+	if err := os.MkdirAll(filepath.Join(moviePath, "Subs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(moviePath, "Heat.mkv"), []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := store.Add(Entry{MovieID: 7, TMDBID: 949, Title: "Heat", Year: 1995, OriginalPath: moviePath})
+	if err != nil {
+		t.Fatalf("failed to quarantine: %v", err)
+	}
+	if _, err := os.Stat(moviePath); !os.IsNotExist(err) {
+		t.Errorf("expected original folder to be gone, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(entry.QuarantinePath, "Heat.mkv")); err != nil {
+		t.Errorf("expected file in quarantine: %v", err)
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(entries) != 1 || entries[0].TMDBID != 949 {
+		t.Fatalf("expected journal with one entry, got %+v", entries)
+	}
+
+	// Retention is measured from the quarantine time
+	expired, err := store.Expired(24*time.Hour, time.Now())
+	if err != nil || len(expired) != 0 {
+		t.Errorf("expected nothing expired yet, got %d (err %v)", len(expired), err)
+	}
+	expired, err = store.Expired(24*time.Hour, time.Now().Add(48*time.Hour))
+	if err != nil || len(expired) != 1 {
+		t.Errorf("expected one expired entry, got %d (err %v)", len(expired), err)
+	}
+
+	if err := store.Restore(entries[0]); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(moviePath, "Heat.mkv")); err != nil {
+		t.Errorf("expected file restored: %v", err)
+	}
+	if entries, _ := store.Entries(); len(entries) != 0 {
+		t.Errorf("expected empty journal after restore, got %d entries", len(entries))
+	}
+
+	// Quarantine again and purge
+	entry, err = store.Add(Entry{MovieID: 7, Title: "Heat", OriginalPath: moviePath})
+	if err != nil {
+		t.Fatalf("failed to quarantine again: %v", err)
+	}
+	if err := store.Purge(entry); err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+	if _, err := os.Stat(entry.QuarantinePath); !os.IsNotExist(err) {
+		t.Errorf("expected quarantined folder removed, stat err = %v", err)
+	}
+	if entries, _ := store.Entries(); len(entries) != 0 {
+		t.Errorf("expected empty journal after purge, got %d entries", len(entries))
+	}
+}
+
+func TestStoreAddTwice(t *testing.T) {
+	library := t.TempDir()
+	store, err := NewStore(filepath.Join(t.TempDir(), "quarantine"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	moviePath := filepath.Join(library, "Heat (1995)")
+	if err := os.MkdirAll(moviePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := store.Add(Entry{MovieID: 7, Instance: "main", Title: "Heat", OriginalPath: moviePath})
+	if err != nil {
+		t.Fatalf("failed to quarantine: %v", err)
+	}
+	if ok, err := store.Contains("main", 7); err != nil || !ok {
+		t.Errorf("expected the movie to be quarantined, got %v (err %v)", ok, err)
+	}
+	if ok, _ := store.Contains("4k", 7); ok {
+		t.Error("expected the same movie ID of another instance not to be quarantined")
+	}
+
+	// The same movie matching again leaves the quarantined copy alone
+	if _, err := store.Add(Entry{MovieID: 7, Instance: "main", Title: "Heat", OriginalPath: moviePath}); !errors.Is(err, ErrAlreadyQuarantined) {
+		t.Errorf("expected ErrAlreadyQuarantined, got %v", err)
+	}
+
+	// So does a leftover folder that the journal lost track of
+	if err := os.MkdirAll(moviePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Entry{MovieID: 7, Instance: "other", Title: "Heat", OriginalPath: moviePath}); !errors.Is(err, ErrAlreadyQuarantined) {
+		t.Errorf("expected ErrAlreadyQuarantined for an existing quarantine folder, got %v", err)
+	}
+	if _, err := os.Stat(moviePath); err != nil {
+		t.Errorf("expected the library folder to be left in place: %v", err)
+	}
+
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 || entries[0].QuarantinePath != entry.QuarantinePath {
+		t.Errorf("expected the journal to keep only the first entry, got %+v (err %v)", entries, err)
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "extras"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "extras", "trailer.mkv"), []byte("trailer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("copyDir failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "extras", "trailer.mkv"))
+	if err != nil || string(data) != "trailer" {
+		t.Errorf("expected copied file contents, got %q (err %v)", data, err)
+	}
+}
@@ -0,0 +1,102 @@
+package radarr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"golift.io/starr"
+	"golift.io/starr/radarr"
+)
+
+// UnmonitorMovies marks movies as unmonitored so Radarr stops searching for upgrades
+func (o *Operations) UnmonitorMovies(ctx context.Context, movies []MovieInfo) error {
+	return o.updateMovies(ctx, movies, "unmonitor", func(movie *radarr.Movie) {
+		movie.Monitored = false
+	})
+}
+
+// TagMovies adds a tag to movies, creating the tag in Radarr if needed
+func (o *Operations) TagMovies(ctx context.Context, movies []MovieInfo, tagName string) error {
+	tag, err := o.client.GetOrCreateTag(ctx, tagName)
+	if err != nil {
+		return err
+	}
+
+	return o.updateMovies(ctx, movies, "tag", func(movie *radarr.Movie) {
+		if !slices.Contains(movie.Tags, tag.ID) {
+			movie.Tags = append(movie.Tags, tag.ID)
+		}
+	})
+}
+
+// UntagMovies removes a tag from movies. Nothing happens if the tag doesn't exist.
+func (o *Operations) UntagMovies(ctx context.Context, movies []MovieInfo, tagName string) error {
+	tags, err := o.client.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(tags, func(tag *starr.Tag) bool { return tag.Label == tagName })
+	if index < 0 {
+		return nil
+	}
+	tagID := tags[index].ID
+
+	return o.updateMovies(ctx, movies, "untag", func(movie *radarr.Movie) {
+		movie.Tags = slices.DeleteFunc(movie.Tags, func(id int) bool { return id == tagID })
+	})
+}
+
+// ChangeQualityProfile moves movies to the named quality profile
+func (o *Operations) ChangeQualityProfile(ctx context.Context, movies []MovieInfo, profileName string) error {
+	profile, err := o.client.GetQualityProfileByName(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	return o.updateMovies(ctx, movies, "change quality profile of", func(movie *radarr.Movie) {
+		movie.QualityProfileID = profile.ID
+	})
+}
+
+// updateMovies fetches each movie, applies the change and saves it back to Radarr
+func (o *Operations) updateMovies(ctx context.Context, movies []MovieInfo, action string, apply func(*radarr.Movie)) error {
+	var failed int
+	for _, info := range movies {
+		if err := o.updateMovie(ctx, info.ID, apply); err != nil {
+			failed++
+			o.logger.Error().
+				Err(err).
+				Int64("id", info.ID).
+				Str("title", info.Title).
+				Msgf("Failed to %s movie", action)
+		}
+	}
+
+	o.logger.Info().
+		Int("updated", len(movies)-failed).
+		Int("failed", failed).
+		Msgf("Finished: %s", action)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d movies", action, failed)
+	}
+
+	return nil
+}
+
+// updateMovie applies a change to a single movie
+func (o *Operations) updateMovie(ctx context.Context, movieID int64, apply func(*radarr.Movie)) error {
+	movie, err := o.client.GetMovieByID(ctx, movieID)
+	if err != nil {
+		return err
+	}
+	if movie == nil {
+		return fmt.Errorf("movie ID %d not found", movieID)
+	}
+
+	apply(movie)
+	_, err = o.client.UpdateMovie(ctx, movie)
+	return err
+}
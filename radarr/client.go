@@ -3,6 +3,8 @@ package radarr
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -90,18 +92,59 @@ func (c *Client) GetTags(ctx context.Context) ([]*starr.Tag, error) {
 	return tags, nil
 }
 
-// DeleteMovie deletes a movie and its files from Radarr
-func (c *Client) DeleteMovie(ctx context.Context, movieID int64) error {
-	err := c.api.DeleteMovieContext(ctx, movieID, true, false)
+// DeleteMovie deletes a movie from Radarr, optionally with its files and an import exclusion
+func (c *Client) DeleteMovie(ctx context.Context, movieID int64, deleteFiles, addImportExclusion bool) error {
+	err := c.api.DeleteMovieContext(ctx, movieID, deleteFiles, addImportExclusion)
 	if err != nil {
 		return fmt.Errorf("failed to delete movie ID %d: %w", movieID, err)
 	}
 
 	c.logger.Info().Int64("movie_id", movieID).
+		Bool("delete_files", deleteFiles).
+		Bool("import_exclusion", addImportExclusion).
 		Msg("Successfully deleted movie")
 	return nil
 }
 
+// AddMovie adds a movie to Radarr
+func (c *Client) AddMovie(ctx context.Context, input *radarr.AddMovieInput) (*radarr.Movie, error) {
+	movie, err := c.api.AddMovieContext(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add movie %s: %w", input.Title, err)
+	}
+
+	c.logger.Info().Int64("movie_id", movie.ID).Int64("tmdb_id", input.TmdbID).
+		Str("root_folder", input.RootFolderPath).
+		Msg("Successfully added movie")
+	return movie, nil
+}
+
+// RemoveImportExclusion removes the import exclusion for a TMDB ID.
+// It returns false if the movie had no exclusion.
+func (c *Client) RemoveImportExclusion(ctx context.Context, tmdbID int64) (bool, error) {
+	exclusions, err := c.api.GetExclusionsContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get import exclusions: %w", err)
+	}
+
+	var ids []int64
+	for _, exclusion := range exclusions {
+		if exclusion.TMDBID == tmdbID {
+			ids = append(ids, exclusion.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	if err := c.api.DeleteExclusionsContext(ctx, ids); err != nil {
+		return false, fmt.Errorf("failed to delete import exclusion for TMDB ID %d: %w", tmdbID, err)
+	}
+
+	c.logger.Info().Int64("tmdb_id", tmdbID).Msg("Removed import exclusion")
+	return true, nil
+}
+
 // DeleteMovieFiles deletes movie files by their IDs (without deleting the movie entry)
 func (c *Client) DeleteMovieFiles(ctx context.Context, movieFileIDs ...int64) error {
 	err := c.api.DeleteMovieFilesContext(ctx, movieFileIDs...)
@@ -142,6 +185,51 @@ func (c *Client) GetTagByName(ctx context.Context, tagName string) (*starr.Tag,
 	return nil, fmt.Errorf("tag not found: %s", tagName)
 }
 
+// GetOrCreateTag finds a tag by its label, creating it if it doesn't exist yet
+func (c *Client) GetOrCreateTag(ctx context.Context, tagName string) (*starr.Tag, error) {
+	if tag, err := c.GetTagByName(ctx, tagName); err == nil {
+		return tag, nil
+	}
+
+	tag, err := c.api.AddTagContext(ctx, &starr.Tag{Label: tagName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %s: %w", tagName, err)
+	}
+
+	// Invalidate the cache so the new tag is picked up
+	c.tagCacheMutex.Lock()
+	c.tagCache = nil
+	c.tagCacheMutex.Unlock()
+
+	c.logger.Info().Str("tag", tagName).Int("tag_id", tag.ID).Msg("Created tag")
+	return tag, nil
+}
+
+// GetQualityProfiles retrieves all quality profiles from Radarr
+func (c *Client) GetQualityProfiles(ctx context.Context) ([]*radarr.QualityProfile, error) {
+	profiles, err := c.api.GetQualityProfilesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// GetQualityProfileByName finds a quality profile by its name
+func (c *Client) GetQualityProfileByName(ctx context.Context, name string) (*radarr.QualityProfile, error) {
+	profiles, err := c.GetQualityProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+
+	return nil, fmt.Errorf("quality profile not found: %s", name)
+}
+
 // GetManualImportItems scans a folder for importable movie files
 // Note: The starr library's ManualImport method returns a single output, but the actual
 // Radarr API returns an array. We need to work around this limitation.
@@ -177,21 +265,50 @@ func (c *Client) ProcessManualImport(ctx context.Context, items []*radarr.Manual
 	return nil
 }
 
+// MovieKey identifies a movie across Radarr instances, whose movie IDs overlap
+type MovieKey struct {
+	Instance string
+	ID       int64
+}
+
 // MovieInfo contains relevant movie information for filtering and display
 type MovieInfo struct {
-	ID             int64
-	Title          string
-	Year           int
-	TMDBID         int64
-	IMDBID         string
-	Path           string
-	Tags           []int
-	TagNames       []string
-	Added          time.Time
-	MonitoredSince time.Time
-	MovieFile      *radarr.MovieFile
-	HasFile        bool
-	FileImported   time.Time
+	ID               int64
+	Instance         string // Name of the Radarr instance the movie is in, set by Operations
+	Title            string
+	Year             int
+	TMDBID           int64
+	IMDBID           string
+	Path             string
+	RootFolder       string
+	Tags             []int
+	TagNames         []string
+	Monitored        bool
+	QualityProfileID int64
+	QualityProfile   string // Name of the quality profile, resolved by GetAllMovies
+	Availability     string // Radarr's minimum availability for the movie
+	Added            time.Time
+	MonitoredSince   time.Time
+	MovieFile        *radarr.MovieFile
+	HasFile          bool
+	FileImported     time.Time
+	// Radarr metadata
+	Genres           []string
+	Studio           string
+	Certification    string
+	OriginalLanguage string
+	Runtime          int // Minutes
+	CollectionName   string
+	CollectionTMDBID int64
+	InCinemas        time.Time
+	DigitalRelease   time.Time
+	PhysicalRelease  time.Time
+	// Summary of every movie in the same TMDB collection, filled by filter.AnnotateCollections
+	CollectionSize         int
+	CollectionLastWatched  time.Time
+	CollectionAnyRequested bool
+	// Instances that have this movie with a file, filled by filter.AnnotateInstances
+	Instances []string
 	// Watch status fields (aggregate across all users)
 	Watched       bool
 	WatchCount    int
@@ -220,6 +337,11 @@ type MovieInfo struct {
 	AlternateTorrents []*qbittorrent.TorrentMatch
 }
 
+// Key returns the movie's identity across Radarr instances
+func (m MovieInfo) Key() MovieKey {
+	return MovieKey{Instance: m.Instance, ID: m.ID}
+}
+
 // UserWatchInfo contains watch information for a specific user
 type UserWatchInfo struct {
 	Username    string
@@ -232,20 +354,42 @@ type UserWatchInfo struct {
 // GetMovieInfo converts a Radarr movie to our MovieInfo struct
 func (c *Client) GetMovieInfo(movie *radarr.Movie, tags []*starr.Tag) MovieInfo {
 	info := MovieInfo{
-		ID:             movie.ID,
-		Title:          movie.Title,
-		Year:           movie.Year,
-		TMDBID:         movie.TmdbID,
-		IMDBID:         movie.ImdbID,
-		Path:           movie.Path,
-		Tags:           movie.Tags,
-		TagNames:       make([]string, 0),
-		Added:          movie.Added,
-		MonitoredSince: movie.Added,
-		HasFile:        movie.HasFile,
-		UserWatchData:  make(map[string]*UserWatchInfo),
-		Ratings:        make(map[string]float64),
-		Popularity:     movie.Popularity,
+		ID:               movie.ID,
+		Title:            movie.Title,
+		Year:             movie.Year,
+		TMDBID:           movie.TmdbID,
+		IMDBID:           movie.ImdbID,
+		Path:             movie.Path,
+		Tags:             movie.Tags,
+		Monitored:        movie.Monitored,
+		QualityProfileID: movie.QualityProfileID,
+		Availability:     string(movie.MinimumAvailability),
+		TagNames:         make([]string, 0),
+		Added:            movie.Added,
+		MonitoredSince:   movie.Added,
+		HasFile:          movie.HasFile,
+		UserWatchData:    make(map[string]*UserWatchInfo),
+		Ratings:          make(map[string]float64),
+		Popularity:       movie.Popularity,
+		Genres:           movie.Genres,
+		Studio:           movie.Studio,
+		Certification:    movie.Certification,
+		Runtime:          movie.Runtime,
+		InCinemas:        movie.InCinemas,
+		DigitalRelease:   movie.DigitalRelease,
+		PhysicalRelease:  movie.PhysicalRelease,
+	}
+
+	if movie.OriginalLanguage != nil {
+		info.OriginalLanguage = movie.OriginalLanguage.Name
+	}
+	if movie.Collection != nil {
+		info.CollectionName = movie.Collection.Name
+		info.CollectionTMDBID = movie.Collection.TmdbID
+	}
+
+	if movie.Path != "" {
+		info.RootFolder = filepath.Dir(movie.Path)
 	}
 
 	// Map tag IDs to names
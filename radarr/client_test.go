@@ -2,12 +2,19 @@ package radarr
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/rs/zerolog"
 	"golift.io/starr"
 	"golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/journal"
+	"github.com/s0up4200/arrbiter/quarantine"
 )
 
 // mockRadarrAPI implements RadarrAPI for testing
@@ -15,8 +22,15 @@ type mockRadarrAPI struct {
 	movies          []*radarr.Movie
 	tags            []*starr.Tag
 	customFormats   []*radarr.CustomFormatOutput
+	qualityProfiles []*radarr.QualityProfile
 	movieFiles      map[int64]*radarr.MovieFile
 	deleteFileFlags []bool
+	exclusions      []*radarr.Exclusion
+	addedMovies     []*radarr.AddMovieInput
+	rootFolders     []*radarr.RootFolder
+	diskSpace       []DiskSpace
+	commandErr      error
+	deleteErr       error
 
 	// Track calls for verification
 	getMovieCalls int
@@ -42,6 +56,9 @@ func (m *mockRadarrAPI) UpdateMovieContext(ctx context.Context, movieID int64, m
 }
 
 func (m *mockRadarrAPI) DeleteMovieContext(ctx context.Context, movieID int64, deleteFiles, addImportExclusion bool) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
 	m.deleteFileFlags = append(m.deleteFileFlags, deleteFiles)
 	return nil
 }
@@ -62,11 +79,60 @@ func (m *mockRadarrAPI) GetTagsContext(ctx context.Context) ([]*starr.Tag, error
 	return m.tags, nil
 }
 
+func (m *mockRadarrAPI) AddTagContext(ctx context.Context, tag *starr.Tag) (*starr.Tag, error) {
+	created := &starr.Tag{ID: len(m.tags) + 1, Label: tag.Label}
+	m.tags = append(m.tags, created)
+	return created, nil
+}
+
+func (m *mockRadarrAPI) AddMovieContext(ctx context.Context, movie *radarr.AddMovieInput) (*radarr.Movie, error) {
+	m.addedMovies = append(m.addedMovies, movie)
+	added := &radarr.Movie{
+		ID:               int64(len(m.movies) + 1000),
+		Title:            movie.Title,
+		TmdbID:           movie.TmdbID,
+		QualityProfileID: movie.QualityProfileID,
+		Tags:             movie.Tags,
+		Monitored:        movie.Monitored,
+	}
+	m.movies = append(m.movies, added)
+	return added, nil
+}
+
+func (m *mockRadarrAPI) GetExclusionsContext(ctx context.Context) ([]*radarr.Exclusion, error) {
+	return m.exclusions, nil
+}
+
+func (m *mockRadarrAPI) DeleteExclusionsContext(ctx context.Context, ids []int64) error {
+	m.exclusions = slices.DeleteFunc(m.exclusions, func(e *radarr.Exclusion) bool {
+		return slices.Contains(ids, e.ID)
+	})
+	return nil
+}
+
+func (m *mockRadarrAPI) GetRootFoldersContext(ctx context.Context) ([]*radarr.RootFolder, error) {
+	return m.rootFolders, nil
+}
+
+func (m *mockRadarrAPI) GetInto(ctx context.Context, req starr.Request, output any) error {
+	if disks, ok := output.(*[]DiskSpace); ok && req.URI == bpDiskSpace {
+		*disks = m.diskSpace
+	}
+	return nil
+}
+
+func (m *mockRadarrAPI) GetQualityProfilesContext(ctx context.Context) ([]*radarr.QualityProfile, error) {
+	return m.qualityProfiles, nil
+}
+
 func (m *mockRadarrAPI) GetCustomFormatsContext(ctx context.Context) ([]*radarr.CustomFormatOutput, error) {
 	return m.customFormats, nil
 }
 
 func (m *mockRadarrAPI) SendCommandContext(ctx context.Context, cmd *radarr.CommandRequest) (*radarr.CommandResponse, error) {
+	if m.commandErr != nil {
+		return nil, m.commandErr
+	}
 	return &radarr.CommandResponse{
 		ID:     1,
 		Name:   cmd.Name,
@@ -282,6 +348,55 @@ func TestClient_GetMovieInfo(t *testing.T) {
 	}
 }
 
+func TestGetAllMoviesMetadata(t *testing.T) {
+	released := time.Date(2019, 10, 4, 0, 0, 0, 0, time.UTC)
+	mockAPI := &mockRadarrAPI{
+		movies: []*radarr.Movie{{
+			ID:               1,
+			Title:            "Joker",
+			Path:             "/movies/Joker (2019)",
+			Genres:           []string{"Crime", "Thriller"},
+			Studio:           "Warner Bros. Pictures",
+			Certification:    "R",
+			Runtime:          122,
+			OriginalLanguage: &starr.Value{ID: 1, Name: "English"},
+			Collection:       &radarr.Collection{Name: "Joker Collection", TmdbID: 1198117},
+			InCinemas:        released,
+			Monitored:        true,
+			QualityProfileID: 4,
+			MovieFile:        &radarr.MovieFile{ID: 10, DateAdded: released},
+		}},
+		qualityProfiles: []*radarr.QualityProfile{{ID: 4, Name: "HD-1080p"}},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	movies, err := ops.GetAllMovies(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllMovies failed: %v", err)
+	}
+	if len(movies) != 1 {
+		t.Fatalf("expected 1 movie, got %d", len(movies))
+	}
+
+	movie := movies[0]
+	if len(movie.Genres) != 2 || movie.Studio != "Warner Bros. Pictures" || movie.Certification != "R" || movie.Runtime != 122 {
+		t.Errorf("metadata not carried over: %+v", movie)
+	}
+	if movie.OriginalLanguage != "English" {
+		t.Errorf("expected original language English, got %q", movie.OriginalLanguage)
+	}
+	if movie.CollectionName != "Joker Collection" || movie.CollectionTMDBID != 1198117 {
+		t.Errorf("expected collection to be set, got %q (%d)", movie.CollectionName, movie.CollectionTMDBID)
+	}
+	if !movie.InCinemas.Equal(released) || !movie.Monitored {
+		t.Errorf("expected release date and monitored flag, got %v / %v", movie.InCinemas, movie.Monitored)
+	}
+	if movie.QualityProfile != "HD-1080p" || movie.RootFolder != "/movies" {
+		t.Errorf("expected quality profile HD-1080p in /movies, got %q in %q", movie.QualityProfile, movie.RootFolder)
+	}
+}
+
 func TestBatchDeleteMovies(t *testing.T) {
 	mockAPI := &mockRadarrAPI{}
 	logger := zerolog.New(nil).Level(zerolog.Disabled)
@@ -294,7 +409,7 @@ func TestBatchDeleteMovies(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	result := client.BatchDeleteMovies(ctx, movies)
+	result := client.BatchDeleteMovies(ctx, movies, DeleteOptions{})
 
 	if result.Requested != 3 {
 		t.Errorf("expected 3 requested deletions, got %d", result.Requested)
@@ -314,3 +429,317 @@ func TestBatchDeleteMovies(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterActions(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		movies: []*radarr.Movie{
+			{ID: 1, Title: "Movie 1", Monitored: true, QualityProfileID: 1},
+			{ID: 2, Title: "Movie 2", Monitored: true, QualityProfileID: 1, Tags: []int{1}},
+		},
+		tags:            []*starr.Tag{{ID: 1, Label: "existing"}},
+		qualityProfiles: []*radarr.QualityProfile{{ID: 1, Name: "Any"}, {ID: 4, Name: "HD-720p"}},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+	ctx := context.Background()
+	movies := []MovieInfo{{ID: 1, Title: "Movie 1"}, {ID: 2, Title: "Movie 2"}}
+
+	if err := ops.TagMovies(ctx, movies, "leaving-soon"); err != nil {
+		t.Fatalf("TagMovies failed: %v", err)
+	}
+	if len(mockAPI.tags) != 2 {
+		t.Fatalf("expected tag to be created, got %d tags", len(mockAPI.tags))
+	}
+	for _, movie := range mockAPI.movies {
+		if !slices.Contains(movie.Tags, 2) {
+			t.Errorf("expected %s to be tagged, got tags %v", movie.Title, movie.Tags)
+		}
+	}
+
+	if err := ops.ChangeQualityProfile(ctx, movies, "hd-720p"); err != nil {
+		t.Fatalf("ChangeQualityProfile failed: %v", err)
+	}
+	if err := ops.UnmonitorMovies(ctx, movies); err != nil {
+		t.Fatalf("UnmonitorMovies failed: %v", err)
+	}
+	for _, movie := range mockAPI.movies {
+		if movie.QualityProfileID != 4 {
+			t.Errorf("expected %s to use profile 4, got %d", movie.Title, movie.QualityProfileID)
+		}
+		if movie.Monitored {
+			t.Errorf("expected %s to be unmonitored", movie.Title)
+		}
+	}
+
+	if err := ops.ChangeQualityProfile(ctx, movies, "missing"); err == nil {
+		t.Error("expected error for unknown quality profile")
+	}
+	if err := ops.UnmonitorMovies(ctx, []MovieInfo{{ID: 99, Title: "Unknown"}}); err == nil {
+		t.Error("expected error for unknown movie")
+	}
+}
+
+func TestDeleteMoviesWritesJournal(t *testing.T) {
+	mockAPI := &mockRadarrAPI{}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	j := journal.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	ops.SetJournal(j)
+
+	movies := []MovieInfo{{
+		ID:          1,
+		Title:       "Heat",
+		TMDBID:      949,
+		Path:        "/movies/Heat (1995)",
+		MovieFile:   &radarr.MovieFile{Size: 1 << 30},
+		WatchCount:  1,
+		IsRequested: true,
+		RequestedBy: "john",
+		UserWatchData: map[string]*UserWatchInfo{
+			"john": {Watched: true},
+		},
+	}}
+
+	err := ops.DeleteMovies(context.Background(), movies, DeleteOptions{
+		FilterName:       "old_unwatched",
+		FilterExpression: "not Watched",
+	})
+	if err != nil {
+		t.Fatalf("DeleteMovies failed: %v", err)
+	}
+
+	records, err := j.Read(journal.Query{})
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 journal record, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Action != journal.ActionDelete || record.Outcome != journal.OutcomeSuccess {
+		t.Errorf("unexpected action/outcome: %s/%s", record.Action, record.Outcome)
+	}
+	if record.Filter != "old_unwatched" || record.Expression != "not Watched" {
+		t.Errorf("expected filter details recorded, got %q / %q", record.Filter, record.Expression)
+	}
+	if record.TMDBID != 949 || record.SizeBytes != 1<<30 || record.Path != "/movies/Heat (1995)" {
+		t.Errorf("expected movie details recorded, got %+v", record)
+	}
+	if record.Watch == nil || len(record.Watch.WatchedBy) != 1 || record.Watch.WatchedBy[0] != "john" {
+		t.Errorf("expected watch snapshot, got %+v", record.Watch)
+	}
+	if record.Request == nil || record.Request.RequestedBy != "john" {
+		t.Errorf("expected request snapshot, got %+v", record.Request)
+	}
+}
+
+func TestQuarantineMovieTwice(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		movies: []*radarr.Movie{{ID: 1, Title: "Heat", Monitored: true}},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	j := journal.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	ops.SetJournal(j)
+
+	store, err := quarantine.NewStore(filepath.Join(t.TempDir(), "quarantine"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	moviePath := filepath.Join(t.TempDir(), "Heat (1995)")
+	if err := os.MkdirAll(moviePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	movies := []MovieInfo{{ID: 1, Title: "Heat", Year: 1995, Path: moviePath, Monitored: true}}
+	for run := range 2 {
+		if err := ops.QuarantineMovies(context.Background(), movies, store, DeleteOptions{}); err != nil {
+			t.Fatalf("run %d: QuarantineMovies failed: %v", run+1, err)
+		}
+		if mockAPI.movies[0].Monitored {
+			t.Fatalf("run %d: expected the movie to stay unmonitored", run+1)
+		}
+	}
+
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected one quarantine entry, got %d (err %v)", len(entries), err)
+	}
+	records, err := j.Read(journal.Query{})
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(records) != 1 || records[0].Outcome != journal.OutcomeSuccess {
+		t.Errorf("expected a single successful journal record, got %+v", records)
+	}
+}
+
+func TestPurgeQuarantinedMovies(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		movies: []*radarr.Movie{{ID: 1, Title: "Heat", Monitored: true}},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	j := journal.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	ops.SetJournal(j)
+
+	store, err := quarantine.NewStore(filepath.Join(t.TempDir(), "quarantine"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	moviePath := filepath.Join(t.TempDir(), "Heat (1995)")
+	if err := os.MkdirAll(moviePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	movies := []MovieInfo{{ID: 1, Title: "Heat", Year: 1995, Path: moviePath, Monitored: true}}
+	if err := ops.QuarantineMovies(context.Background(), movies, store, DeleteOptions{}); err != nil {
+		t.Fatalf("QuarantineMovies failed: %v", err)
+	}
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one quarantine entry, got %d (err %v)", len(entries), err)
+	}
+
+	// A movie Radarr fails to remove keeps its files and entry
+	mockAPI.deleteErr = errors.New("radarr is down")
+	if err := ops.PurgeQuarantinedMovies(context.Background(), entries, store); err == nil {
+		t.Error("expected the purge to fail")
+	}
+	if _, err := os.Stat(entries[0].QuarantinePath); err != nil {
+		t.Errorf("expected the quarantined folder to be kept: %v", err)
+	}
+	if kept, err := store.Contains("", 1); err != nil || !kept {
+		t.Errorf("expected the quarantine entry to be kept (err %v)", err)
+	}
+
+	mockAPI.deleteErr = nil
+	if err := ops.PurgeQuarantinedMovies(context.Background(), entries, store); err != nil {
+		t.Fatalf("PurgeQuarantinedMovies failed: %v", err)
+	}
+	if _, err := os.Stat(entries[0].QuarantinePath); !os.IsNotExist(err) {
+		t.Errorf("expected the quarantined folder to be deleted, got %v", err)
+	}
+
+	records, err := j.Read(journal.Query{Action: journal.ActionPurge})
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(records) != 2 || records[0].Outcome != journal.OutcomeFailed || records[1].Outcome != journal.OutcomeSuccess {
+		t.Errorf("expected a failed and a successful purge record, got %+v", records)
+	}
+}
+
+func TestUndoDeletion(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		tags:        []*starr.Tag{{ID: 1, Label: "keep"}},
+		exclusions:  []*radarr.Exclusion{{ID: 7, TMDBID: 949, Title: "Heat"}},
+		rootFolders: []*radarr.RootFolder{{Path: "/data"}, {Path: "/data/movies"}},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	j := journal.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	ops.SetJournal(j)
+
+	movies := []MovieInfo{{
+		ID:               1,
+		Title:            "Heat",
+		Year:             1995,
+		TMDBID:           949,
+		Path:             "/data/movies/H/Heat (1995)",
+		RootFolder:       "/data/movies/H",
+		TagNames:         []string{"keep", "leaving-soon", "4k"},
+		Monitored:        true,
+		QualityProfileID: 4,
+		Availability:     "announced",
+	}}
+	if err := ops.DeleteMovies(context.Background(), movies, DeleteOptions{AddImportExclusion: true}); err != nil {
+		t.Fatalf("DeleteMovies failed: %v", err)
+	}
+
+	records, err := j.Read(journal.Query{Action: journal.ActionDelete})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected 1 delete record, got %d (%v)", len(records), err)
+	}
+
+	movie, err := ops.UndoDeletion(context.Background(), records[0], UndoOptions{DropTags: []string{"Leaving-Soon"}})
+	if err != nil {
+		t.Fatalf("UndoDeletion failed: %v", err)
+	}
+	if movie == nil {
+		t.Fatal("expected the re-added movie")
+	}
+
+	if len(mockAPI.addedMovies) != 1 {
+		t.Fatalf("expected 1 movie added, got %d", len(mockAPI.addedMovies))
+	}
+	added := mockAPI.addedMovies[0]
+	if added.TmdbID != 949 || added.QualityProfileID != 4 || !added.Monitored {
+		t.Errorf("movie not re-added with its original settings: %+v", added)
+	}
+	if added.RootFolderPath != "/data/movies" || added.MinimumAvailability != radarr.AvailabilityAnnounced {
+		t.Errorf("expected Radarr's root folder and the minimum availability, got %q and %q", added.RootFolderPath, added.MinimumAvailability)
+	}
+	if len(added.Tags) != 2 || added.Tags[0] != 1 {
+		t.Errorf("expected existing and recreated tags without the dropped one, got %v", added.Tags)
+	}
+	if slices.ContainsFunc(mockAPI.tags, func(tag *starr.Tag) bool { return tag.Label == "leaving-soon" }) {
+		t.Error("expected the dropped tag not to be created")
+	}
+	if len(mockAPI.exclusions) != 0 {
+		t.Errorf("expected import exclusion to be removed, got %d left", len(mockAPI.exclusions))
+	}
+
+	undos, err := j.Read(journal.Query{Action: journal.ActionUndo})
+	if err != nil || len(undos) != 1 || undos[0].Outcome != journal.OutcomeSuccess {
+		t.Errorf("expected a successful undo record, got %+v (%v)", undos, err)
+	}
+
+	// A record without Radarr settings can't be undone
+	if _, err := ops.UndoDeletion(context.Background(), journal.Record{Action: journal.ActionDelete, Title: "Old"}, UndoOptions{}); err == nil {
+		t.Error("expected an error for a record without Radarr settings")
+	}
+}
+
+func TestDiskSpaceTarget(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		diskSpace: []DiskSpace{
+			{Path: "/", FreeSpace: 50 << 30, TotalSpace: 100 << 30},
+			{Path: "/data", FreeSpace: 100 << 30, TotalSpace: 1000 << 30},
+		},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	client := NewClientWithAPI(mockAPI, logger)
+
+	disk, err := client.GetDiskSpace(context.Background(), "/data/movies")
+	if err != nil {
+		t.Fatalf("GetDiskSpace failed: %v", err)
+	}
+	if disk.Path != "/data" {
+		t.Errorf("expected the /data disk, got %s", disk.Path)
+	}
+	if got := disk.BytesToReachFree(15); got != 50<<30 {
+		t.Errorf("expected 50 GiB needed for 15%% free, got %d", got)
+	}
+	if got := disk.BytesToReachFree(5); got != 0 {
+		t.Errorf("expected nothing needed when already free enough, got %d", got)
+	}
+
+	movies := []MovieInfo{
+		{ID: 1, MovieFile: &radarr.MovieFile{Size: 30 << 30}},
+		{ID: 2},
+		{ID: 3, MovieFile: &radarr.MovieFile{Size: 25 << 30}},
+		{ID: 4, MovieFile: &radarr.MovieFile{Size: 10 << 30}},
+	}
+	selected, reclaimed := SelectToReclaim(movies, 50<<30)
+	if len(selected) != 2 || selected[0].ID != 1 || selected[1].ID != 3 {
+		t.Errorf("expected movies 1 and 3 to be selected, got %+v", selected)
+	}
+	if reclaimed != 55<<30 {
+		t.Errorf("expected 55 GiB reclaimed, got %d", reclaimed)
+	}
+}
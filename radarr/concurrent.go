@@ -76,7 +76,7 @@ func (c *Client) ProcessMovieFiles(ctx context.Context, movies []*radarr.Movie)
 }
 
 // BatchDeleteMovies deletes movies in batches with proper error aggregation
-func (c *Client) BatchDeleteMovies(ctx context.Context, movies []MovieInfo) BatchDeleteResult {
+func (c *Client) BatchDeleteMovies(ctx context.Context, movies []MovieInfo, opts DeleteOptions) BatchDeleteResult {
 	result := BatchDeleteResult{
 		Requested: len(movies),
 	}
@@ -97,7 +97,7 @@ func (c *Client) BatchDeleteMovies(ctx context.Context, movies []MovieInfo) Batc
 		currentMovie := movie
 
 		g.Go(func() error {
-			err := c.DeleteMovie(ctx, currentMovie.ID)
+			err := c.DeleteMovie(ctx, currentMovie.ID, !opts.KeepFiles, opts.AddImportExclusion)
 			if err != nil {
 				errorChan <- DeleteError{
 					MovieID:    currentMovie.ID,
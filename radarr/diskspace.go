@@ -0,0 +1,118 @@
+package radarr
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golift.io/starr"
+)
+
+// bpDiskSpace is the Radarr endpoint reporting space on every mounted disk
+const bpDiskSpace = "v3/diskspace"
+
+// DiskSpace is the free and total space of a disk Radarr can see
+type DiskSpace struct {
+	Path       string `json:"path"`
+	Label      string `json:"label"`
+	FreeSpace  int64  `json:"freeSpace"`
+	TotalSpace int64  `json:"totalSpace"`
+}
+
+// FreePercent returns the share of the disk that is free
+func (d DiskSpace) FreePercent() float64 {
+	if d.TotalSpace <= 0 {
+		return 0
+	}
+	return float64(d.FreeSpace) / float64(d.TotalSpace) * 100
+}
+
+// BytesToReachFree returns how many bytes must be freed for the disk to be
+// percent free, or 0 if it already is
+func (d DiskSpace) BytesToReachFree(percent float64) int64 {
+	needed := int64(float64(d.TotalSpace)*percent/100) - d.FreeSpace
+	return max(needed, 0)
+}
+
+// GetRootFolders retrieves the root folder paths configured in Radarr
+func (c *Client) GetRootFolders(ctx context.Context) ([]string, error) {
+	folders, err := c.api.GetRootFoldersContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get root folders: %w", err)
+	}
+
+	paths := make([]string, 0, len(folders))
+	for _, folder := range folders {
+		paths = append(paths, filepath.Clean(folder.Path))
+	}
+	return paths, nil
+}
+
+// GetDiskSpace finds the disk a path lives on, going by the longest matching mount path
+func (c *Client) GetDiskSpace(ctx context.Context, path string) (DiskSpace, error) {
+	var disks []DiskSpace
+	if err := c.api.GetInto(ctx, starr.Request{URI: bpDiskSpace}, &disks); err != nil {
+		return DiskSpace{}, fmt.Errorf("failed to get disk space: %w", err)
+	}
+
+	path = filepath.Clean(path)
+	var best DiskSpace
+	for _, disk := range disks {
+		if !isWithin(path, disk.Path) || len(disk.Path) <= len(best.Path) {
+			continue
+		}
+		best = disk
+	}
+
+	if best.Path == "" {
+		return DiskSpace{}, fmt.Errorf("no disk found for %s", path)
+	}
+
+	c.logger.Debug().Str("path", path).Str("disk", best.Path).
+		Int64("free", best.FreeSpace).Int64("total", best.TotalSpace).
+		Msg("Resolved disk space")
+	return best, nil
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	dir = filepath.Clean(dir)
+	if path == dir || dir == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// SelectToReclaim takes movies in order until their files add up to target bytes.
+// Movies without a file free nothing and are skipped.
+func SelectToReclaim(movies []MovieInfo, target int64) (selected []MovieInfo, reclaimed int64) {
+	for _, movie := range movies {
+		if reclaimed >= target {
+			break
+		}
+		if movie.MovieFile == nil || movie.MovieFile.Size <= 0 {
+			continue
+		}
+		selected = append(selected, movie)
+		reclaimed += movie.MovieFile.Size
+	}
+	return selected, reclaimed
+}
+
+// InRootFolder reports whether a movie lives under a root folder
+func (m MovieInfo) InRootFolder(root string) bool {
+	return m.Path != "" && isWithin(filepath.Clean(m.Path), root)
+}
+
+// radarrRootFolder returns the most specific of Radarr's root folders the
+// movie lives under, or an empty string when it is under none of them
+func (m MovieInfo) radarrRootFolder(roots []string) string {
+	var best string
+	for _, root := range roots {
+		if len(root) > len(best) && m.InRootFolder(root) {
+			best = root
+		}
+	}
+	return best
+}
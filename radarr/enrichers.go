@@ -5,40 +5,45 @@ import (
 	"fmt"
 
 	"github.com/s0up4200/arrbiter/overseerr"
-	"github.com/s0up4200/arrbiter/tautulli"
+	"github.com/s0up4200/arrbiter/watch"
 )
 
-// tautulliEnricher implements MovieEnricher for Tautulli integration
-type tautulliEnricher struct {
+// watchEnricher implements MovieEnricher for the configured watch history
+// providers, such as Tautulli or Jellyfin
+type watchEnricher struct {
 	operations *Operations
 }
 
-// EnrichMovies adds watch status information from Tautulli
-func (e *tautulliEnricher) EnrichMovies(ctx context.Context, movies []MovieInfo) error {
-	if e.operations.tautulliClient == nil {
+// EnrichMovies adds watch status information from every watch history provider
+func (e *watchEnricher) EnrichMovies(ctx context.Context, movies []MovieInfo) error {
+	if len(e.operations.watchProviders) == 0 {
 		return nil
 	}
+	provider := watch.Combine(e.operations.watchProviders...)
 
 	// Create identifiers for batch lookup
-	var identifiers []tautulli.MovieIdentifier
+	var identifiers []watch.MovieIdentifier
 	for _, movie := range movies {
-		identifiers = append(identifiers, tautulli.MovieIdentifier{
+		identifiers = append(identifiers, watch.MovieIdentifier{
 			IMDbID: movie.IMDBID,
 			TMDbID: movie.TMDBID,
 			Title:  movie.Title,
 		})
 	}
 
-	// Get watch status with per-user data for all movies at once
-	watchStatuses, err := e.operations.tautulliClient.BatchGetMovieWatchStatusWithUsers(
-		ctx, identifiers, e.operations.minWatchPercent)
+	// Get watch status with per-user data for all movies at once. When only
+	// some providers fail, the others' data is still used.
+	watchStatuses, err := provider.MovieWatchStatus(ctx, identifiers, e.operations.watchThreshold)
 	if err != nil {
-		return fmt.Errorf("failed to fetch watch status: %w", err)
+		if watchStatuses == nil {
+			return fmt.Errorf("failed to fetch watch status: %w", err)
+		}
+		e.operations.logger.Warn().Err(err).Msg("Failed to fetch watch status from some providers")
 	}
 
 	// Update movie info with watch status
 	for i := range movies {
-		if status, ok := watchStatuses[movies[i].IMDBID]; ok {
+		if status, ok := watchStatuses[movies[i].TMDBID]; ok {
 			movies[i].Watched = status.Watched
 			movies[i].WatchCount = status.WatchCount
 			movies[i].LastWatched = status.LastWatched
@@ -16,188 +16,157 @@ func NewConsoleFormatter() *ConsoleFormatter {
 	return &ConsoleFormatter{}
 }
 
-// FormatMovieList formats a list of movies for console display
-func (f *ConsoleFormatter) FormatMovieList(movies []MovieInfo, options FormatOptions) string {
-	if len(movies) == 0 {
-		return "No movies found"
+// FormatMovieList formats the movies matched by each filter for console display
+func (f *ConsoleFormatter) FormatMovieList(groups []MovieGroup, options FormatOptions) (string, error) {
+	matched := make(map[MovieKey]bool) // Movies can match several filters
+	for _, group := range groups {
+		for _, movie := range group.Movies {
+			matched[movie.Key()] = true
+		}
+	}
+	if len(matched) == 0 {
+		return "No movies found matching any filter criteria.\n", nil
 	}
 
 	var sb strings.Builder
 
-	// Header
-	sb.WriteString("\nMovie")
-	if len(movies) != 1 {
+	fmt.Fprintf(&sb, "\nFound %d movie", len(matched))
+	if len(matched) != 1 {
 		sb.WriteString("s")
 	}
-	fmt.Fprintf(&sb, " (%d):\n\n", len(movies))
+	sb.WriteString("\n\n")
 
-	// Format each movie
-	for i, movie := range movies {
-		isLast := i == len(movies)-1
-		f.formatMovie(&sb, movie, isLast, options)
+	for _, group := range groups {
+		if len(group.Movies) == 0 {
+			continue
+		}
+		f.formatGroupHeader(&sb, group)
 
-		if !isLast {
-			sb.WriteString("\u2502\n")
+		for i, movie := range group.Movies {
+			isLast := i == len(group.Movies)-1
+			f.formatMovie(&sb, movie, isLast, options)
+
+			if !isLast && options.ShowDetails {
+				sb.WriteString("\u2502\n")
+			}
 		}
+		sb.WriteString("\n")
 	}
 
-	sb.WriteString("\n")
-	return sb.String()
+	return sb.String(), nil
 }
 
-// FormatMoviesToDelete formats movies for deletion confirmation
-func (f *ConsoleFormatter) FormatMoviesToDelete(movies []MovieInfo) string {
-	if len(movies) == 0 {
-		return ""
-	}
-
+// FormatMoviesToDelete formats what delete is about to do for confirmation
+func (f *ConsoleFormatter) FormatMoviesToDelete(plan DeletePlan, options FormatOptions) (string, error) {
 	var sb strings.Builder
-	var watchedCount int
 
-	// Header
-	sb.WriteString("\nMovie")
-	if len(movies) != 1 {
-		sb.WriteString("s")
-	}
-	fmt.Fprintf(&sb, " to be deleted (%d):\n\n", len(movies))
-
-	// Format each movie
-	for i, movie := range movies {
-		isLast := i == len(movies)-1
-		prefix := "\u251c"
-		if isLast {
-			prefix = "\u2570"
+	if len(plan.Protected) > 0 {
+		fmt.Fprintf(&sb, "\u256d\u2500 Protected (%d movie", len(plan.Protected))
+		if len(plan.Protected) != 1 {
+			sb.WriteString("s")
 		}
-
-		fmt.Fprintf(&sb, "%s\u2500\u2500 %s (%d)\n", prefix, movie.Title, movie.Year)
-
-		// Track watch status for warning
-		if movie.Watched {
-			watchedCount++
-		}
-
-		indent := "\u2502   "
-		if isLast {
-			indent = "    "
+		sb.WriteString(")\n")
+		for i, p := range plan.Protected {
+			fmt.Fprintf(&sb, "%s\u2500\u2500 %s - matched %s, protected by rule %s\n",
+				treePrefix(i, len(plan.Protected)), movieTitle(p.Movie, options), p.Filter, p.Rule)
 		}
+		sb.WriteString("\n")
+	}
 
-		// Tags
-		if len(movie.TagNames) > 0 {
-			fmt.Fprintf(&sb, "%sTags: %s\n", indent, strings.Join(movie.TagNames, ", "))
+	if len(plan.Held) > 0 {
+		fmt.Fprintf(&sb, "\u256d\u2500 Held back (%d movie", len(plan.Held))
+		if len(plan.Held) != 1 {
+			sb.WriteString("s")
 		}
-
-		// File path
-		if movie.HasFile {
-			fmt.Fprintf(&sb, "%sFile: %s\n", indent, movie.Path)
+		sb.WriteString(")\n")
+		for i, h := range plan.Held {
+			fmt.Fprintf(&sb, "%s\u2500\u2500 %s - matched %s, but not all of %s did\n",
+				treePrefix(i, len(plan.Held)), movieTitle(h.Movie, options), h.Filter, h.Movie.CollectionName)
 		}
+		sb.WriteString("\n")
+	}
 
-		// Dates
-		var dateParts []string
-		if !movie.Added.IsZero() {
-			dateParts = append(dateParts, fmt.Sprintf("Available: %s", movie.Added.Format("2006-01-02")))
-		}
-		if !movie.FileImported.IsZero() && !movie.FileImported.Equal(movie.Added) {
-			dateParts = append(dateParts, fmt.Sprintf("Imported: %s", movie.FileImported.Format("2006-01-02")))
-		}
-		if !movie.MonitoredSince.IsZero() && !movie.MonitoredSince.Equal(movie.Added) {
-			dateParts = append(dateParts, fmt.Sprintf("Monitored: %s", movie.MonitoredSince.Format("2006-01-02")))
-		}
-		if len(dateParts) > 0 {
-			fmt.Fprintf(&sb, "%s%s\n", indent, strings.Join(dateParts, " | "))
+	var total int
+	for _, group := range plan.Groups {
+		if len(group.Movies) == 0 {
+			continue
 		}
+		total += len(group.Movies)
+		f.formatGroupHeader(&sb, group)
 
-		// Watch info
-		if movie.WatchCount > 0 {
-			watchInfo := fmt.Sprintf("Watched %dx", movie.WatchCount)
-			if !movie.LastWatched.IsZero() {
-				watchInfo += fmt.Sprintf(" (last: %s)", movie.LastWatched.Format("2006-01-02"))
-			}
-			fmt.Fprintf(&sb, "%s%s\n", indent, watchInfo)
-		}
+		for i, movie := range group.Movies {
+			isLast := i == len(group.Movies)-1
+			f.formatMovie(&sb, movie, isLast, options)
 
-		// Request info
-		if movie.IsRequested {
-			requestInfo := fmt.Sprintf("Requested by: %s", movie.RequestedBy)
-			if !movie.RequestDate.IsZero() {
-				requestInfo += fmt.Sprintf(" on %s", movie.RequestDate.Format("2006-01-02"))
-			}
-			if movie.RequestStatus != "" {
-				requestInfo += fmt.Sprintf(" (Status: %s)", movie.RequestStatus)
+			if !isLast && options.ShowDetails {
+				sb.WriteString("\u2502\n")
 			}
-			fmt.Fprintf(&sb, "%s%s\n", indent, requestInfo)
 		}
+		sb.WriteString("\n")
+	}
 
-		if !isLast {
-			sb.WriteString("\u2502\n")
-		}
+	if total == 0 {
+		sb.WriteString("No movies found matching any filter criteria.\n")
 	}
 
-	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// formatGroupHeader writes the box heading a filter's movies
+func (f *ConsoleFormatter) formatGroupHeader(sb *strings.Builder, group MovieGroup) {
+	switch {
+	case group.Filter == "":
+		fmt.Fprintf(sb, "\u256d\u2500 Movies to %s (%d", group.Action, len(group.Movies))
+	case group.Action != "":
+		fmt.Fprintf(sb, "\u256d\u2500 Filter: %s \u2192 %s (%d match", group.Filter, group.Action, len(group.Movies))
+	default:
+		fmt.Fprintf(sb, "\u256d\u2500 Filter: %s (%d match", group.Filter, len(group.Movies))
+	}
+	if group.Filter != "" && len(group.Movies) != 1 {
+		sb.WriteString("es")
+	}
+	sb.WriteString(")\n")
 
-	return sb.String()
+	if group.Description != "" {
+		fmt.Fprintf(sb, "\u2502  %s\n", group.Description)
+	}
 }
 
-// FormatUpgradeCandidates formats upgrade candidates for display
-func (f *ConsoleFormatter) FormatUpgradeCandidates(candidates []UpgradeResult) string {
+// FormatUpgradeCandidates formats upgrade candidates as a numbered table
+func (f *ConsoleFormatter) FormatUpgradeCandidates(candidates []UpgradeResult) (string, error) {
 	if len(candidates) == 0 {
-		return "No movies need upgrading"
+		return "\u2713 All movies have the required custom formats!\n", nil
 	}
 
 	var sb strings.Builder
-	fmt.Fprintf(&sb, "\nMovies that can be upgraded (%d):\n\n", len(candidates))
-
-	for i, candidate := range candidates {
-		isLast := i == len(candidates)-1
-		prefix := "\u251c"
-		if isLast {
-			prefix = "\u2570"
-		}
 
-		fmt.Fprintf(&sb, "%s\u2500\u2500 %s (%d)\n", prefix, candidate.Movie.Title, candidate.Movie.Year)
+	movieText := "movie"
+	if len(candidates) != 1 {
+		movieText = "movies"
+	}
+	fmt.Fprintf(&sb, "Found %d %s missing custom formats:\n\n", len(candidates), movieText)
 
-		indent := "\u2502   "
-		if isLast {
-			indent = "    "
-		}
+	sb.WriteString(strings.Repeat("\u2501", 85) + "\n")
+	fmt.Fprintf(&sb, "%-4s %-50s %-15s %s\n", "#", "MOVIE", "YEAR", "CURRENT FORMATS")
+	sb.WriteString(strings.Repeat("\u2501", 85) + "\n")
 
-		// Current formats and score
+	for i, candidate := range candidates {
+		currentFormats := "None"
 		if len(candidate.CurrentFormats) > 0 {
-			fmt.Fprintf(&sb, "%sCurrent Formats: %v (Score: %d)\n",
-				indent, candidate.CurrentFormats, candidate.CurrentFormatScore)
-		} else {
-			fmt.Fprintf(&sb, "%sCurrent Formats: None (Score: %d)\n",
-				indent, candidate.CurrentFormatScore)
-		}
-
-		// Missing formats
-		if len(candidate.MissingFormats) > 0 {
-			fmt.Fprintf(&sb, "%sMissing Formats: %v\n", indent, candidate.MissingFormats)
+			currentFormats = strings.Join(candidate.CurrentFormats, ", ")
 		}
 
-		// Status info
-		var statusParts []string
-		if !candidate.IsAvailable {
-			statusParts = append(statusParts, "Not Released")
-		}
-		if candidate.NeedsMonitoring {
-			statusParts = append(statusParts, "Not Monitored")
-		}
-		if len(statusParts) > 0 {
-			fmt.Fprintf(&sb, "%sStatus: %v\n", indent, statusParts)
+		// Truncate title if too long
+		title := candidate.Movie.Title
+		if len(title) > 48 {
+			title = title[:45] + "..."
 		}
 
-		// File info
-		if candidate.Movie.MovieFile != nil && candidate.Movie.MovieFile.Path != "" {
-			fmt.Fprintf(&sb, "%sFile: %s\n", indent, candidate.Movie.MovieFile.Path)
-		}
-
-		if !isLast {
-			sb.WriteString("\u2502\n")
-		}
+		fmt.Fprintf(&sb, "%-4d %-50s %-15d %s\n", i+1, title, candidate.Movie.Year, currentFormats)
 	}
+	sb.WriteString(strings.Repeat("\u2501", 85) + "\n")
 
-	sb.WriteString("\n")
-	return sb.String()
+	return sb.String(), nil
 }
 
 // PrintImportableItems prints importable items in a formatted way
@@ -253,22 +222,30 @@ func (f *ConsoleFormatter) formatMovie(sb *strings.Builder, movie MovieInfo, isL
 		prefix = "\u2570"
 	}
 
-	fmt.Fprintf(sb, "%s\u2500\u2500 %s (%d)\n", prefix, movie.Title, movie.Year)
+	fmt.Fprintf(sb, "%s\u2500\u2500 %s\n", prefix, movieTitle(movie, options))
 
 	indent := "\u2502   "
 	if isLast {
 		indent = "    "
 	}
 
-	// Basic info
-	if len(movie.TagNames) > 0 && options.ShowDetails {
-		fmt.Fprintf(sb, "%sTags: %s\n", indent, strings.Join(movie.TagNames, ", "))
-	}
+	if options.ShowDetails {
+		if len(movie.TagNames) > 0 {
+			fmt.Fprintf(sb, "%sTags: %s\n", indent, strings.Join(movie.TagNames, ", "))
+		}
 
-	if movie.HasFile && options.ShowDetails {
-		fmt.Fprintf(sb, "%sFile: %s\n", indent, movie.Path)
-		if movie.IsHardlinked {
-			fmt.Fprintf(sb, "%sHardlinks: %d\n", indent, movie.HardlinkCount)
+		var dateParts []string
+		if !movie.Added.IsZero() {
+			dateParts = append(dateParts, fmt.Sprintf("Available: %s", movie.Added.Format("2006-01-02")))
+		}
+		if !movie.FileImported.IsZero() && !movie.FileImported.Equal(movie.Added) {
+			dateParts = append(dateParts, fmt.Sprintf("Imported: %s", movie.FileImported.Format("2006-01-02")))
+		}
+		if !movie.MonitoredSince.IsZero() && !movie.MonitoredSince.Equal(movie.Added) {
+			dateParts = append(dateParts, fmt.Sprintf("Monitored: %s", movie.MonitoredSince.Format("2006-01-02")))
+		}
+		if len(dateParts) > 0 {
+			fmt.Fprintf(sb, "%s%s\n", indent, strings.Join(dateParts, " | "))
 		}
 	}
 
@@ -279,44 +256,39 @@ func (f *ConsoleFormatter) formatMovie(sb *strings.Builder, movie MovieInfo, isL
 			watchInfo += fmt.Sprintf(" (last: %s)", movie.LastWatched.Format("2006-01-02"))
 		}
 		fmt.Fprintf(sb, "%s%s\n", indent, watchInfo)
-
-		// Per-user watch data
-		if len(movie.UserWatchData) > 0 {
-			for username, userData := range movie.UserWatchData {
-				if userData.Watched {
-					userInfo := fmt.Sprintf("  - %s: %dx", username, userData.WatchCount)
-					if userData.MaxProgress > 0 {
-						userInfo += fmt.Sprintf(" (%.0f%%)", userData.MaxProgress)
-					}
-					fmt.Fprintf(sb, "%s%s\n", indent, userInfo)
-				}
-			}
-		}
 	}
 
 	// Request info
-	if options.ShowRequests && movie.IsRequested {
+	if options.ShowRequests && movie.IsRequested && movie.RequestedBy != "" {
 		requestInfo := fmt.Sprintf("Requested by: %s", movie.RequestedBy)
 		if !movie.RequestDate.IsZero() {
 			requestInfo += fmt.Sprintf(" on %s", movie.RequestDate.Format("2006-01-02"))
 		}
 		fmt.Fprintf(sb, "%s%s\n", indent, requestInfo)
 	}
+}
 
-	// Ratings
-	if options.ShowDetails && len(movie.Ratings) > 0 {
-		var ratings []string
-		for source, rating := range movie.Ratings {
-			ratings = append(ratings, fmt.Sprintf("%s: %.1f", source, rating))
-		}
-		fmt.Fprintf(sb, "%sRatings: %s\n", indent, strings.Join(ratings, ", "))
+// movieTitle returns a movie's title and year, with its instance when asked to
+func movieTitle(movie MovieInfo, options FormatOptions) string {
+	title := fmt.Sprintf("%s (%d)", movie.Title, movie.Year)
+	if options.ShowInstance {
+		title += fmt.Sprintf(" [%s]", movie.Instance)
 	}
+	return title
+}
+
+// treePrefix returns the branch drawn before the i-th of n entries
+func treePrefix(i, n int) string {
+	if i == n-1 {
+		return "\u2570"
+	}
+	return "\u251c"
 }
 
 // FormatHardlinkResults formats hardlink scan results
-func (f *ConsoleFormatter) FormatHardlinkResults(movies []MovieInfo) string {
+func (f *ConsoleFormatter) FormatHardlinkResults(movies []MovieInfo) (string, error) {
 	if len(movies) == 0 {
-		return "All movies are properly hardlinked"
+		return "\u2713 All movies are properly hardlinked!\n", nil
 	}
 
 	var sb strings.Builder
@@ -371,7 +343,8 @@ func (f *ConsoleFormatter) FormatHardlinkResults(movies []MovieInfo) string {
 				sb.WriteString("\u2502\n")
 			}
 		}
+		sb.WriteString("\n")
 	}
 
-	return sb.String()
+	return sb.String(), nil
 }
@@ -9,6 +9,7 @@ import (
 	"golift.io/starr/radarr"
 
 	"github.com/s0up4200/arrbiter/hardlink"
+	"github.com/s0up4200/arrbiter/journal"
 	"github.com/s0up4200/arrbiter/qbittorrent"
 )
 
@@ -130,6 +131,45 @@ func (o *Operations) ScanNonHardlinkedMovies(ctx context.Context) ([]MovieInfo,
 	return nonHardlinkedMovies, nil
 }
 
+// AddHardlinkInfo fills in the hardlink count of every movie file and, for
+// files that are not hardlinked, the qBittorrent torrent they came from. Files
+// arrbiter cannot reach on disk are left without hardlink data.
+func (o *Operations) AddHardlinkInfo(ctx context.Context, movies []MovieInfo) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(DefaultBatchSize)
+
+	for i := range movies {
+		movie := &movies[i]
+		if movie.MovieFile == nil || movie.MovieFile.Path == "" {
+			continue
+		}
+
+		g.Go(func() error {
+			count, err := hardlink.GetHardlinkCount(movie.MovieFile.Path)
+			if err != nil {
+				o.logger.Debug().Err(err).Str("movie", movie.Title).Msg("Failed to check hardlink status")
+				return nil
+			}
+			movie.HardlinkCount = count
+			movie.IsHardlinked = count > 1
+
+			if movie.IsHardlinked || o.qbittorrentClient == nil {
+				return nil
+			}
+			torrent, err := o.qbittorrentClient.GetTorrentByPath(ctx, movie.MovieFile.Path)
+			if err != nil {
+				o.logger.Warn().Err(err).Str("movie", movie.Title).Msg("Failed to check qBittorrent status")
+			} else if torrent != nil {
+				movie.QBittorrentHash = torrent.Hash
+				movie.IsSeeding = torrent.IsSeeding
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
 // ReimportMovieFromQBittorrent re-imports a movie from qBittorrent to create hardlinks
 func (o *Operations) ReimportMovieFromQBittorrent(ctx context.Context, movie MovieInfo) error {
 	if o.qbittorrentClient == nil {
@@ -171,7 +211,18 @@ func (o *Operations) ReimportMovieFromTorrentMatch(ctx context.Context, movie Mo
 	return o.reimportMovieFromTorrent(ctx, movie, torrent)
 }
 
+// reimportMovieFromTorrent re-imports a movie from a torrent and records the outcome in the journal
 func (o *Operations) reimportMovieFromTorrent(ctx context.Context, movie MovieInfo, torrent *qbittorrent.TorrentInfo) error {
+	err := o.importFromTorrent(ctx, movie, torrent)
+
+	record := newJournalRecord(movie, journal.ActionReimport, err)
+	record.Details = fmt.Sprintf("torrent %s (%s)", torrent.Name, torrent.Hash)
+	o.recordActions(record)
+
+	return err
+}
+
+func (o *Operations) importFromTorrent(ctx context.Context, movie MovieInfo, torrent *qbittorrent.TorrentInfo) error {
 	// Use manual import to re-import the file
 	// This will create a hardlink between qBittorrent and Radarr
 	importPath := torrent.GetFullPath()
@@ -231,6 +282,12 @@ func (o *Operations) reimportMovieFromTorrent(ctx context.Context, movie MovieIn
 
 // DeleteAndResearchMovie deletes a movie file and triggers a new search
 func (o *Operations) DeleteAndResearchMovie(ctx context.Context, movie MovieInfo) error {
+	err := o.deleteAndResearchMovie(ctx, movie)
+	o.recordActions(newJournalRecord(movie, journal.ActionDeleteAndResearch, err))
+	return err
+}
+
+func (o *Operations) deleteAndResearchMovie(ctx context.Context, movie MovieInfo) error {
 	o.logger.Info().
 		Str("movie", movie.Title).
 		Msg("Deleting movie file and triggering new search")
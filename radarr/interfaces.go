@@ -14,6 +14,11 @@ type RadarrAPI interface {
 	GetMovieByIDContext(ctx context.Context, movieID int64) (*radarr.Movie, error)
 	UpdateMovieContext(ctx context.Context, movieID int64, movie *radarr.Movie, moveFiles bool) (*radarr.Movie, error)
 	DeleteMovieContext(ctx context.Context, movieID int64, deleteFiles, addImportExclusion bool) error
+	AddMovieContext(ctx context.Context, movie *radarr.AddMovieInput) (*radarr.Movie, error)
+
+	// Import exclusion operations
+	GetExclusionsContext(ctx context.Context) ([]*radarr.Exclusion, error)
+	DeleteExclusionsContext(ctx context.Context, ids []int64) error
 	
 	// File operations
 	GetMovieFileByIDContext(ctx context.Context, fileID int64) (*radarr.MovieFile, error)
@@ -21,7 +26,16 @@ type RadarrAPI interface {
 	
 	// Tag operations
 	GetTagsContext(ctx context.Context) ([]*starr.Tag, error)
+	AddTagContext(ctx context.Context, tag *starr.Tag) (*starr.Tag, error)
+
+	// Quality profile operations
+	GetQualityProfilesContext(ctx context.Context) ([]*radarr.QualityProfile, error)
 	
+	// Disk operations
+	GetRootFoldersContext(ctx context.Context) ([]*radarr.RootFolder, error)
+	// GetInto reaches endpoints starr has no method for, such as diskspace
+	GetInto(ctx context.Context, req starr.Request, output any) error
+
 	// Custom format operations
 	GetCustomFormatsContext(ctx context.Context) ([]*radarr.CustomFormatOutput, error)
 	
@@ -43,10 +57,10 @@ type MovieEnricher interface {
 
 // MovieFormatter defines the interface for formatting movie output
 type MovieFormatter interface {
-	FormatMovieList(movies []MovieInfo, options FormatOptions) string
-	FormatMoviesToDelete(movies []MovieInfo) string
-	FormatUpgradeCandidates(candidates []UpgradeResult) string
-	FormatHardlinkResults(movies []MovieInfo) string
+	FormatMovieList(groups []MovieGroup, options FormatOptions) (string, error)
+	FormatMoviesToDelete(plan DeletePlan, options FormatOptions) (string, error)
+	FormatUpgradeCandidates(candidates []UpgradeResult) (string, error)
+	FormatHardlinkResults(movies []MovieInfo) (string, error)
 }
 
 // FormatOptions contains options for formatting output
@@ -54,4 +68,27 @@ type FormatOptions struct {
 	ShowDetails   bool
 	ShowWatchInfo bool
 	ShowRequests  bool
-}
\ No newline at end of file
+	ShowInstance  bool // Label each movie with its Radarr instance
+}
+
+// MovieGroup is the movies matched by one filter
+type MovieGroup struct {
+	Filter      string
+	Description string
+	Action      string // What will be done to the movies, empty when only listing
+	Movies      []MovieInfo
+}
+
+// DeletePlan is what delete is about to do, grouped by filter
+type DeletePlan struct {
+	Groups    []MovieGroup
+	Protected []SkippedMovie // Matched a filter but protected by a rule
+	Held      []SkippedMovie // Matched a filter but the rest of their collection did not
+}
+
+// SkippedMovie is a movie a filter matched that is left alone
+type SkippedMovie struct {
+	Movie  MovieInfo
+	Filter string
+	Rule   string // Protection rule, empty for held back movies
+}
@@ -0,0 +1,80 @@
+package radarr
+
+import (
+	"slices"
+
+	"github.com/s0up4200/arrbiter/journal"
+)
+
+// SetJournal sets the journal that actions on movies are recorded in
+func (o *Operations) SetJournal(j *journal.Journal) {
+	o.journal = j
+}
+
+// recordActions appends records to the journal if one is configured.
+// Failing to write the journal never fails the action itself.
+func (o *Operations) recordActions(records ...journal.Record) {
+	if o.journal == nil {
+		return
+	}
+	if err := o.journal.Append(records...); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to write journal")
+	}
+}
+
+// newJournalRecord captures a movie and its enrichment data for the journal
+func newJournalRecord(movie MovieInfo, action string, err error) journal.Record {
+	record := journal.Record{
+		Action:   action,
+		Outcome:  journal.OutcomeSuccess,
+		MovieID:  movie.ID,
+		Instance: movie.Instance,
+		TMDBID:   movie.TMDBID,
+		IMDBID:   movie.IMDBID,
+		Title:    movie.Title,
+		Year:     movie.Year,
+		Path:     movie.Path,
+		Radarr: &journal.RadarrSnapshot{
+			QualityProfileID: movie.QualityProfileID,
+			RootFolder:       movie.RootFolder,
+			Availability:     movie.Availability,
+			Tags:             movie.TagNames,
+			Monitored:        movie.Monitored,
+		},
+	}
+	if err != nil {
+		record.Outcome = journal.OutcomeFailed
+		record.Error = err.Error()
+	}
+
+	if movie.MovieFile != nil {
+		record.SizeBytes = movie.MovieFile.Size
+	}
+
+	if movie.WatchCount > 0 || movie.Watched || len(movie.UserWatchData) > 0 {
+		watch := &journal.WatchSnapshot{
+			Watched:     movie.Watched,
+			WatchCount:  movie.WatchCount,
+			LastWatched: movie.LastWatched,
+			Progress:    movie.WatchProgress,
+		}
+		for user, data := range movie.UserWatchData {
+			if data.Watched {
+				watch.WatchedBy = append(watch.WatchedBy, user)
+			}
+		}
+		slices.Sort(watch.WatchedBy)
+		record.Watch = watch
+	}
+
+	if movie.IsRequested {
+		record.Request = &journal.RequestSnapshot{
+			RequestedBy: movie.RequestedBy,
+			RequestDate: movie.RequestDate,
+			Status:      movie.RequestStatus,
+			ApprovedBy:  movie.ApprovedBy,
+		}
+	}
+
+	return record
+}
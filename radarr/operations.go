@@ -9,9 +9,11 @@ import (
 	"github.com/rs/zerolog"
 	"golift.io/starr/radarr"
 
+	"github.com/s0up4200/arrbiter/journal"
 	"github.com/s0up4200/arrbiter/overseerr"
 	"github.com/s0up4200/arrbiter/qbittorrent"
 	"github.com/s0up4200/arrbiter/tautulli"
+	"github.com/s0up4200/arrbiter/watch"
 )
 
 // SearchOptions contains options for searching movies
@@ -22,43 +24,85 @@ type SearchOptions struct {
 
 // DeleteOptions contains options for deleting movies
 type DeleteOptions struct {
-	DryRun        bool
-	ConfirmDelete bool
+	DryRun             bool
+	ConfirmDelete      bool
+	KeepFiles          bool // Leave media files on disk and only remove the Radarr entry
+	AddImportExclusion bool // Prevent lists from re-adding the movie
+
+	// Filter that selected the movies, recorded in the journal
+	FilterName       string
+	FilterExpression string
 }
 
 // Operations handles movie search and delete operations
 type Operations struct {
 	client            *Client
-	tautulliClient    *tautulli.Client
+	watchProviders    []watch.Provider
 	overseerrClient   *overseerr.Client
 	qbittorrentClient *qbittorrent.Client
 	logger            zerolog.Logger
-	minWatchPercent   float64
+	watchThreshold    watch.Threshold
 	formatter         MovieFormatter
 	enrichers         []MovieEnricher
+	journal           *journal.Journal
+	fetchFileDetails  bool
+	instance          string
 }
 
 // NewOperations creates a new Operations instance
 func NewOperations(client *Client, logger zerolog.Logger) *Operations {
 	return &Operations{
-		client:          client,
-		logger:          logger,
-		minWatchPercent: 85.0, // Default value
-		formatter:       NewConsoleFormatter(),
-		enrichers:       make([]MovieEnricher, 0),
+		client:         client,
+		logger:         logger,
+		watchThreshold: watch.Threshold{MinPercent: watch.DefaultMinPercent},
+		formatter:      NewConsoleFormatter(),
+		enrichers:      make([]MovieEnricher, 0),
 	}
 }
 
 // SetTautulliClient sets the Tautulli client for watch status lookups
 func (o *Operations) SetTautulliClient(client *tautulli.Client) {
-	o.tautulliClient = client
+	o.AddWatchProvider(client)
+}
+
+// AddWatchProvider adds a source of watch history. With several providers,
+// their watch history is merged so users of every media server count.
+func (o *Operations) AddWatchProvider(provider watch.Provider) {
+	o.watchProviders = append(o.watchProviders, provider)
 	// Add to enrichers if not already present
-	o.addEnricher(&tautulliEnricher{operations: o})
+	o.addEnricher(&watchEnricher{operations: o})
 }
 
 // SetMinWatchPercent sets the minimum watch percentage for considering a movie watched
 func (o *Operations) SetMinWatchPercent(percent float64) {
-	o.minWatchPercent = percent
+	o.watchThreshold.MinPercent = percent
+}
+
+// SetWatchThreshold sets the watch threshold, including per-user overrides
+func (o *Operations) SetWatchThreshold(threshold watch.Threshold) {
+	o.watchThreshold = threshold
+}
+
+// SetFetchFileDetails makes GetAllMovies fetch each movie file individually so
+// custom formats and their score are available. This costs one request per movie.
+func (o *Operations) SetFetchFileDetails(enabled bool) {
+	o.fetchFileDetails = enabled
+}
+
+// SetInstance names the Radarr instance these operations work on. Movies
+// returned by GetAllMovies and journal records carry the name.
+func (o *Operations) SetInstance(name string) {
+	o.instance = name
+}
+
+// Instance returns the name of the Radarr instance these operations work on
+func (o *Operations) Instance() string {
+	return o.instance
+}
+
+// SetFormatter sets how movies are printed
+func (o *Operations) SetFormatter(formatter MovieFormatter) {
+	o.formatter = formatter
 }
 
 // SetOverseerrClient sets the Overseerr client for request data lookups
@@ -82,16 +126,37 @@ func (o *Operations) GetAllMovies(ctx context.Context) ([]MovieInfo, error) {
 		return nil, err
 	}
 
+	// The movie list doesn't carry custom formats, only the movie file endpoint does
+	if o.fetchFileDetails {
+		if err := o.client.ProcessMovieFiles(ctx, movies); err != nil {
+			o.logger.Warn().Err(err).Msg("Failed to fetch some movie file details")
+		}
+	}
+
 	// Convert to MovieInfo with tags
 	var results []MovieInfo
 	for _, movie := range movies {
 		info := o.client.GetMovieInfo(movie, tags)
+		info.Instance = o.instance
 		// Only include movies with imported files
 		if !info.FileImported.IsZero() {
 			results = append(results, info)
 		}
 	}
 
+	// Resolve quality profile names, which the movie list only has IDs for
+	if profiles, err := o.client.GetQualityProfiles(ctx); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to get quality profiles, profile names will be empty")
+	} else {
+		names := make(map[int64]string, len(profiles))
+		for _, profile := range profiles {
+			names[profile.ID] = profile.Name
+		}
+		for i := range results {
+			results[i].QualityProfile = names[results[i].QualityProfileID]
+		}
+	}
+
 	// Enrich movies from all configured sources concurrently
 	if len(o.enrichers) > 0 {
 		if err := o.client.EnrichMoviesFromMultipleSources(ctx, results, o.enrichers...); err != nil {
@@ -160,12 +225,13 @@ func (o *Operations) DeleteMovies(ctx context.Context, movies []MovieInfo, opts
 
 	if opts.DryRun {
 		o.logger.Info().Msg("DRY RUN MODE - No movies will be deleted")
-		fmt.Print(o.formatter.FormatMoviesToDelete(movies))
-		return nil
+		return o.printMoviesToDelete(movies)
 	}
 
 	if opts.ConfirmDelete {
-		fmt.Print(o.formatter.FormatMoviesToDelete(movies))
+		if err := o.printMoviesToDelete(movies); err != nil {
+			return err
+		}
 		if !o.confirmDeletion(len(movies)) {
 			o.logger.Info().Msg("Deletion cancelled by user")
 			return nil
@@ -173,7 +239,7 @@ func (o *Operations) DeleteMovies(ctx context.Context, movies []MovieInfo, opts
 	}
 
 	// Use concurrent batch deletion
-	result := o.client.BatchDeleteMovies(ctx, movies)
+	result := o.client.BatchDeleteMovies(ctx, movies, opts)
 
 	o.logger.Info().
 		Int("deleted", len(result.Successful)).
@@ -181,7 +247,9 @@ func (o *Operations) DeleteMovies(ctx context.Context, movies []MovieInfo, opts
 		Msg("Deletion complete")
 
 	// Log individual failures
+	failures := make(map[int64]error, len(result.Failed))
 	for _, failure := range result.Failed {
+		failures[failure.MovieID] = failure.Err
 		o.logger.Error().
 			Err(failure.Err).
 			Int64("id", failure.MovieID).
@@ -189,6 +257,28 @@ func (o *Operations) DeleteMovies(ctx context.Context, movies []MovieInfo, opts
 			Msg("Failed to delete movie")
 	}
 
+	// Undo adds movies back to the Radarr root folder they were in
+	roots, err := o.client.GetRootFolders(ctx)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to get root folders, journaling each movie's parent folder instead")
+	}
+
+	records := make([]journal.Record, 0, len(movies))
+	for _, movie := range movies {
+		record := newJournalRecord(movie, journal.ActionDelete, failures[movie.ID])
+		record.Filter = opts.FilterName
+		record.Expression = opts.FilterExpression
+		record.Radarr.AddedExclusion = opts.AddImportExclusion
+		if root := movie.radarrRootFolder(roots); root != "" {
+			record.Radarr.RootFolder = root
+		}
+		if opts.KeepFiles {
+			record.Details = "files kept"
+		}
+		records = append(records, record)
+	}
+	o.recordActions(records...)
+
 	if len(result.Failed) > 0 {
 		return fmt.Errorf("failed to delete %d movies", len(result.Failed))
 	}
@@ -264,6 +354,18 @@ func (o *Operations) ImportMovies(ctx context.Context, items []*radarr.ManualImp
 	return nil
 }
 
+// printMoviesToDelete prints the movies DeleteMovies is about to delete
+func (o *Operations) printMoviesToDelete(movies []MovieInfo) error {
+	output, err := o.formatter.FormatMoviesToDelete(DeletePlan{
+		Groups: []MovieGroup{{Action: "delete", Movies: movies}},
+	}, FormatOptions{ShowDetails: true, ShowWatchInfo: true, ShowRequests: true})
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
 // PrintImportableItems prints importable items in a formatted way
 func (o *Operations) PrintImportableItems(items []*radarr.ManualImportOutput) {
 	formatter := &ConsoleFormatter{}
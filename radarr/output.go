@@ -0,0 +1,330 @@
+package radarr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by NewFormatter
+const (
+	OutputText     = "text"
+	OutputJSON     = "json"
+	OutputNDJSON   = "ndjson"
+	OutputCSV      = "csv"
+	OutputYAML     = "yaml"
+	OutputTemplate = "template" // Written as template=<Go template>
+)
+
+// NewFormatter returns the formatter for an output format: text for the
+// console, json, ndjson, csv, yaml, or template=<text> to execute a Go
+// text/template once per record
+func NewFormatter(output string) (MovieFormatter, error) {
+	name, text, hasTemplate := strings.Cut(output, "=")
+
+	switch {
+	case output == "" || output == OutputText:
+		return NewConsoleFormatter(), nil
+	case hasTemplate && name == OutputTemplate:
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output template: %w", err)
+		}
+		return &RecordFormatter{format: OutputTemplate, template: tmpl}, nil
+	case output == OutputJSON, output == OutputNDJSON, output == OutputCSV, output == OutputYAML:
+		return &RecordFormatter{format: output}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (must be text, json, ndjson, csv, yaml or template=<text>)", output)
+}
+
+// RecordFormatter writes movies as machine-readable records, one per movie
+type RecordFormatter struct {
+	format   string
+	template *template.Template
+}
+
+// MovieRecord is a movie as written by RecordFormatter. Filter and Action are
+// set by list and delete, Skipped when delete leaves a matched movie alone.
+type MovieRecord struct {
+	Filter          string             `json:"filter,omitempty" yaml:"filter,omitempty"`
+	Action          string             `json:"action,omitempty" yaml:"action,omitempty"`
+	Skipped         string             `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	Instance        string             `json:"instance,omitempty" yaml:"instance,omitempty"`
+	ID              int64              `json:"id" yaml:"id"`
+	Title           string             `json:"title" yaml:"title"`
+	Year            int                `json:"year" yaml:"year"`
+	TMDBID          int64              `json:"tmdb_id" yaml:"tmdb_id"`
+	IMDBID          string             `json:"imdb_id,omitempty" yaml:"imdb_id,omitempty"`
+	Path            string             `json:"path,omitempty" yaml:"path,omitempty"`
+	File            string             `json:"file,omitempty" yaml:"file,omitempty"`
+	SizeBytes       int64              `json:"size_bytes" yaml:"size_bytes"`
+	Quality         string             `json:"quality,omitempty" yaml:"quality,omitempty"`
+	QualityProfile  string             `json:"quality_profile,omitempty" yaml:"quality_profile,omitempty"`
+	Tags            []string           `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Monitored       bool               `json:"monitored" yaml:"monitored"`
+	Added           time.Time          `json:"added,omitzero" yaml:"added,omitempty"`
+	FileImported    time.Time          `json:"file_imported,omitzero" yaml:"file_imported,omitempty"`
+	Watched         bool               `json:"watched" yaml:"watched"`
+	WatchCount      int                `json:"watch_count" yaml:"watch_count"`
+	LastWatched     time.Time          `json:"last_watched,omitzero" yaml:"last_watched,omitempty"`
+	WatchedBy       []string           `json:"watched_by,omitempty" yaml:"watched_by,omitempty"`
+	Requested       bool               `json:"requested" yaml:"requested"`
+	RequestedBy     string             `json:"requested_by,omitempty" yaml:"requested_by,omitempty"`
+	RequestDate     time.Time          `json:"request_date,omitzero" yaml:"request_date,omitempty"`
+	Ratings         map[string]float64 `json:"ratings,omitempty" yaml:"ratings,omitempty"`
+	HardlinkCount   uint32             `json:"hardlink_count" yaml:"hardlink_count"`
+	QBittorrentHash string             `json:"qbittorrent_hash,omitempty" yaml:"qbittorrent_hash,omitempty"`
+	Seeding         bool               `json:"seeding" yaml:"seeding"`
+}
+
+// UpgradeRecord is an upgrade candidate as written by RecordFormatter
+type UpgradeRecord struct {
+	MovieRecord        `yaml:",inline"`
+	CurrentFormats     []string `json:"current_formats" yaml:"current_formats"`
+	CurrentFormatScore int      `json:"current_format_score" yaml:"current_format_score"`
+	MissingFormats     []string `json:"missing_formats" yaml:"missing_formats"`
+	Available          bool     `json:"available" yaml:"available"`
+	NeedsMonitoring    bool     `json:"needs_monitoring" yaml:"needs_monitoring"`
+}
+
+// NewMovieRecord flattens a movie into its record
+func NewMovieRecord(movie MovieInfo) MovieRecord {
+	record := MovieRecord{
+		Instance:        movie.Instance,
+		ID:              movie.ID,
+		Title:           movie.Title,
+		Year:            movie.Year,
+		TMDBID:          movie.TMDBID,
+		IMDBID:          movie.IMDBID,
+		Path:            movie.Path,
+		QualityProfile:  movie.QualityProfile,
+		Tags:            movie.TagNames,
+		Monitored:       movie.Monitored,
+		Added:           movie.Added,
+		FileImported:    movie.FileImported,
+		Watched:         movie.Watched,
+		WatchCount:      movie.WatchCount,
+		LastWatched:     movie.LastWatched,
+		Requested:       movie.IsRequested,
+		RequestedBy:     movie.RequestedBy,
+		RequestDate:     movie.RequestDate,
+		Ratings:         movie.Ratings,
+		HardlinkCount:   movie.HardlinkCount,
+		QBittorrentHash: movie.QBittorrentHash,
+		Seeding:         movie.IsSeeding,
+	}
+
+	if movie.MovieFile != nil {
+		record.File = movie.MovieFile.Path
+		record.SizeBytes = movie.MovieFile.Size
+		if movie.MovieFile.Quality != nil && movie.MovieFile.Quality.Quality != nil {
+			record.Quality = movie.MovieFile.Quality.Quality.Name
+		}
+	}
+
+	for username, userData := range movie.UserWatchData {
+		if userData.Watched {
+			record.WatchedBy = append(record.WatchedBy, username)
+		}
+	}
+	slices.Sort(record.WatchedBy)
+
+	return record
+}
+
+// FormatMovieList writes one record per movie and filter that matched it
+func (f *RecordFormatter) FormatMovieList(groups []MovieGroup, options FormatOptions) (string, error) {
+	return renderRecords(f, groupRecords(groups))
+}
+
+// FormatMoviesToDelete writes one record per movie delete acts on, followed
+// by the protected and held back movies it leaves alone
+func (f *RecordFormatter) FormatMoviesToDelete(plan DeletePlan, options FormatOptions) (string, error) {
+	records := groupRecords(plan.Groups)
+	for _, p := range plan.Protected {
+		record := NewMovieRecord(p.Movie)
+		record.Filter = p.Filter
+		record.Skipped = "protected by rule " + p.Rule
+		records = append(records, record)
+	}
+	for _, h := range plan.Held {
+		record := NewMovieRecord(h.Movie)
+		record.Filter = h.Filter
+		record.Skipped = "collection incomplete"
+		records = append(records, record)
+	}
+	return renderRecords(f, records)
+}
+
+// FormatUpgradeCandidates writes one record per upgrade candidate
+func (f *RecordFormatter) FormatUpgradeCandidates(candidates []UpgradeResult) (string, error) {
+	records := make([]UpgradeRecord, 0, len(candidates))
+	for _, candidate := range candidates {
+		records = append(records, UpgradeRecord{
+			MovieRecord:        NewMovieRecord(candidate.Movie),
+			CurrentFormats:     candidate.CurrentFormats,
+			CurrentFormatScore: candidate.CurrentFormatScore,
+			MissingFormats:     candidate.MissingFormats,
+			Available:          candidate.IsAvailable,
+			NeedsMonitoring:    candidate.NeedsMonitoring,
+		})
+	}
+	return renderRecords(f, records)
+}
+
+// FormatHardlinkResults writes one record per movie that is not hardlinked
+func (f *RecordFormatter) FormatHardlinkResults(movies []MovieInfo) (string, error) {
+	records := make([]MovieRecord, 0, len(movies))
+	for _, movie := range movies {
+		records = append(records, NewMovieRecord(movie))
+	}
+	return renderRecords(f, records)
+}
+
+// groupRecords flattens movies grouped by filter into records
+func groupRecords(groups []MovieGroup) []MovieRecord {
+	records := make([]MovieRecord, 0)
+	for _, group := range groups {
+		for _, movie := range group.Movies {
+			record := NewMovieRecord(movie)
+			record.Filter = group.Filter
+			record.Action = group.Action
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// renderRecords encodes records in the formatter's format
+func renderRecords[T any](f *RecordFormatter, records []T) (string, error) {
+	var buf bytes.Buffer
+
+	switch f.format {
+	case OutputJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(records); err != nil {
+			return "", err
+		}
+	case OutputNDJSON:
+		encoder := json.NewEncoder(&buf)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return "", err
+			}
+		}
+	case OutputYAML:
+		if len(records) == 0 {
+			return "[]\n", nil
+		}
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return "", err
+		}
+		if err := encoder.Close(); err != nil {
+			return "", err
+		}
+	case OutputCSV:
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(csvHeader(reflect.TypeFor[T]())); err != nil {
+			return "", err
+		}
+		for _, record := range records {
+			if err := writer.Write(csvRow(reflect.ValueOf(record))); err != nil {
+				return "", err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return "", err
+		}
+	case OutputTemplate:
+		for _, record := range records {
+			if err := f.template.Execute(&buf, record); err != nil {
+				return "", fmt.Errorf("failed to execute output template: %w", err)
+			}
+			buf.WriteString("\n")
+		}
+	default:
+		return "", fmt.Errorf("unknown output format %q", f.format)
+	}
+
+	return buf.String(), nil
+}
+
+// csvHeader returns the column names of a record type, taken from its JSON
+// field names with embedded records flattened
+func csvHeader(t reflect.Type) []string {
+	var header []string
+	for field := range fields(t) {
+		if field.Anonymous {
+			header = append(header, csvHeader(field.Type)...)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		header = append(header, name)
+	}
+	return header
+}
+
+// csvRow returns the columns of a record in the order of csvHeader
+func csvRow(v reflect.Value) []string {
+	var row []string
+	for field := range fields(v.Type()) {
+		value := v.FieldByIndex(field.Index)
+		if field.Anonymous {
+			row = append(row, csvRow(value)...)
+			continue
+		}
+		row = append(row, csvValue(value.Interface()))
+	}
+	return row
+}
+
+// fields yields the exported fields of a struct type
+func fields(t reflect.Type) iter.Seq[reflect.StructField] {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			if field := t.Field(i); field.IsExported() && !yield(field) {
+				return
+			}
+		}
+	}
+}
+
+// csvValue formats a record field as a CSV column. Lists are separated by
+// semicolons and zero times are left empty.
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	case map[string]float64:
+		var parts []string
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			parts = append(parts, key+"="+strconv.FormatFloat(v[key], 'f', -1, 64))
+		}
+		return strings.Join(parts, ";")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,225 @@
+package radarr
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"golift.io/starr"
+	"golift.io/starr/radarr"
+	"gopkg.in/yaml.v3"
+)
+
+func outputGroups() []MovieGroup {
+	heat := MovieInfo{
+		ID: 1, Instance: "main", Title: "Heat", Year: 1995, TMDBID: 949,
+		TagNames: []string{"keep", "classic"},
+		Added:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		MovieFile: &radarr.MovieFile{
+			Path:    "/movies/Heat (1995)/Heat.mkv",
+			Size:    1 << 30,
+			Quality: &starr.Quality{Quality: &starr.BaseQuality{Name: "Bluray-1080p"}},
+		},
+		UserWatchData: map[string]*UserWatchInfo{
+			"bob":   {Watched: true},
+			"alice": {Watched: true},
+			"carol": {Watched: false},
+		},
+	}
+	alien := MovieInfo{ID: 2, Instance: "main", Title: "Alien, the \"Director's Cut\"", Year: 1979}
+
+	return []MovieGroup{
+		{Filter: "old", Action: "delete", Movies: []MovieInfo{heat, alien}},
+		{Filter: "unwatched", Action: "tag \"leaving\"", Movies: []MovieInfo{alien}},
+	}
+}
+
+func format(t *testing.T, output string, groups []MovieGroup) string {
+	t.Helper()
+	formatter, err := NewFormatter(output)
+	if err != nil {
+		t.Fatalf("NewFormatter(%q) failed: %v", output, err)
+	}
+	result, err := formatter.FormatMovieList(groups, FormatOptions{})
+	if err != nil {
+		t.Fatalf("formatting as %q failed: %v", output, err)
+	}
+	return result
+}
+
+func TestNewFormatter(t *testing.T) {
+	for _, output := range []string{"", "text"} {
+		formatter, err := NewFormatter(output)
+		if err != nil {
+			t.Fatalf("NewFormatter(%q) failed: %v", output, err)
+		}
+		if _, ok := formatter.(*ConsoleFormatter); !ok {
+			t.Errorf("expected %q to select the console formatter, got %T", output, formatter)
+		}
+	}
+
+	for _, output := range []string{"xml", "template", "template={{.Title", "json=x"} {
+		if _, err := NewFormatter(output); err == nil {
+			t.Errorf("expected %q to be rejected", output)
+		}
+	}
+}
+
+func TestRecordFormatterJSON(t *testing.T) {
+	var records []MovieRecord
+	if err := json.Unmarshal([]byte(format(t, "json", outputGroups())), &records); err != nil {
+		t.Fatalf("output is not a JSON array: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected one record per movie and filter, got %d", len(records))
+	}
+
+	heat := records[0]
+	if heat.Filter != "old" || heat.Action != "delete" || heat.Quality != "Bluray-1080p" || heat.SizeBytes != 1<<30 {
+		t.Errorf("unexpected record %+v", heat)
+	}
+	if strings.Join(heat.WatchedBy, ",") != "alice,bob" {
+		t.Errorf("expected the users who watched Heat, sorted, got %v", heat.WatchedBy)
+	}
+	if records[2].Filter != "unwatched" {
+		t.Errorf("expected the last record to come from the second filter, got %+v", records[2])
+	}
+
+	if got := format(t, "json", nil); strings.TrimSpace(got) != "[]" {
+		t.Errorf("expected an empty JSON array without matches, got %q", got)
+	}
+}
+
+func TestRecordFormatterNDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(format(t, "ndjson", outputGroups())), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per record, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var record MovieRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Errorf("line %q is not a JSON object: %v", line, err)
+		}
+	}
+
+	if got := format(t, "ndjson", nil); got != "" {
+		t.Errorf("expected no output without matches, got %q", got)
+	}
+}
+
+func TestRecordFormatterCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(format(t, "csv", outputGroups()))).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected a header and three rows, got %d rows", len(rows))
+	}
+
+	column := make(map[string]int)
+	for i, name := range rows[0] {
+		column[name] = i
+	}
+	heat := rows[1]
+	if heat[column["title"]] != "Heat" || heat[column["tags"]] != "keep;classic" ||
+		heat[column["added"]] != "2024-01-02T00:00:00Z" || heat[column["last_watched"]] != "" {
+		t.Errorf("unexpected row %v", heat)
+	}
+	if title := rows[2][column["title"]]; title != "Alien, the \"Director's Cut\"" {
+		t.Errorf("expected quotes and commas to survive, got %q", title)
+	}
+
+	rows, err = csv.NewReader(strings.NewReader(format(t, "csv", nil))).ReadAll()
+	if err != nil || len(rows) != 1 {
+		t.Errorf("expected only the header without matches, got %v (%v)", rows, err)
+	}
+}
+
+func TestRecordFormatterYAML(t *testing.T) {
+	var records []MovieRecord
+	if err := yaml.Unmarshal([]byte(format(t, "yaml", outputGroups())), &records); err != nil {
+		t.Fatalf("output is not YAML: %v", err)
+	}
+	if len(records) != 3 || records[0].Title != "Heat" || records[0].TMDBID != 949 {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestRecordFormatterTemplate(t *testing.T) {
+	got := format(t, "template={{.Title}} ({{.Year}}) [{{.Filter}}]", outputGroups())
+	want := "Heat (1995) [old]\nAlien, the \"Director's Cut\" (1979) [old]\nAlien, the \"Director's Cut\" (1979) [unwatched]\n"
+	if got != want {
+		t.Errorf("expected %q but got %q", want, got)
+	}
+
+	formatter, err := NewFormatter("template={{.MissingFormats}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := formatter.FormatMovieList(outputGroups(), FormatOptions{}); err == nil {
+		t.Error("expected a template using a field movies don't have to fail")
+	}
+	candidates := []UpgradeResult{{Movie: MovieInfo{Title: "Heat"}, MissingFormats: []string{"DV", "HDR10"}}}
+	if got, err := formatter.FormatUpgradeCandidates(candidates); err != nil || got != "[DV HDR10]\n" {
+		t.Errorf("expected upgrade fields in upgrade templates, got %q (%v)", got, err)
+	}
+}
+
+func TestRecordFormatterDeletePlan(t *testing.T) {
+	formatter, err := NewFormatter("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := DeletePlan{
+		Groups:    outputGroups()[:1],
+		Protected: []SkippedMovie{{Movie: MovieInfo{Title: "Ronin"}, Filter: "old", Rule: "favourites"}},
+		Held:      []SkippedMovie{{Movie: MovieInfo{Title: "Aliens"}, Filter: "old"}},
+	}
+	output, err := formatter.FormatMoviesToDelete(plan, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []MovieRecord
+	if err := json.Unmarshal([]byte(output), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected the movies to act on and the skipped ones, got %+v", records)
+	}
+	if records[2].Skipped != "protected by rule favourites" || records[3].Skipped != "collection incomplete" {
+		t.Errorf("unexpected skipped records %+v", records[2:])
+	}
+	if records[0].Skipped != "" || records[0].Action != "delete" {
+		t.Errorf("unexpected record to act on %+v", records[0])
+	}
+}
+
+func TestUpgradeRecordCSV(t *testing.T) {
+	formatter, err := NewFormatter("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := formatter.FormatUpgradeCandidates([]UpgradeResult{{
+		Movie:          MovieInfo{Title: "Heat"},
+		CurrentFormats: []string{"x264"},
+		MissingFormats: []string{"DV"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[0]) != len(rows[1]) {
+		t.Fatalf("expected a header and one row of the same width, got %v", rows)
+	}
+	header := strings.Join(rows[0], ",")
+	if !strings.Contains(header, ",title,") || !strings.HasSuffix(header, ",missing_formats,available,needs_monitoring") {
+		t.Errorf("expected movie columns followed by upgrade columns, got %s", header)
+	}
+}
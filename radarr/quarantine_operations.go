@@ -0,0 +1,183 @@
+package radarr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/journal"
+	"github.com/s0up4200/arrbiter/quarantine"
+)
+
+// QuarantineMovies soft-deletes movies: each one is unmonitored in Radarr and its
+// folder is moved into the quarantine store, where it can later be restored or purged.
+// Movies that are already quarantined are skipped.
+func (o *Operations) QuarantineMovies(ctx context.Context, movies []MovieInfo, store *quarantine.Store, opts DeleteOptions) error {
+	var failed, skipped int
+	records := make([]journal.Record, 0, len(movies))
+	for _, movie := range movies {
+		err := o.quarantineMovie(ctx, movie, store, opts)
+		if errors.Is(err, quarantine.ErrAlreadyQuarantined) {
+			skipped++
+			o.logger.Info().
+				Int64("id", movie.ID).
+				Str("title", movie.Title).
+				Msg("Movie is already quarantined, skipping")
+			continue
+		}
+		if err != nil {
+			failed++
+			o.logger.Error().
+				Err(err).
+				Int64("id", movie.ID).
+				Str("title", movie.Title).
+				Msg("Failed to quarantine movie")
+		}
+
+		record := newJournalRecord(movie, journal.ActionQuarantine, err)
+		record.Filter = opts.FilterName
+		record.Expression = opts.FilterExpression
+		records = append(records, record)
+	}
+	o.recordActions(records...)
+
+	o.logger.Info().
+		Int("quarantined", len(movies)-failed-skipped).
+		Int("skipped", skipped).
+		Int("failed", failed).
+		Msg("Quarantine complete")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to quarantine %d movies", failed)
+	}
+
+	return nil
+}
+
+// quarantineMovie unmonitors a single movie and moves its folder into quarantine
+func (o *Operations) quarantineMovie(ctx context.Context, movie MovieInfo, store *quarantine.Store, opts DeleteOptions) error {
+	if movie.Path == "" {
+		return fmt.Errorf("movie has no path")
+	}
+
+	// Already quarantined movies stay unmonitored and keep their entry
+	quarantined, err := store.Contains(movie.Instance, movie.ID)
+	if err != nil {
+		return err
+	}
+	if quarantined {
+		return quarantine.ErrAlreadyQuarantined
+	}
+
+	// Unmonitor first so Radarr doesn't grab it again once the files are gone
+	if err := o.updateMovie(ctx, movie.ID, func(m *radarr.Movie) { m.Monitored = false }); err != nil {
+		return fmt.Errorf("failed to unmonitor: %w", err)
+	}
+
+	entry, err := store.Add(quarantine.Entry{
+		MovieID:            movie.ID,
+		Instance:           movie.Instance,
+		TMDBID:             movie.TMDBID,
+		Title:              movie.Title,
+		Year:               movie.Year,
+		OriginalPath:       movie.Path,
+		AddImportExclusion: opts.AddImportExclusion,
+	})
+	if errors.Is(err, quarantine.ErrAlreadyQuarantined) {
+		// The files are in quarantine, so the movie must stay unmonitored
+		return err
+	}
+	if err != nil {
+		// Put monitoring back so the movie isn't silently left half-quarantined
+		if restoreErr := o.updateMovie(ctx, movie.ID, func(m *radarr.Movie) { m.Monitored = true }); restoreErr != nil {
+			o.logger.Warn().Err(restoreErr).Str("title", movie.Title).Msg("Failed to re-monitor movie after quarantine failure")
+		}
+		return err
+	}
+
+	o.logger.Info().
+		Str("title", movie.Title).
+		Str("path", entry.QuarantinePath).
+		Msg("Moved movie to quarantine")
+	return nil
+}
+
+// RestoreQuarantinedMovie moves a quarantined folder back, re-monitors the movie
+// and asks Radarr to rescan it
+func (o *Operations) RestoreQuarantinedMovie(ctx context.Context, entry quarantine.Entry, store *quarantine.Store) error {
+	if err := store.Restore(entry); err != nil {
+		return err
+	}
+
+	if err := o.updateMovie(ctx, entry.MovieID, func(m *radarr.Movie) { m.Monitored = true }); err != nil {
+		return fmt.Errorf("files restored but failed to re-monitor %s: %w", entry.Title, err)
+	}
+
+	if _, err := o.client.SendCommand(ctx, &radarr.CommandRequest{
+		Name:     "RescanMovie",
+		MovieIDs: []int64{entry.MovieID},
+	}); err != nil {
+		o.logger.Warn().Err(err).Str("title", entry.Title).Msg("Failed to trigger rescan after restore")
+	}
+
+	o.logger.Info().Str("title", entry.Title).Str("path", entry.OriginalPath).Msg("Restored movie from quarantine")
+	return nil
+}
+
+// PurgeQuarantinedMovies removes quarantined movies from Radarr and deletes their folders for good.
+// Movies Radarr fails to remove keep their files so they can still be restored.
+func (o *Operations) PurgeQuarantinedMovies(ctx context.Context, entries []quarantine.Entry, store *quarantine.Store) error {
+	var failed int
+	records := make([]journal.Record, 0, len(entries))
+	for _, entry := range entries {
+		err := o.purgeQuarantinedMovie(ctx, entry, store)
+		if err != nil {
+			failed++
+			o.logger.Error().Err(err).Str("title", entry.Title).Msg("Failed to purge quarantined movie")
+		}
+		records = append(records, purgeJournalRecord(entry, err))
+	}
+	o.recordActions(records...)
+
+	o.logger.Info().
+		Int("purged", len(entries)-failed).
+		Int("failed", failed).
+		Msg("Purge complete")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to purge %d movies", failed)
+	}
+
+	return nil
+}
+
+// purgeQuarantinedMovie removes a single movie from Radarr, then deletes its quarantined folder
+func (o *Operations) purgeQuarantinedMovie(ctx context.Context, entry quarantine.Entry, store *quarantine.Store) error {
+	// The files already live in quarantine, so Radarr only needs to drop the entry
+	if err := o.client.DeleteMovie(ctx, entry.MovieID, false, entry.AddImportExclusion); err != nil {
+		return fmt.Errorf("failed to remove movie from Radarr, files kept in quarantine: %w", err)
+	}
+	return store.Purge(entry)
+}
+
+// purgeJournalRecord describes a purged quarantine entry for the journal
+func purgeJournalRecord(entry quarantine.Entry, err error) journal.Record {
+	record := journal.Record{
+		Action:   journal.ActionPurge,
+		Outcome:  journal.OutcomeSuccess,
+		MovieID:  entry.MovieID,
+		Instance: entry.Instance,
+		TMDBID:   entry.TMDBID,
+		Title:    entry.Title,
+		Year:     entry.Year,
+		Path:     entry.OriginalPath,
+		Details:  fmt.Sprintf("quarantined %s at %s", entry.QuarantinedAt.Format("2006-01-02"), entry.QuarantinePath),
+	}
+	if err != nil {
+		record.Outcome = journal.OutcomeFailed
+		record.Error = err.Error()
+	}
+	return record
+}
@@ -0,0 +1,120 @@
+package radarr
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/journal"
+)
+
+// UndoOptions contains options for undoing a deletion
+type UndoOptions struct {
+	Search   bool     // Trigger a search once the movie is back in Radarr
+	DropTags []string // Recorded tags not to restore, e.g. the leaving soon tag
+}
+
+// UndoDeletion adds a movie deleted by arrbiter back to Radarr using the quality
+// profile, root folder, tags and monitored state recorded in the journal, and
+// removes the import exclusion arrbiter created for it. Tags in opts.DropTags
+// are left off.
+func (o *Operations) UndoDeletion(ctx context.Context, record journal.Record, opts UndoOptions) (*radarr.Movie, error) {
+	movie, steps, err := o.undoDeletion(ctx, record, opts)
+
+	undo := journal.Record{
+		Action:   journal.ActionUndo,
+		Outcome:  journal.OutcomeSuccess,
+		MovieID:  record.MovieID,
+		Instance: record.Instance,
+		TMDBID:   record.TMDBID,
+		IMDBID:   record.IMDBID,
+		Title:    record.Title,
+		Year:     record.Year,
+		Path:     record.Path,
+		Filter:   record.Filter,
+		Radarr:   record.Radarr,
+		Details:  strings.Join(steps, "; "),
+	}
+	if movie != nil {
+		undo.MovieID = movie.ID
+	}
+	if err != nil {
+		undo.Outcome = journal.OutcomeFailed
+		undo.Error = err.Error()
+	}
+	o.recordActions(undo)
+
+	return movie, err
+}
+
+// undoDeletion does the work for UndoDeletion and reports the steps it completed
+func (o *Operations) undoDeletion(ctx context.Context, record journal.Record, opts UndoOptions) (*radarr.Movie, []string, error) {
+	if record.Action != journal.ActionDelete {
+		return nil, nil, fmt.Errorf("%s was not deleted by arrbiter (action: %s)", record.Title, record.Action)
+	}
+	if record.Radarr == nil || record.Radarr.QualityProfileID == 0 || record.Radarr.RootFolder == "" {
+		return nil, nil, fmt.Errorf("journal entry for %s has no Radarr settings to restore", record.Title)
+	}
+
+	var steps []string
+
+	tagIDs := make([]int, 0, len(record.Radarr.Tags))
+	for _, name := range record.Radarr.Tags {
+		if slices.ContainsFunc(opts.DropTags, func(drop string) bool { return strings.EqualFold(drop, name) }) {
+			continue
+		}
+		tag, err := o.client.GetOrCreateTag(ctx, name)
+		if err != nil {
+			return nil, steps, err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	// Drop the exclusion first, Radarr refuses to add excluded movies
+	if record.Radarr.AddedExclusion {
+		removed, err := o.client.RemoveImportExclusion(ctx, record.TMDBID)
+		if err != nil {
+			return nil, steps, err
+		}
+		if removed {
+			steps = append(steps, "import exclusion removed")
+		}
+	}
+
+	movie, err := o.client.AddMovie(ctx, &radarr.AddMovieInput{
+		Title:               record.Title,
+		Year:                record.Year,
+		TmdbID:              record.TMDBID,
+		QualityProfileID:    record.Radarr.QualityProfileID,
+		RootFolderPath:      record.Radarr.RootFolder,
+		MinimumAvailability: cmp.Or(radarr.Availability(record.Radarr.Availability), radarr.AvailabilityReleased),
+		Tags:                tagIDs,
+		Monitored:           record.Radarr.Monitored,
+		AddOptions:          &radarr.AddMovieOptions{Monitor: "movieOnly"},
+	})
+	if err != nil {
+		return nil, steps, err
+	}
+	steps = append(steps, "re-added to Radarr")
+
+	if opts.Search {
+		if _, err := o.client.SendCommand(ctx, &radarr.CommandRequest{
+			Name:     "MoviesSearch",
+			MovieIDs: []int64{movie.ID},
+		}); err != nil {
+			return movie, steps, fmt.Errorf("movie re-added but search failed: %w", err)
+		}
+		steps = append(steps, "search triggered")
+	}
+
+	o.logger.Info().
+		Str("title", record.Title).
+		Int64("movie_id", movie.ID).
+		Msg("Undid deletion")
+
+	return movie, steps, nil
+}
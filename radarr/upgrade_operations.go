@@ -3,9 +3,12 @@ package radarr
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/journal"
 )
 
 // UpgradeOptions contains options for upgrade operations
@@ -14,8 +17,22 @@ type UpgradeOptions struct {
 	MinFormatScore      int      // Minimum custom format score required
 	CheckAvailability   bool     // Whether to check if movie is released before searching
 	DryRun              bool     // Whether to run in dry-run mode
+	Monitor             bool     // Whether to enable monitoring on unmonitored movies
+}
+
+// UpgradeSummary counts what ProcessUpgrades did
+type UpgradeSummary struct {
+	Searched      int // Movies a search was triggered for
+	SearchFailed  int // Movies whose search could not be triggered
+	MonitorFailed int // Movies monitoring could not be enabled for
+	Unavailable   int // Movies not searched because they aren't released yet
 }
 
+// upgradeSearchBatchSize and upgradeSearchDelay pace the searches sent to Radarr
+const upgradeSearchBatchSize = 10
+
+var upgradeSearchDelay = 2 * time.Second
+
 // UpgradeResult contains information about a movie that needs upgrading
 type UpgradeResult struct {
 	Movie               MovieInfo
@@ -230,16 +247,21 @@ func (o *Operations) TriggerUpgradeSearch(ctx context.Context, movieIDs []int64)
 }
 
 // ProcessUpgrades handles the upgrade workflow for a list of upgrade candidates
-func (o *Operations) ProcessUpgrades(ctx context.Context, candidates []UpgradeResult, opts UpgradeOptions) error {
+func (o *Operations) ProcessUpgrades(ctx context.Context, candidates []UpgradeResult, opts UpgradeOptions) (UpgradeSummary, error) {
+	var summary UpgradeSummary
 	if len(candidates) == 0 {
 		o.logger.Info().Msg("No movies need upgrading")
-		return nil
+		return summary, nil
 	}
 
 	if opts.DryRun {
 		o.logger.Info().Msg("DRY RUN MODE - No changes will be made")
-		fmt.Print(o.formatter.FormatUpgradeCandidates(candidates))
-		return nil
+		output, err := o.formatter.FormatUpgradeCandidates(candidates)
+		if err != nil {
+			return summary, err
+		}
+		fmt.Print(output)
+		return summary, nil
 	}
 
 	// Group by actions needed
@@ -248,40 +270,91 @@ func (o *Operations) ProcessUpgrades(ctx context.Context, candidates []UpgradeRe
 
 	for _, candidate := range candidates {
 		// Enable monitoring if needed
-		if candidate.NeedsMonitoring {
+		if opts.Monitor && candidate.NeedsMonitoring {
 			toMonitor = append(toMonitor, candidate.Movie.ID)
 		}
 
 		// Only search if available
-		if candidate.IsAvailable {
+		if candidate.IsAvailable || !opts.CheckAvailability {
 			toSearch = append(toSearch, candidate.Movie.ID)
+		} else {
+			summary.Unavailable++
 		}
 	}
 
 	// Enable monitoring for unmonitored movies
+	monitorErrs := make(map[int64]error)
 	if len(toMonitor) > 0 {
 		o.logger.Info().Int("count", len(toMonitor)).Msg("Enabling monitoring for movies")
 		for _, movieID := range toMonitor {
 			if err := o.MonitorMovie(ctx, movieID); err != nil {
 				o.logger.Error().Err(err).Int64("movie_id", movieID).Msg("Failed to enable monitoring")
+				monitorErrs[movieID] = err
+				summary.MonitorFailed++
 				// Continue with other movies
 			}
 		}
 	}
 
-	// Trigger searches using concurrent batch processing
+	// Trigger searches in batches, remembering which ones failed
+	searchErrs := make(map[int64]error)
 	if len(toSearch) > 0 {
 		o.logger.Info().Int("count", len(toSearch)).Msg("Triggering upgrade searches")
-		if err := o.client.BatchSearchMovies(ctx, toSearch); err != nil {
-			o.logger.Error().Err(err).Msg("Failed to trigger some searches")
+		for i := 0; i < len(toSearch); i += upgradeSearchBatchSize {
+			end := min(i+upgradeSearchBatchSize, len(toSearch))
+			batch := toSearch[i:end]
+
+			if err := o.TriggerUpgradeSearch(ctx, batch); err != nil {
+				o.logger.Error().Err(err).Ints64("movie_ids", batch).Msg("Failed to trigger upgrade search")
+				for _, movieID := range batch {
+					searchErrs[movieID] = err
+				}
+				summary.SearchFailed += len(batch)
+			} else {
+				summary.Searched += len(batch)
+			}
+
+			// Add a small delay between batches
+			if end < len(toSearch) {
+				time.Sleep(upgradeSearchDelay)
+			}
 		}
 	}
 
+	o.recordActions(upgradeJournalRecords(candidates, opts, monitorErrs, searchErrs)...)
+
 	o.logger.Info().
-		Int("monitored", len(toMonitor)).
-		Int("searched", len(toSearch)).
+		Int("monitored", len(toMonitor)-summary.MonitorFailed).
+		Int("searched", summary.Searched).
 		Msg("Upgrade processing complete")
 
-	return nil
+	return summary, nil
 }
 
+// upgradeJournalRecords describes what ProcessUpgrades did to each candidate
+func upgradeJournalRecords(candidates []UpgradeResult, opts UpgradeOptions, monitorErrs, searchErrs map[int64]error) []journal.Record {
+	records := make([]journal.Record, 0, len(candidates))
+	for _, candidate := range candidates {
+		var steps []string
+		err := monitorErrs[candidate.Movie.ID]
+		if opts.Monitor && candidate.NeedsMonitoring && err == nil {
+			steps = append(steps, "monitored")
+		}
+		if candidate.IsAvailable || !opts.CheckAvailability {
+			steps = append(steps, "search triggered")
+			if err == nil {
+				err = searchErrs[candidate.Movie.ID]
+			}
+		} else {
+			steps = append(steps, "not yet available")
+		}
+		if len(candidate.MissingFormats) > 0 {
+			steps = append(steps, "missing "+strings.Join(candidate.MissingFormats, ", "))
+		}
+
+		record := newJournalRecord(candidate.Movie, journal.ActionUpgrade, err)
+		record.Details = strings.Join(steps, "; ")
+		records = append(records, record)
+	}
+	return records
+}
@@ -1,10 +1,16 @@
 package radarr
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/journal"
 )
 
 func TestIsMovieAvailable(t *testing.T) {
@@ -77,4 +83,67 @@ func TestIsMovieAvailable(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestProcessUpgradesWritesJournal(t *testing.T) {
+	mockAPI := &mockRadarrAPI{
+		movies: []*radarr.Movie{
+			{ID: 1, Title: "Heat", Monitored: true},
+			{ID: 2, Title: "Ronin", Monitored: false},
+		},
+	}
+	logger := zerolog.New(nil).Level(zerolog.Disabled)
+	ops := NewOperations(NewClientWithAPI(mockAPI, logger), logger)
+
+	j := journal.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	ops.SetJournal(j)
+
+	candidates := []UpgradeResult{
+		{Movie: MovieInfo{ID: 1, Title: "Heat", Year: 1995}, MissingFormats: []string{"HDR"}, IsAvailable: true},
+		{Movie: MovieInfo{ID: 2, Title: "Ronin", Year: 1998}, MissingFormats: []string{"HDR"}, NeedsMonitoring: true},
+	}
+
+	// The options the upgrade command uses: monitor and search regardless of release
+	summary, err := ops.ProcessUpgrades(context.Background(), candidates, UpgradeOptions{Monitor: true})
+	if err != nil {
+		t.Fatalf("ProcessUpgrades failed: %v", err)
+	}
+	if summary.Searched != 2 || summary.SearchFailed != 0 || summary.MonitorFailed != 0 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if !mockAPI.movies[1].Monitored {
+		t.Error("expected Ronin to be monitored")
+	}
+
+	records, err := j.Read(journal.Query{Action: journal.ActionUpgrade})
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 upgrade records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.Outcome != journal.OutcomeSuccess {
+			t.Errorf("expected %s to succeed, got %s", record.Title, record.Outcome)
+		}
+	}
+	if records[1].Title != "Ronin" || records[1].Details != "monitored; search triggered; missing HDR" {
+		t.Errorf("unexpected record for Ronin: %s %q", records[1].Title, records[1].Details)
+	}
+
+	// A failed search is recorded as failed
+	mockAPI.commandErr = errors.New("radarr is down")
+	summary, err = ops.ProcessUpgrades(context.Background(), candidates[:1], UpgradeOptions{Monitor: true})
+	if err != nil {
+		t.Fatalf("ProcessUpgrades failed: %v", err)
+	}
+	if summary.SearchFailed != 1 {
+		t.Errorf("expected the search to fail, got %+v", summary)
+	}
+	records, err = j.Read(journal.Query{Action: journal.ActionUpgrade})
+	if err != nil || len(records) != 3 {
+		t.Fatalf("expected 3 upgrade records, got %d (%v)", len(records), err)
+	}
+	if failed := records[2]; failed.Outcome != journal.OutcomeFailed || failed.Error == "" {
+		t.Errorf("expected a failed record with the error, got %+v", failed)
+	}
+}
@@ -0,0 +1,97 @@
+package review
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyInterrupt
+)
+
+// key is a key press, r is set for keyRune
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// escapeSequences maps the sequences terminals send for special keys
+var escapeSequences = map[string]keyCode{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1bOH":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1bOF":  keyEnd,
+	"\x1b[4~": keyEnd,
+}
+
+// parseKeys decodes the bytes read from a terminal in raw mode into key
+// presses. Escape sequences for keys it doesn't know are dropped.
+func parseKeys(input []byte) []key {
+	var keys []key
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b:
+			n := escapeLength(input)
+			if n == 1 {
+				keys = append(keys, key{code: keyEscape})
+			} else if code, ok := escapeSequences[string(input[:n])]; ok {
+				keys = append(keys, key{code: code})
+			}
+			input = input[n:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case b == 0x03:
+			keys = append(keys, key{code: keyInterrupt})
+		case b < 0x20:
+			// Other control characters
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, key{code: keyRune, r: r})
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// escapeLength returns the length of the escape sequence at the start of
+// input, 1 for a lone escape
+func escapeLength(input []byte) int {
+	if len(input) < 2 {
+		return 1
+	}
+	switch input[1] {
+	case 'O':
+		return min(3, len(input))
+	case '[':
+		// Parameters, then a final byte in 0x40-0x7e
+		if end := bytes.IndexFunc(input[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e }); end >= 0 {
+			return end + 3
+		}
+		return len(input)
+	}
+	return 1
+}
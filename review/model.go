@@ -0,0 +1,508 @@
+package review
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// sortOrder is how movies are ordered within each filter
+type sortOrder int
+
+const (
+	sortDefault sortOrder = iota // The order the candidates were given in
+	sortSize                     // Largest first
+	sortAdded                    // Oldest first
+	sortRating                   // Lowest rated first
+)
+
+var sortNames = map[sortOrder]string{
+	sortDefault: "default order",
+	sortSize:    "size",
+	sortAdded:   "date added",
+	sortRating:  "rating",
+}
+
+type state int
+
+const (
+	stateBrowsing state = iota
+	stateSearching
+	stateConfirming
+	stateApplied
+	stateCancelled
+)
+
+// entry is a candidate and whether it is selected
+type entry struct {
+	Candidate
+	index     int // Position in the candidates, for the default order
+	selected  bool
+	protected bool
+}
+
+// model is the state of a review session, independent of the terminal
+type model struct {
+	entries    []*entry
+	visible    []*entry // Entries matching the search, grouped by filter and sorted
+	filters    []string // Filters in the order their groups are shown
+	cursor     int      // Index into visible
+	offset     int      // First list row on screen
+	order      sortOrder
+	query      string
+	state      state
+	message    string
+	protect    func(radarr.MovieInfo) error
+	protectTag string
+}
+
+func newModel(candidates []Candidate, opts Options) *model {
+	m := &model{protect: opts.Protect, protectTag: opts.ProtectTag}
+	for i, candidate := range candidates {
+		m.entries = append(m.entries, &entry{Candidate: candidate, index: i, selected: true})
+		if !slices.Contains(m.filters, candidate.Filter) {
+			m.filters = append(m.filters, candidate.Filter)
+		}
+	}
+	m.refresh()
+	return m
+}
+
+// selected returns the candidates left selected, in the order they were given
+func (m *model) selected() []Candidate {
+	var result []Candidate
+	for _, e := range m.entries {
+		if e.selected {
+			result = append(result, e.Candidate)
+		}
+	}
+	return result
+}
+
+// refresh recomputes the visible entries after the search or sort order
+// changed, keeping the cursor on the same movie when it is still shown
+func (m *model) refresh() {
+	var current *entry
+	if m.cursor < len(m.visible) {
+		current = m.visible[m.cursor]
+	}
+
+	query := strings.ToLower(m.query)
+	m.visible = m.visible[:0]
+	for _, e := range m.entries {
+		if query == "" || strings.Contains(strings.ToLower(e.Movie.Title), query) {
+			m.visible = append(m.visible, e)
+		}
+	}
+
+	slices.SortStableFunc(m.visible, func(a, b *entry) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(m.filters, a.Filter), slices.Index(m.filters, b.Filter)),
+			m.compare(a, b),
+			cmp.Compare(a.index, b.index),
+		)
+	})
+
+	m.cursor = max(slices.Index(m.visible, current), 0)
+}
+
+func (m *model) compare(a, b *entry) int {
+	switch m.order {
+	case sortSize:
+		return cmp.Compare(fileSize(b.Movie), fileSize(a.Movie))
+	case sortAdded:
+		return a.Movie.Added.Compare(b.Movie.Added)
+	case sortRating:
+		return cmp.Compare(rating(a.Movie), rating(b.Movie))
+	}
+	return 0
+}
+
+// handle updates the model for a key press. pageSize is the number of list
+// rows on screen.
+func (m *model) handle(k key, pageSize int) {
+	if k.code == keyInterrupt {
+		m.state = stateCancelled
+		return
+	}
+	m.message = ""
+
+	switch m.state {
+	case stateSearching:
+		switch k.code {
+		case keyRune:
+			m.query += string(k.r)
+		case keyBackspace:
+			if m.query != "" {
+				_, size := utf8.DecodeLastRuneInString(m.query)
+				m.query = m.query[:len(m.query)-size]
+			}
+		case keyEnter:
+			m.state = stateBrowsing
+			return
+		case keyEscape:
+			m.query = ""
+			m.state = stateBrowsing
+		default:
+			m.move(k, pageSize)
+			return
+		}
+		m.refresh()
+		return
+
+	case stateConfirming:
+		if k.code == keyRune && (k.r == 'y' || k.r == 'Y') {
+			m.state = stateApplied
+			return
+		}
+		m.state = stateBrowsing
+		return
+	}
+
+	if m.move(k, pageSize) {
+		return
+	}
+
+	switch k.code {
+	case keyEnter:
+		m.state = stateConfirming
+	case keyEscape:
+		m.state = stateCancelled
+	case keyRune:
+		switch k.r {
+		case 'q':
+			m.state = stateCancelled
+		case ' ', 'x':
+			m.toggle()
+		case 'a':
+			m.selectVisible(true)
+		case 'n':
+			m.selectVisible(false)
+		case '/':
+			m.state = stateSearching
+		case 's':
+			m.order = (m.order + 1) % sortOrder(len(sortNames))
+			m.refresh()
+			m.message = "Sorted by " + sortNames[m.order]
+		case 'p':
+			m.protectCurrent()
+		}
+	}
+}
+
+// move handles cursor movement and reports whether the key moved the cursor
+func (m *model) move(k key, pageSize int) bool {
+	switch {
+	case k.code == keyUp || k.code == keyRune && k.r == 'k':
+		m.cursor--
+	case k.code == keyDown || k.code == keyRune && k.r == 'j':
+		m.cursor++
+	case k.code == keyPageUp:
+		m.cursor -= max(pageSize, 1)
+	case k.code == keyPageDown:
+		m.cursor += max(pageSize, 1)
+	case k.code == keyHome || k.code == keyRune && k.r == 'g':
+		m.cursor = 0
+	case k.code == keyEnd || k.code == keyRune && k.r == 'G':
+		m.cursor = len(m.visible) - 1
+	default:
+		return false
+	}
+	m.cursor = max(min(m.cursor, len(m.visible)-1), 0)
+	return true
+}
+
+func (m *model) current() *entry {
+	if m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+func (m *model) toggle() {
+	e := m.current()
+	switch {
+	case e == nil:
+	case e.protected:
+		m.message = fmt.Sprintf("%s is protected", movieName(e.Movie))
+	default:
+		e.selected = !e.selected
+	}
+}
+
+func (m *model) selectVisible(selected bool) {
+	for _, e := range m.visible {
+		if !e.protected {
+			e.selected = selected
+		}
+	}
+}
+
+// protectCurrent tags the movie under the cursor so protection rules skip it
+// and leaves it out of this run
+func (m *model) protectCurrent() {
+	e := m.current()
+	switch {
+	case e == nil:
+		return
+	case e.protected:
+		m.message = fmt.Sprintf("%s is already protected", movieName(e.Movie))
+		return
+	case m.protect == nil && m.protectTag == "":
+		m.message = "Protecting needs review.protect_tag to be set"
+		return
+	case m.protect == nil:
+		m.message = "Protecting is not available in dry-run mode"
+		return
+	}
+
+	if err := m.protect(e.Movie); err != nil {
+		m.message = fmt.Sprintf("Failed to protect %s: %v", movieName(e.Movie), err)
+		return
+	}
+
+	// The same movie can be a candidate of several filters
+	for _, other := range m.entries {
+		if other.Movie.Key() == e.Movie.Key() {
+			other.protected = true
+			other.selected = false
+		}
+	}
+	m.message = fmt.Sprintf("Tagged %s with '%s'", movieName(e.Movie), m.protectTag)
+}
+
+// listHeight returns the number of list rows on a screen of the given height
+func listHeight(height int) int {
+	return max(height-5, 1)
+}
+
+// view renders the screen, one string per terminal line
+func (m *model) view(width, height int) []string {
+	var selected int
+	var selectedSize int64
+	for _, e := range m.entries {
+		if e.selected {
+			selected++
+			selectedSize += fileSize(e.Movie)
+		}
+	}
+
+	header := fmt.Sprintf(" Review delete candidates: %d of %d selected (%s) · sorted by %s",
+		selected, len(m.entries), formatSize(selectedSize), sortNames[m.order])
+	if m.query != "" || m.state == stateSearching {
+		header += " · search: " + m.query
+		if m.state == stateSearching {
+			header += "_"
+		}
+	}
+
+	lines := []string{
+		"\x1b[1m" + truncate(header, width) + "\x1b[0m",
+		strings.Repeat("─", width),
+	}
+
+	// List rows, with a heading for each filter
+	var rows []string
+	cursorRow := 0
+	previous := ""
+	for i, e := range m.visible {
+		if i == 0 || e.Filter != previous {
+			rows = append(rows, truncate(m.groupHeading(e), width))
+			previous = e.Filter
+		}
+		if i == m.cursor {
+			cursorRow = len(rows)
+		}
+		rows = append(rows, m.row(e, i == m.cursor, width))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, "  No movies match the search")
+	}
+
+	pageSize := listHeight(height)
+	if cursorRow < m.offset {
+		m.offset = cursorRow
+	}
+	if cursorRow >= m.offset+pageSize {
+		m.offset = cursorRow - pageSize + 1
+	}
+	m.offset = max(min(m.offset, len(rows)-pageSize), 0)
+	for i := range pageSize {
+		if m.offset+i < len(rows) {
+			lines = append(lines, rows[m.offset+i])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	lines = append(lines, strings.Repeat("─", width))
+	if e := m.current(); e != nil {
+		lines = append(lines, truncate(" "+details(e.Movie), width))
+	} else {
+		lines = append(lines, "")
+	}
+
+	switch {
+	case m.state == stateConfirming:
+		lines = append(lines, truncate(fmt.Sprintf(" Apply the filter actions to %d movie(s)? [y/N]", selected), width))
+	case m.message != "":
+		lines = append(lines, truncate(" "+m.message, width))
+	case m.state == stateSearching:
+		lines = append(lines, truncate(" Type to search titles  enter done  esc clear", width))
+	default:
+		lines = append(lines, truncate(fmt.Sprintf(" ↑↓ move  space toggle  a all  n none  / search  s sort  p protect ('%s')  enter apply  q cancel", m.protectTag), width))
+	}
+
+	return lines
+}
+
+func (m *model) groupHeading(first *entry) string {
+	var total, selected int
+	for _, e := range m.visible {
+		if e.Filter == first.Filter {
+			total++
+			if e.selected {
+				selected++
+			}
+		}
+	}
+	heading := "── " + first.Filter
+	if first.Action != "" {
+		heading += " → " + first.Action
+	}
+	return fmt.Sprintf("%s (%d of %d selected)", heading, selected, total)
+}
+
+func (m *model) row(e *entry, isCursor bool, width int) string {
+	check := "[ ]"
+	switch {
+	case e.protected:
+		check = "[P]"
+	case e.selected:
+		check = "[x]"
+	}
+
+	watch := "unwatched"
+	if e.Movie.WatchCount > 0 {
+		watch = fmt.Sprintf("watched %dx", e.Movie.WatchCount)
+	}
+	added := "          "
+	if !e.Movie.Added.IsZero() {
+		added = e.Movie.Added.Format("2006-01-02")
+	}
+	request := ""
+	if e.Movie.IsRequested && e.Movie.RequestedBy != "" {
+		request = "requested by " + e.Movie.RequestedBy
+	}
+
+	const columns = 44 // Everything but the title
+	titleWidth := max(width-columns-utf8.RuneCountInString(request), 20)
+	title := pad(truncate(movieName(e.Movie), titleWidth), titleWidth)
+
+	line := fmt.Sprintf("  %s %s %9s  %s  %-11s  %s", check, title, formatSize(fileSize(e.Movie)), added, watch, request)
+	line = truncate(line, width)
+	if isCursor {
+		return "\x1b[7m" + pad(line, width) + "\x1b[0m"
+	}
+	return line
+}
+
+// details describes a movie in one line for the status bar
+func details(movie radarr.MovieInfo) string {
+	parts := []string{movieName(movie)}
+	if movie.MovieFile != nil && movie.MovieFile.Quality != nil && movie.MovieFile.Quality.Quality != nil {
+		parts = append(parts, movie.MovieFile.Quality.Quality.Name)
+	}
+	if r := rating(movie); r > 0 {
+		parts = append(parts, fmt.Sprintf("rated %.1f", r))
+	}
+
+	var watchers []string
+	for username, userData := range movie.UserWatchData {
+		if userData.Watched {
+			watchers = append(watchers, username)
+		}
+	}
+	slices.Sort(watchers)
+	if len(watchers) > 0 {
+		watched := "watched by " + strings.Join(watchers, ", ")
+		if !movie.LastWatched.IsZero() {
+			watched += ", last " + movie.LastWatched.Format("2006-01-02")
+		}
+		parts = append(parts, watched)
+	}
+
+	if movie.IsRequested {
+		requested := "requested"
+		if movie.RequestedBy != "" {
+			requested += " by " + movie.RequestedBy
+		}
+		if !movie.RequestDate.IsZero() {
+			requested += " on " + movie.RequestDate.Format("2006-01-02")
+		}
+		parts = append(parts, requested)
+	}
+	if len(movie.TagNames) > 0 {
+		parts = append(parts, "tags "+strings.Join(movie.TagNames, ", "))
+	}
+	if movie.Path != "" {
+		parts = append(parts, movie.Path)
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+func movieName(movie radarr.MovieInfo) string {
+	name := fmt.Sprintf("%s (%d)", movie.Title, movie.Year)
+	if movie.Instance != "" && movie.Instance != "default" {
+		name += " [" + movie.Instance + "]"
+	}
+	return name
+}
+
+func fileSize(movie radarr.MovieInfo) int64 {
+	if movie.MovieFile == nil {
+		return 0
+	}
+	return movie.MovieFile.Size
+}
+
+// rating returns the IMDb rating of a movie, or its TMDB rating without one
+func rating(movie radarr.MovieInfo) float64 {
+	if r, ok := movie.Ratings["imdb"]; ok {
+		return r
+	}
+	return movie.Ratings["tmdb"]
+}
+
+func formatSize(bytes int64) string {
+	switch {
+	case bytes <= 0:
+		return "-"
+	case bytes >= 1<<40:
+		return fmt.Sprintf("%.1f TB", float64(bytes)/(1<<40))
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
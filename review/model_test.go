@@ -0,0 +1,265 @@
+package review
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golift.io/starr/radarr"
+
+	arrbiter_radarr "github.com/s0up4200/arrbiter/radarr"
+)
+
+func candidate(id int64, title, filter string, sizeGB int64, added string, imdb float64) Candidate {
+	movie := arrbiter_radarr.MovieInfo{ID: id, Title: title, Year: 2000, Ratings: map[string]float64{"imdb": imdb}}
+	if sizeGB > 0 {
+		movie.MovieFile = &radarr.MovieFile{Size: sizeGB << 30}
+	}
+	movie.Added, _ = time.Parse("2006-01-02", added)
+	return Candidate{Movie: movie, Filter: filter, Action: "delete"}
+}
+
+func testCandidates() []Candidate {
+	return []Candidate{
+		candidate(1, "Heat", "old", 40, "2020-01-01", 8.3),
+		candidate(2, "Alien", "old", 10, "2018-01-01", 8.5),
+		candidate(3, "Ronin", "old", 25, "2022-01-01", 7.2),
+		candidate(4, "Cats", "bad", 5, "2021-01-01", 2.8),
+	}
+}
+
+func press(m *model, keys ...key) {
+	for _, k := range keys {
+		m.handle(k, 10)
+	}
+}
+
+func runes(s string) []key {
+	var keys []key
+	for _, r := range s {
+		keys = append(keys, key{code: keyRune, r: r})
+	}
+	return keys
+}
+
+func visibleTitles(m *model) []string {
+	var result []string
+	for _, e := range m.visible {
+		result = append(result, e.Movie.Title)
+	}
+	return result
+}
+
+func selectedTitles(m *model) []string {
+	var result []string
+	for _, c := range m.selected() {
+		result = append(result, c.Movie.Title)
+	}
+	return result
+}
+
+func TestModelToggle(t *testing.T) {
+	m := newModel(testCandidates(), Options{})
+
+	if got := selectedTitles(m); len(got) != 4 {
+		t.Fatalf("expected every candidate to start selected, got %v", got)
+	}
+
+	press(m, key{code: keyDown}, key{code: keyRune, r: ' '})
+	if got := selectedTitles(m); !slices.Equal(got, []string{"Heat", "Ronin", "Cats"}) {
+		t.Errorf("expected Alien to be deselected, got %v", got)
+	}
+
+	press(m, runes("n")...)
+	if got := selectedTitles(m); len(got) != 0 {
+		t.Errorf("expected none to be selected, got %v", got)
+	}
+	press(m, runes("a")...)
+	if got := selectedTitles(m); len(got) != 4 {
+		t.Errorf("expected all to be selected, got %v", got)
+	}
+
+	// The cursor stays within the list
+	press(m, key{code: keyPageDown}, key{code: keyDown})
+	if m.cursor != 3 {
+		t.Errorf("expected the cursor on the last movie, got %d", m.cursor)
+	}
+	press(m, key{code: keyHome})
+	if m.cursor != 0 {
+		t.Errorf("expected the cursor on the first movie, got %d", m.cursor)
+	}
+}
+
+func TestModelSort(t *testing.T) {
+	m := newModel(testCandidates(), Options{})
+
+	if got := visibleTitles(m); !slices.Equal(got, []string{"Heat", "Alien", "Ronin", "Cats"}) {
+		t.Errorf("expected the given order grouped by filter, got %v", got)
+	}
+
+	tests := []struct {
+		order sortOrder
+		want  []string
+	}{
+		{sortSize, []string{"Heat", "Ronin", "Alien", "Cats"}},
+		{sortAdded, []string{"Alien", "Heat", "Ronin", "Cats"}},
+		{sortRating, []string{"Ronin", "Heat", "Alien", "Cats"}},
+		{sortDefault, []string{"Heat", "Alien", "Ronin", "Cats"}},
+	}
+	for _, tt := range tests {
+		press(m, runes("s")...)
+		if m.order != tt.order {
+			t.Fatalf("expected sort order %v, got %v", tt.order, m.order)
+		}
+		if got := visibleTitles(m); !slices.Equal(got, tt.want) {
+			t.Errorf("sorted by %s: expected %v, got %v", sortNames[tt.order], tt.want, got)
+		}
+	}
+}
+
+func TestModelSearch(t *testing.T) {
+	m := newModel(testCandidates(), Options{})
+	press(m, key{code: keyDown}, key{code: keyDown}) // Ronin
+
+	press(m, runes("/ron")...)
+	if m.state != stateSearching {
+		t.Fatalf("expected to be searching, got state %v", m.state)
+	}
+	if got := visibleTitles(m); !slices.Equal(got, []string{"Ronin"}) {
+		t.Errorf("expected only Ronin to match, got %v", got)
+	}
+
+	// Letters are typed into the search rather than acting as commands
+	press(m, key{code: keyBackspace}, key{code: keyBackspace}, key{code: keyBackspace})
+	press(m, runes("a")...)
+	if got := visibleTitles(m); !slices.Equal(got, []string{"Heat", "Alien", "Cats"}) {
+		t.Errorf("expected the titles containing an a, got %v", got)
+	}
+	if len(m.selected()) != 4 {
+		t.Error("expected typing in the search not to change the selection")
+	}
+
+	press(m, key{code: keyEnter}, key{code: keyRune, r: ' '})
+	if got := selectedTitles(m); !slices.Equal(got, []string{"Alien", "Ronin", "Cats"}) {
+		t.Errorf("expected toggling to work on the search results, got %v", got)
+	}
+
+	press(m, runes("/")...)
+	press(m, key{code: keyEscape})
+	if m.state != stateBrowsing || len(m.visible) != 4 {
+		t.Errorf("expected escape to clear the search, got state %v with %v", m.state, visibleTitles(m))
+	}
+}
+
+func TestModelProtect(t *testing.T) {
+	var tagged []string
+	protect := func(movie arrbiter_radarr.MovieInfo) error {
+		if movie.Title == "Alien" {
+			return errors.New("radarr is down")
+		}
+		tagged = append(tagged, movie.Title)
+		return nil
+	}
+
+	m := newModel(testCandidates(), Options{Protect: protect, ProtectTag: "keep"})
+	press(m, runes("p")...)
+	if !slices.Equal(tagged, []string{"Heat"}) {
+		t.Fatalf("expected Heat to be tagged, got %v", tagged)
+	}
+	if !strings.Contains(m.message, "Tagged Heat (2000) with 'keep'") {
+		t.Errorf("unexpected message %q", m.message)
+	}
+
+	// Protected movies can't be selected again
+	press(m, runes(" a")...)
+	if got := selectedTitles(m); slices.Contains(got, "Heat") {
+		t.Errorf("expected Heat to stay deselected, got %v", got)
+	}
+
+	press(m, key{code: keyDown}, key{code: keyRune, r: 'p'})
+	if !strings.Contains(m.message, "radarr is down") || !slices.Contains(selectedTitles(m), "Alien") {
+		t.Errorf("expected a failed protect to leave Alien selected, got %q", m.message)
+	}
+
+	m = newModel(testCandidates(), Options{ProtectTag: "keep"})
+	press(m, runes("p")...)
+	if len(m.selected()) != 4 || !strings.Contains(m.message, "dry-run") {
+		t.Errorf("expected protecting to be unavailable in dry-run mode, got %q", m.message)
+	}
+
+	m = newModel(testCandidates(), Options{})
+	press(m, runes("p")...)
+	if len(m.selected()) != 4 || !strings.Contains(m.message, "review.protect_tag") {
+		t.Errorf("expected protecting to need a protect tag, got %q", m.message)
+	}
+}
+
+func TestModelApplyAndCancel(t *testing.T) {
+	m := newModel(testCandidates(), Options{})
+	press(m, key{code: keyEnter}, key{code: keyRune, r: 'n'})
+	if m.state != stateBrowsing {
+		t.Errorf("expected anything but y to go back to the list, got state %v", m.state)
+	}
+	press(m, key{code: keyEnter}, key{code: keyRune, r: 'y'})
+	if m.state != stateApplied {
+		t.Errorf("expected y to apply, got state %v", m.state)
+	}
+
+	for _, k := range []key{{code: keyRune, r: 'q'}, {code: keyEscape}, {code: keyInterrupt}} {
+		m := newModel(testCandidates(), Options{})
+		press(m, k)
+		if m.state != stateCancelled {
+			t.Errorf("expected %+v to cancel, got state %v", k, m.state)
+		}
+	}
+}
+
+func TestModelView(t *testing.T) {
+	m := newModel(testCandidates(), Options{ProtectTag: "keep"})
+	press(m, runes("s")...)
+
+	lines := m.view(100, 12)
+	if len(lines) != 12 {
+		t.Fatalf("expected a line per terminal row, got %d", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"4 of 4 selected (80.0 GB)",
+		"sorted by size",
+		"── old → delete (3 of 3 selected)",
+		"── bad → delete (1 of 1 selected)",
+		"[x] Heat (2000)",
+		"40.0 GB  2020-01-01  unwatched",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected the screen to contain %q:\n%s", want, screen)
+		}
+	}
+
+	// Only the list scrolls to keep the cursor in view
+	press(m, key{code: keyEnd})
+	lines = m.view(100, 7)
+	if len(lines) != 7 || !strings.Contains(strings.Join(lines, "\n"), "Cats") {
+		t.Errorf("expected the last movie to be scrolled into view:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1b[6~\x1bOF\r\x7f\x03é\x1b[1;5C\x1b"))
+	want := []key{
+		{code: keyRune, r: 'j'},
+		{code: keyUp},
+		{code: keyPageDown},
+		{code: keyEnd},
+		{code: keyEnter},
+		{code: keyBackspace},
+		{code: keyInterrupt},
+		{code: keyRune, r: 'é'},
+		{code: keyEscape},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %+v but got %+v", want, got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package review shows delete candidates full screen in the terminal so they
+// can be picked one by one before any action is applied.
+package review
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// ErrCancelled is returned by Run when the review is left without applying
+var ErrCancelled = errors.New("review cancelled")
+
+// Candidate is a movie a filter is about to act on
+type Candidate struct {
+	Movie  radarr.MovieInfo
+	Filter string
+	Action string // What the filter will do, e.g. "delete"
+}
+
+// Options configure a review
+type Options struct {
+	// Protect tags a movie so protection rules skip it from now on. Nil
+	// disables protecting, e.g. in dry-run mode or without a ProtectTag.
+	Protect    func(radarr.MovieInfo) error
+	ProtectTag string
+}
+
+// Run lets the user review the candidates, all selected to begin with, and
+// returns the ones still selected when they apply. Candidates are grouped by
+// filter in the order the filters first appear.
+func Run(candidates []Candidate, opts Options) ([]Candidate, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return nil, errors.New("reviewing needs an interactive terminal")
+	}
+
+	term, err := openTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	defer term.close()
+
+	m := newModel(candidates, opts)
+	buf := make([]byte, 256)
+	for m.state != stateApplied && m.state != stateCancelled {
+		width, height := term.size()
+		term.draw(m.view(width, height))
+
+		n, err := term.in.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from terminal: %w", err)
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			m.handle(k, listHeight(height))
+		}
+	}
+
+	if m.state == stateCancelled {
+		return nil, ErrCancelled
+	}
+	return m.selected(), nil
+}
+
+// terminal is a terminal switched to raw mode and the alternate screen
+type terminal struct {
+	in, out *os.File
+	state   rawState
+}
+
+func openTerminal(in, out *os.File) (*terminal, error) {
+	state, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+
+	// Switch to the alternate screen and hide the cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	return &terminal{in: in, out: out, state: state}, nil
+}
+
+// close restores the screen and terminal mode as they were
+func (t *terminal) close() {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	restore(int(t.in.Fd()), t.state)
+}
+
+// size returns the terminal's width and height, 80x24 when unknown
+func (t *terminal) size() (int, int) {
+	width, height, err := terminalSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// draw replaces the screen with lines. In raw mode a newline doesn't return
+// the cursor to the start of the line, so every line ends in \r\n.
+func (t *terminal) draw(lines []string) {
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	for i, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\x1b[K")
+		if i < len(lines)-1 {
+			sb.WriteString("\r\n")
+		}
+	}
+	sb.WriteString("\x1b[J")
+	fmt.Fprint(t.out, sb.String())
+}
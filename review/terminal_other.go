@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package review
+
+import "fmt"
+
+// rawState is the terminal mode to restore
+type rawState struct{}
+
+// makeRaw returns an error as raw terminal mode is not supported on this platform
+func makeRaw(fd int) (rawState, error) {
+	return rawState{}, fmt.Errorf("reviewing is not supported on this platform")
+}
+
+func restore(fd int, state rawState) error {
+	return nil
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, fmt.Errorf("terminal size not supported on this platform")
+}
@@ -0,0 +1,45 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package review
+
+import "golang.org/x/sys/unix"
+
+// rawState is the terminal mode to restore
+type rawState struct {
+	termios unix.Termios
+}
+
+// makeRaw puts the terminal in raw mode, so key presses arrive one at a time
+// without echo, and returns the mode it was in
+func makeRaw(fd int) (rawState, error) {
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return rawState{}, err
+	}
+	state := rawState{termios: *termios}
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+		return rawState{}, err
+	}
+
+	return state, nil
+}
+
+func restore(fd int, state rawState) error {
+	return unix.IoctlSetTermios(fd, ioctlSetTermios, &state.termios)
+}
+
+func terminalSize(fd int) (int, int, error) {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
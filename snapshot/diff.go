@@ -0,0 +1,205 @@
+package snapshot
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	starr_radarr "golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// Diff is what changed in a library between two snapshots
+type Diff struct {
+	Added          []radarr.MovieInfo
+	Removed        []radarr.MovieInfo
+	NewlyWatched   []WatchChange
+	NewlyRequested []radarr.MovieInfo
+	FileChanged    []FileChange
+	Filters        map[string]FilterChange // By filter name, only filters whose matches changed
+}
+
+// WatchChange is a movie that users watched since the old snapshot
+type WatchChange struct {
+	Movie radarr.MovieInfo
+	Users []string // Users who had not watched it before, sorted
+}
+
+// FileChange is a movie whose file changed quality or custom formats. An
+// empty quality means the movie had no file.
+type FileChange struct {
+	Movie          radarr.MovieInfo
+	OldQuality     string
+	NewQuality     string
+	AddedFormats   []string
+	RemovedFormats []string
+}
+
+// FilterChange is how the matches of one filter changed
+type FilterChange struct {
+	Matching    []radarr.MovieInfo // Newly matching
+	NotMatching []radarr.MovieInfo // No longer matching
+}
+
+// Empty reports whether nothing changed
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.NewlyWatched) == 0 &&
+		len(d.NewlyRequested) == 0 && len(d.FileChanged) == 0 && len(d.Filters) == 0
+}
+
+// Compare lists the movies added to and removed from a library, and the
+// movies watched, requested or given a different file since the old snapshot
+func Compare(previous, current []radarr.MovieInfo) Diff {
+	var diff Diff
+
+	oldMovies := make(map[radarr.MovieKey]radarr.MovieInfo, len(previous))
+	for _, movie := range previous {
+		oldMovies[movie.Key()] = movie
+	}
+
+	for _, movie := range current {
+		before, ok := oldMovies[movie.Key()]
+		if !ok {
+			diff.Added = append(diff.Added, movie)
+			continue
+		}
+		delete(oldMovies, movie.Key())
+
+		if users := newWatchers(before, movie); len(users) > 0 || (!before.Watched && movie.Watched) {
+			diff.NewlyWatched = append(diff.NewlyWatched, WatchChange{Movie: movie, Users: users})
+		}
+		if !before.IsRequested && movie.IsRequested {
+			diff.NewlyRequested = append(diff.NewlyRequested, movie)
+		}
+		if change, ok := compareFiles(before, movie); ok {
+			diff.FileChanged = append(diff.FileChanged, change)
+		}
+	}
+	diff.Removed = slices.Collect(maps.Values(oldMovies))
+
+	sortMovies(diff.Added)
+	sortMovies(diff.Removed)
+	sortMovies(diff.NewlyRequested)
+	slices.SortFunc(diff.NewlyWatched, func(a, b WatchChange) int { return compareMovies(a.Movie, b.Movie) })
+	slices.SortFunc(diff.FileChanged, func(a, b FileChange) int { return compareMovies(a.Movie, b.Movie) })
+
+	return diff
+}
+
+// CompareMatches lists, for each filter, the movies it matches now but did
+// not before and the other way around. Filters whose matches did not change
+// are left out.
+func CompareMatches(previous, current map[string][]radarr.MovieInfo) map[string]FilterChange {
+	changes := make(map[string]FilterChange)
+
+	for name, movies := range current {
+		change := FilterChange{
+			Matching:    missingFrom(movies, previous[name]),
+			NotMatching: missingFrom(previous[name], movies),
+		}
+		if len(change.Matching) > 0 || len(change.NotMatching) > 0 {
+			changes[name] = change
+		}
+	}
+	for name, movies := range previous {
+		if _, ok := current[name]; !ok && len(movies) > 0 {
+			changes[name] = FilterChange{NotMatching: missingFrom(movies, nil)}
+		}
+	}
+
+	return changes
+}
+
+// missingFrom returns the movies that are not in other, sorted
+func missingFrom(movies, other []radarr.MovieInfo) []radarr.MovieInfo {
+	keys := make(map[radarr.MovieKey]bool, len(other))
+	for _, movie := range other {
+		keys[movie.Key()] = true
+	}
+
+	var missing []radarr.MovieInfo
+	for _, movie := range movies {
+		if !keys[movie.Key()] {
+			missing = append(missing, movie)
+		}
+	}
+	sortMovies(missing)
+	return missing
+}
+
+// newWatchers returns the users who watched a movie since the old snapshot
+func newWatchers(before, after radarr.MovieInfo) []string {
+	var users []string
+	for username, userData := range after.UserWatchData {
+		if !userData.Watched {
+			continue
+		}
+		if previous, ok := before.UserWatchData[username]; ok && previous.Watched {
+			continue
+		}
+		users = append(users, username)
+	}
+	slices.Sort(users)
+	return users
+}
+
+// compareFiles reports how a movie's file changed, if it did
+func compareFiles(before, after radarr.MovieInfo) (FileChange, bool) {
+	oldQuality, oldFormats := fileQuality(before.MovieFile)
+	newQuality, newFormats := fileQuality(after.MovieFile)
+
+	change := FileChange{
+		Movie:          after,
+		OldQuality:     oldQuality,
+		NewQuality:     newQuality,
+		AddedFormats:   difference(newFormats, oldFormats),
+		RemovedFormats: difference(oldFormats, newFormats),
+	}
+	changed := oldQuality != newQuality || len(change.AddedFormats) > 0 || len(change.RemovedFormats) > 0
+	return change, changed
+}
+
+// fileQuality returns the quality name and custom formats of a movie file
+func fileQuality(file *starr_radarr.MovieFile) (string, []string) {
+	if file == nil {
+		return "", nil
+	}
+
+	quality := "Unknown"
+	if file.Quality != nil && file.Quality.Quality != nil {
+		quality = file.Quality.Quality.Name
+	}
+
+	var formats []string
+	for _, cf := range file.CustomFormats {
+		if cf != nil && cf.Name != "" {
+			formats = append(formats, cf.Name)
+		}
+	}
+	return quality, formats
+}
+
+// difference returns the names in a that are not in b, sorted
+func difference(a, b []string) []string {
+	var result []string
+	for _, name := range a {
+		if !slices.Contains(b, name) {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func sortMovies(movies []radarr.MovieInfo) {
+	slices.SortFunc(movies, compareMovies)
+}
+
+func compareMovies(a, b radarr.MovieInfo) int {
+	return cmp.Or(
+		cmp.Compare(a.Title, b.Title),
+		cmp.Compare(a.Year, b.Year),
+		cmp.Compare(a.Instance, b.Instance),
+	)
+}
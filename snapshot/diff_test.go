@@ -0,0 +1,106 @@
+package snapshot
+
+import (
+	"slices"
+	"testing"
+
+	"golift.io/starr"
+	starr_radarr "golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+func movieFile(quality string, formats ...string) *starr_radarr.MovieFile {
+	file := &starr_radarr.MovieFile{
+		Quality: &starr.Quality{Quality: &starr.BaseQuality{Name: quality}},
+	}
+	for _, name := range formats {
+		file.CustomFormats = append(file.CustomFormats, &starr_radarr.CustomFormatOutput{Name: name})
+	}
+	return file
+}
+
+func titles(movies []radarr.MovieInfo) []string {
+	var result []string
+	for _, movie := range movies {
+		result = append(result, movie.Title)
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	previous := []radarr.MovieInfo{
+		{ID: 1, Title: "Heat", MovieFile: movieFile("Bluray-1080p", "x264"),
+			UserWatchData: map[string]*radarr.UserWatchInfo{"alice": {Watched: true}}},
+		{ID: 2, Title: "Alien"},
+		{ID: 3, Title: "Ronin", MovieFile: movieFile("Bluray-1080p")},
+	}
+	current := []radarr.MovieInfo{
+		{ID: 1, Title: "Heat", Watched: true, MovieFile: movieFile("Remux-2160p", "DV"),
+			UserWatchData: map[string]*radarr.UserWatchInfo{"alice": {Watched: true}, "bob": {Watched: true}}},
+		{ID: 3, Title: "Ronin", IsRequested: true, RequestedBy: "carol", MovieFile: movieFile("Bluray-1080p")},
+		{ID: 4, Title: "Collateral"},
+		{ID: 1, Instance: "4k", Title: "Heat"},
+	}
+
+	diff := Compare(previous, current)
+
+	if got := titles(diff.Added); !slices.Equal(got, []string{"Collateral", "Heat"}) {
+		t.Errorf("unexpected added movies %v", got)
+	}
+	if got := titles(diff.Removed); !slices.Equal(got, []string{"Alien"}) {
+		t.Errorf("unexpected removed movies %v", got)
+	}
+	if got := titles(diff.NewlyRequested); !slices.Equal(got, []string{"Ronin"}) {
+		t.Errorf("unexpected newly requested movies %v", got)
+	}
+
+	if len(diff.NewlyWatched) != 1 || !slices.Equal(diff.NewlyWatched[0].Users, []string{"bob"}) {
+		t.Errorf("expected bob to have newly watched Heat, got %+v", diff.NewlyWatched)
+	}
+
+	if len(diff.FileChanged) != 1 {
+		t.Fatalf("expected one file change but got %+v", diff.FileChanged)
+	}
+	change := diff.FileChanged[0]
+	if change.OldQuality != "Bluray-1080p" || change.NewQuality != "Remux-2160p" ||
+		!slices.Equal(change.AddedFormats, []string{"DV"}) || !slices.Equal(change.RemovedFormats, []string{"x264"}) {
+		t.Errorf("unexpected file change %+v", change)
+	}
+
+	if diff.Empty() {
+		t.Error("expected the diff not to be empty")
+	}
+	if !Compare(current, current).Empty() {
+		t.Error("expected comparing a library with itself to be empty")
+	}
+}
+
+func TestCompareMatches(t *testing.T) {
+	heat := radarr.MovieInfo{ID: 1, Title: "Heat"}
+	alien := radarr.MovieInfo{ID: 2, Title: "Alien"}
+	ronin := radarr.MovieInfo{ID: 3, Title: "Ronin"}
+
+	changes := CompareMatches(
+		map[string][]radarr.MovieInfo{
+			"unwatched": {heat, alien},
+			"stable":    {ronin},
+			"removed":   {ronin},
+		},
+		map[string][]radarr.MovieInfo{
+			"unwatched": {alien, ronin},
+			"stable":    {ronin},
+		},
+	)
+
+	if len(changes) != 2 {
+		t.Fatalf("expected changes for two filters but got %+v", changes)
+	}
+	unwatched := changes["unwatched"]
+	if !slices.Equal(titles(unwatched.Matching), []string{"Ronin"}) || !slices.Equal(titles(unwatched.NotMatching), []string{"Heat"}) {
+		t.Errorf("unexpected changes for unwatched: %+v", unwatched)
+	}
+	if !slices.Equal(titles(changes["removed"].NotMatching), []string{"Ronin"}) {
+		t.Errorf("expected a filter that is gone to no longer match anything, got %+v", changes["removed"])
+	}
+}
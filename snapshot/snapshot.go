@@ -0,0 +1,76 @@
+// Package snapshot saves a fully enriched library to a file, so filters can be
+// evaluated offline and a library can be shared in a bug report.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/s0up4200/arrbiter/identity"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// Version is the snapshot format written by Save. Load refuses newer formats.
+const Version = 1
+
+// ErrUnsupportedVersion is returned when a snapshot was written in a format
+// this build does not understand
+var ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// Snapshot is a library as arrbiter saw it, after every enricher ran
+type Snapshot struct {
+	Version   int                `json:"version"`
+	CreatedAt time.Time          `json:"created_at"`
+	Arrbiter  string             `json:"arrbiter,omitempty"` // Version of arrbiter that wrote it
+	Instances []string           `json:"instances"`          // Radarr instance names in config order
+	Accounts  []identity.Account `json:"accounts,omitempty"` // Overseerr and Tautulli users, for linking names
+	Movies    []radarr.MovieInfo `json:"movies"`
+}
+
+// Save writes a snapshot to path atomically, stamping it with the current format version
+func Save(path string, snapshot Snapshot) error {
+	snapshot.Version = Version
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("unable to create snapshot directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the snapshot at path
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Version < 1 || snapshot.Version > Version {
+		return nil, fmt.Errorf("%w %d (this build reads up to %d)", ErrUnsupportedVersion, snapshot.Version, Version)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	starr_radarr "golift.io/starr/radarr"
+
+	"github.com/s0up4200/arrbiter/identity"
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.json")
+	lastWatched := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	err := Save(path, Snapshot{
+		CreatedAt: time.Now(),
+		Instances: []string{"default"},
+		Accounts:  []identity.Account{{Names: []string{"alice", "Alice Smith"}, PlexID: 1}},
+		Movies: []radarr.MovieInfo{{
+			ID:          1,
+			Instance:    "default",
+			Title:       "Heat",
+			Year:        1995,
+			MovieFile:   &starr_radarr.MovieFile{Path: "/movies/Heat (1995)/Heat.mkv", Size: 1 << 30},
+			Watched:     true,
+			LastWatched: lastWatched,
+			UserWatchData: map[string]*radarr.UserWatchInfo{
+				"alice": {Username: "alice", Watched: true, WatchCount: 1, MaxProgress: 100},
+			},
+			RequestedBy:   "Alice Smith",
+			IsRequested:   true,
+			HardlinkCount: 2,
+			IsHardlinked:  true,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if loaded.Version != Version {
+		t.Errorf("expected version %d but got %d", Version, loaded.Version)
+	}
+	if len(loaded.Accounts) != 1 || loaded.Accounts[0].PlexID != 1 {
+		t.Errorf("expected the account to round-trip, got %+v", loaded.Accounts)
+	}
+	if len(loaded.Movies) != 1 {
+		t.Fatalf("expected 1 movie but got %d", len(loaded.Movies))
+	}
+
+	movie := loaded.Movies[0]
+	if movie.Title != "Heat" || movie.MovieFile == nil || movie.MovieFile.Size != 1<<30 {
+		t.Errorf("movie did not round-trip: %+v", movie)
+	}
+	if !movie.LastWatched.Equal(lastWatched) || movie.UserWatchData["alice"].MaxProgress != 100 {
+		t.Errorf("watch data did not round-trip: %+v", movie)
+	}
+	if !movie.IsHardlinked || movie.HardlinkCount != 2 {
+		t.Errorf("hardlink data did not round-trip: %+v", movie)
+	}
+}
+
+func TestLoadRejectsUnknownVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"newer.json":   `{"version": 99, "movies": []}`,
+		"missing.json": `{"movies": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("%s: expected ErrUnsupportedVersion but got %v", name, err)
+		}
+	}
+}
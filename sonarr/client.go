@@ -0,0 +1,374 @@
+package sonarr
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golift.io/starr"
+	"golift.io/starr/sonarr"
+)
+
+// Client wraps the starr Sonarr client with additional functionality
+type Client struct {
+	api    SonarrAPI
+	logger zerolog.Logger
+
+	// Cache for frequently accessed data
+	tagCache      []*starr.Tag
+	tagCacheMutex sync.RWMutex
+	tagCacheTime  time.Time
+	cacheTTL      time.Duration
+}
+
+// NewClient creates a new Sonarr client
+func NewClient(url, apiKey string, logger zerolog.Logger) (*Client, error) {
+	config := starr.New(apiKey, url, 30*time.Second)
+	sonarrClient := sonarr.New(config)
+
+	// Test the connection
+	if err := sonarrClient.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Sonarr: %w", err)
+	}
+
+	return &Client{
+		api:      sonarrClient,
+		logger:   logger,
+		cacheTTL: 5 * time.Minute,
+	}, nil
+}
+
+// NewClientWithAPI creates a new client with a custom API implementation (for testing)
+func NewClientWithAPI(api SonarrAPI, logger zerolog.Logger) *Client {
+	return &Client{
+		api:      api,
+		logger:   logger,
+		cacheTTL: 5 * time.Minute,
+	}
+}
+
+// GetAllSeries retrieves all series from Sonarr
+func (c *Client) GetAllSeries(ctx context.Context) ([]*sonarr.Series, error) {
+	series, err := c.api.GetAllSeriesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	c.logger.Debug().Msgf("Retrieved %d series from Sonarr", len(series))
+	return series, nil
+}
+
+// GetSeriesByID retrieves a single series by its ID
+func (c *Client) GetSeriesByID(ctx context.Context, seriesID int64) (*sonarr.Series, error) {
+	series, err := c.api.GetSeriesByIDContext(ctx, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series ID %d: %w", seriesID, err)
+	}
+	return series, nil
+}
+
+// UpdateSeries updates a series in Sonarr (for monitoring status, tags, etc)
+func (c *Client) UpdateSeries(ctx context.Context, series *sonarr.Series) (*sonarr.Series, error) {
+	updated, err := c.api.UpdateSeriesContext(ctx, seriesToInput(series), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update series ID %d: %w", series.ID, err)
+	}
+
+	c.logger.Info().Int64("series_id", series.ID).Str("title", series.Title).
+		Bool("monitored", series.Monitored).
+		Msg("Successfully updated series")
+	return updated, nil
+}
+
+// seriesToInput converts a series into the input Sonarr expects when updating it
+func seriesToInput(series *sonarr.Series) *sonarr.AddSeriesInput {
+	return &sonarr.AddSeriesInput{
+		Monitored:         series.Monitored,
+		SeasonFolder:      series.SeasonFolder,
+		UseSceneNumbering: series.UseSceneNumbering,
+		ID:                series.ID,
+		LanguageProfileID: series.LanguageProfileID,
+		QualityProfileID:  series.QualityProfileID,
+		TvdbID:            series.TvdbID,
+		ImdbID:            series.ImdbID,
+		TvMazeID:          series.TvMazeID,
+		TvRageID:          series.TvRageID,
+		Path:              series.Path,
+		SeriesType:        series.SeriesType,
+		Title:             series.Title,
+		TitleSlug:         series.TitleSlug,
+		RootFolderPath:    series.RootFolderPath,
+		Tags:              series.Tags,
+		Seasons:           series.Seasons,
+		Images:            series.Images,
+	}
+}
+
+// DeleteSeries deletes a series from Sonarr, optionally with its files and an import exclusion
+func (c *Client) DeleteSeries(ctx context.Context, seriesID int64, deleteFiles, addImportExclusion bool) error {
+	err := c.api.DeleteSeriesContext(ctx, int(seriesID), deleteFiles, addImportExclusion)
+	if err != nil {
+		return fmt.Errorf("failed to delete series ID %d: %w", seriesID, err)
+	}
+
+	c.logger.Info().Int64("series_id", seriesID).
+		Bool("delete_files", deleteFiles).
+		Bool("import_exclusion", addImportExclusion).
+		Msg("Successfully deleted series")
+	return nil
+}
+
+// DeleteSeasonFiles deletes every episode file of one season, keeping the series.
+// It returns the number of files deleted.
+func (c *Client) DeleteSeasonFiles(ctx context.Context, seriesID int64, seasonNumber int) (int, error) {
+	files, err := c.api.GetSeriesEpisodeFilesContext(ctx, seriesID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get episode files of series ID %d: %w", seriesID, err)
+	}
+
+	var deleted int
+	for _, file := range files {
+		if file.SeasonNumber != seasonNumber {
+			continue
+		}
+		if err := c.api.DeleteEpisodeFileContext(ctx, file.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete episode file %s: %w", file.RelativePath, err)
+		}
+		deleted++
+	}
+
+	c.logger.Info().Int64("series_id", seriesID).Int("season", seasonNumber).
+		Int("files", deleted).
+		Msg("Successfully deleted season files")
+	return deleted, nil
+}
+
+// GetTags retrieves all tags from Sonarr with caching
+func (c *Client) GetTags(ctx context.Context) ([]*starr.Tag, error) {
+	// Check cache first
+	c.tagCacheMutex.RLock()
+	if c.tagCache != nil && time.Since(c.tagCacheTime) < c.cacheTTL {
+		tags := c.tagCache
+		c.tagCacheMutex.RUnlock()
+		return tags, nil
+	}
+	c.tagCacheMutex.RUnlock()
+
+	// Fetch from API
+	tags, err := c.api.GetTagsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	// Update cache
+	c.tagCacheMutex.Lock()
+	c.tagCache = tags
+	c.tagCacheTime = time.Now()
+	c.tagCacheMutex.Unlock()
+
+	c.logger.Debug().Msgf("Retrieved %d tags from Sonarr", len(tags))
+	return tags, nil
+}
+
+// GetOrCreateTag finds a tag by its label, creating it if it doesn't exist yet
+func (c *Client) GetOrCreateTag(ctx context.Context, tagName string) (*starr.Tag, error) {
+	tags, err := c.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if tag.Label == tagName {
+			return tag, nil
+		}
+	}
+
+	tag, err := c.api.AddTagContext(ctx, &starr.Tag{Label: tagName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %s: %w", tagName, err)
+	}
+
+	// Invalidate the cache so the new tag is picked up
+	c.tagCacheMutex.Lock()
+	c.tagCache = nil
+	c.tagCacheMutex.Unlock()
+
+	c.logger.Info().Str("tag", tagName).Int("tag_id", tag.ID).Msg("Created tag")
+	return tag, nil
+}
+
+// GetQualityProfiles retrieves all quality profiles from Sonarr
+func (c *Client) GetQualityProfiles(ctx context.Context) ([]*sonarr.QualityProfile, error) {
+	profiles, err := c.api.GetQualityProfilesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// GetQualityProfileByName finds a quality profile by its name
+func (c *Client) GetQualityProfileByName(ctx context.Context, name string) (*sonarr.QualityProfile, error) {
+	profiles, err := c.GetQualityProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+
+	return nil, fmt.Errorf("quality profile not found: %s", name)
+}
+
+// SeriesInfo contains relevant series information for filtering and display
+type SeriesInfo struct {
+	ID               int64
+	Title            string
+	Year             int
+	TVDBID           int64
+	IMDBID           string
+	Path             string
+	RootFolder       string
+	Tags             []int
+	TagNames         []string
+	Monitored        bool
+	QualityProfileID int64
+	QualityProfile   string // Name of the quality profile, resolved by GetAllSeries
+	Added            time.Time
+	// Sonarr metadata
+	Status         string // "continuing", "ended", "upcoming"
+	Ended          bool
+	Network        string
+	SeriesType     string // "standard", "daily", "anime"
+	Genres         []string
+	Certification  string
+	Runtime        int // Minutes per episode
+	Rating         float64
+	FirstAired     time.Time
+	PreviousAiring time.Time
+	NextAiring     time.Time
+	// File statistics
+	SizeBytes         int64
+	EpisodeCount      int // Monitored or downloaded episodes that have aired
+	EpisodeFileCount  int
+	TotalEpisodeCount int
+	Seasons           []SeasonInfo
+	// Watch status fields (aggregate across all users)
+	Watched         bool // Every episode on disk has been watched
+	WatchCount      int
+	LastWatched     time.Time
+	EpisodesWatched int
+	UserWatchData   map[string]*UserWatchInfo
+	// Request data from Overseerr
+	RequestedBy      string
+	RequestedByEmail string
+	RequestDate      time.Time
+	RequestStatus    string
+	ApprovedBy       string
+	IsAutoRequest    bool
+	IsRequested      bool
+}
+
+// SeasonInfo contains the files and watch status of one season
+type SeasonInfo struct {
+	Number            int
+	Monitored         bool
+	SizeBytes         int64
+	EpisodeCount      int
+	EpisodeFileCount  int
+	TotalEpisodeCount int
+	PreviousAiring    time.Time
+	// Watch status fields (aggregate across all users)
+	Watched         bool // Every episode on disk has been watched
+	WatchCount      int
+	LastWatched     time.Time
+	EpisodesWatched int
+	UserWatchData   map[string]*UserWatchInfo
+	// Whether an Overseerr request for the series included this season
+	IsRequested bool
+}
+
+// UserWatchInfo contains episode watch information for a specific user
+type UserWatchInfo struct {
+	Username        string
+	Watched         bool // Every episode on disk has been watched by this user
+	WatchCount      int
+	LastWatched     time.Time
+	EpisodesWatched int
+}
+
+// GetSeriesInfo converts a Sonarr series to our SeriesInfo struct
+func (c *Client) GetSeriesInfo(series *sonarr.Series, tags []*starr.Tag) SeriesInfo {
+	info := SeriesInfo{
+		ID:               series.ID,
+		Title:            series.Title,
+		Year:             series.Year,
+		TVDBID:           series.TvdbID,
+		IMDBID:           series.ImdbID,
+		Path:             series.Path,
+		Tags:             series.Tags,
+		TagNames:         make([]string, 0),
+		Monitored:        series.Monitored,
+		QualityProfileID: series.QualityProfileID,
+		Added:            series.Added,
+		Status:           series.Status,
+		Ended:            series.Ended,
+		Network:          series.Network,
+		SeriesType:       series.SeriesType,
+		Genres:           series.Genres,
+		Certification:    series.Certification,
+		Runtime:          series.Runtime,
+		FirstAired:       series.FirstAired,
+		PreviousAiring:   series.PreviousAiring,
+		NextAiring:       series.NextAiring,
+		UserWatchData:    make(map[string]*UserWatchInfo),
+	}
+
+	if series.Path != "" {
+		info.RootFolder = filepath.Dir(series.Path)
+	}
+	if series.Ratings != nil {
+		info.Rating = series.Ratings.Value
+	}
+	if stats := series.Statistics; stats != nil {
+		info.SizeBytes = stats.SizeOnDisk
+		info.EpisodeCount = stats.EpisodeCount
+		info.EpisodeFileCount = stats.EpisodeFileCount
+		info.TotalEpisodeCount = stats.TotalEpisodeCount
+	}
+
+	for _, season := range series.Seasons {
+		seasonInfo := SeasonInfo{
+			Number:        season.SeasonNumber,
+			Monitored:     season.Monitored,
+			UserWatchData: make(map[string]*UserWatchInfo),
+		}
+		if stats := season.Statistics; stats != nil {
+			seasonInfo.SizeBytes = stats.SizeOnDisk
+			seasonInfo.EpisodeCount = stats.EpisodeCount
+			seasonInfo.EpisodeFileCount = stats.EpisodeFileCount
+			seasonInfo.TotalEpisodeCount = stats.TotalEpisodeCount
+			seasonInfo.PreviousAiring = stats.PreviousAiring
+		}
+		info.Seasons = append(info.Seasons, seasonInfo)
+	}
+
+	// Map tag IDs to names
+	tagMap := make(map[int]string)
+	for _, tag := range tags {
+		tagMap[tag.ID] = tag.Label
+	}
+
+	for _, tagID := range series.Tags {
+		if tagName, ok := tagMap[tagID]; ok {
+			info.TagNames = append(info.TagNames, tagName)
+		}
+	}
+
+	return info
+}
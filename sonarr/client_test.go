@@ -0,0 +1,214 @@
+package sonarr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golift.io/starr"
+	"golift.io/starr/sonarr"
+)
+
+// mockSonarrAPI implements SonarrAPI for testing
+type mockSonarrAPI struct {
+	series          []*sonarr.Series
+	tags            []*starr.Tag
+	qualityProfiles []*sonarr.QualityProfile
+	episodeFiles    []*sonarr.EpisodeFile
+	updateErr       error
+
+	// Track calls for verification
+	deletedSeries []int
+	deletedFiles  []int64
+	updated       []*sonarr.AddSeriesInput
+}
+
+func (m *mockSonarrAPI) GetAllSeriesContext(ctx context.Context) ([]*sonarr.Series, error) {
+	return m.series, nil
+}
+
+func (m *mockSonarrAPI) GetSeriesByIDContext(ctx context.Context, seriesID int64) (*sonarr.Series, error) {
+	for _, series := range m.series {
+		if series.ID == seriesID {
+			return series, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockSonarrAPI) UpdateSeriesContext(ctx context.Context, series *sonarr.AddSeriesInput, moveFiles bool) (*sonarr.Series, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	m.updated = append(m.updated, series)
+	return &sonarr.Series{ID: series.ID, Title: series.Title}, nil
+}
+
+func (m *mockSonarrAPI) DeleteSeriesContext(ctx context.Context, seriesID int, deleteFiles bool, importExclude bool) error {
+	m.deletedSeries = append(m.deletedSeries, seriesID)
+	return nil
+}
+
+func (m *mockSonarrAPI) GetSeriesEpisodeFilesContext(ctx context.Context, seriesID int64) ([]*sonarr.EpisodeFile, error) {
+	var files []*sonarr.EpisodeFile
+	for _, file := range m.episodeFiles {
+		if file.SeriesID == seriesID {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func (m *mockSonarrAPI) DeleteEpisodeFileContext(ctx context.Context, episodeFileID int64) error {
+	m.deletedFiles = append(m.deletedFiles, episodeFileID)
+	return nil
+}
+
+func (m *mockSonarrAPI) GetTagsContext(ctx context.Context) ([]*starr.Tag, error) {
+	return m.tags, nil
+}
+
+func (m *mockSonarrAPI) AddTagContext(ctx context.Context, tag *starr.Tag) (*starr.Tag, error) {
+	created := &starr.Tag{ID: len(m.tags) + 1, Label: tag.Label}
+	m.tags = append(m.tags, created)
+	return created, nil
+}
+
+func (m *mockSonarrAPI) GetQualityProfilesContext(ctx context.Context) ([]*sonarr.QualityProfile, error) {
+	return m.qualityProfiles, nil
+}
+
+func (m *mockSonarrAPI) Ping() error {
+	return nil
+}
+
+func newTestSeries() []*sonarr.Series {
+	return []*sonarr.Series{
+		{
+			ID:               1,
+			Title:            "The Wire",
+			Year:             2002,
+			TvdbID:           79126,
+			Path:             "/tv/The Wire",
+			QualityProfileID: 4,
+			Tags:             []int{1},
+			Status:           "ended",
+			Ended:            true,
+			Network:          "HBO",
+			Added:            time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Ratings:          &starr.Ratings{Value: 9.3},
+			Statistics:       &sonarr.Statistics{SizeOnDisk: 30 << 30, EpisodeFileCount: 23, EpisodeCount: 23, TotalEpisodeCount: 60},
+			Seasons: []*sonarr.Season{
+				{SeasonNumber: 1, Monitored: true, Statistics: &sonarr.Statistics{SizeOnDisk: 18 << 30, EpisodeFileCount: 13, EpisodeCount: 13}},
+				{SeasonNumber: 2, Monitored: true, Statistics: &sonarr.Statistics{SizeOnDisk: 12 << 30, EpisodeFileCount: 10, EpisodeCount: 12}},
+			},
+		},
+		{
+			ID:         2,
+			Title:      "Nothing On Disk",
+			Statistics: &sonarr.Statistics{},
+		},
+	}
+}
+
+func TestGetAllSeries(t *testing.T) {
+	api := &mockSonarrAPI{
+		series:          newTestSeries(),
+		tags:            []*starr.Tag{{ID: 1, Label: "keep"}},
+		qualityProfiles: []*sonarr.QualityProfile{{ID: 4, Name: "HD-1080p"}},
+	}
+	ops := NewOperations(NewClientWithAPI(api, zerolog.Nop()), zerolog.Nop())
+
+	series, err := ops.GetAllSeries(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllSeries() error = %v", err)
+	}
+	if len(series) != 1 {
+		t.Fatalf("expected only the series with files, got %d", len(series))
+	}
+
+	info := series[0]
+	if info.Title != "The Wire" || info.TVDBID != 79126 || info.RootFolder != "/tv" {
+		t.Errorf("unexpected series info: %+v", info)
+	}
+	if info.QualityProfile != "HD-1080p" || len(info.TagNames) != 1 || info.TagNames[0] != "keep" {
+		t.Errorf("expected quality profile and tag names to be resolved, got %q and %v", info.QualityProfile, info.TagNames)
+	}
+	if info.Rating != 9.3 || info.SizeBytes != 30<<30 || info.EpisodeFileCount != 23 {
+		t.Errorf("unexpected series statistics: %+v", info)
+	}
+	if len(info.Seasons) != 2 || info.Seasons[1].Number != 2 || info.Seasons[1].SizeBytes != 12<<30 || info.Seasons[1].EpisodeFileCount != 10 {
+		t.Errorf("unexpected seasons: %+v", info.Seasons)
+	}
+}
+
+func TestDeleteSeasons(t *testing.T) {
+	api := &mockSonarrAPI{
+		series: newTestSeries(),
+		episodeFiles: []*sonarr.EpisodeFile{
+			{ID: 10, SeriesID: 1, SeasonNumber: 1},
+			{ID: 11, SeriesID: 1, SeasonNumber: 2},
+			{ID: 12, SeriesID: 1, SeasonNumber: 2},
+			{ID: 13, SeriesID: 2, SeasonNumber: 2},
+		},
+	}
+	ops := NewOperations(NewClientWithAPI(api, zerolog.Nop()), zerolog.Nop())
+
+	match := SeasonMatch{
+		Series: SeriesInfo{ID: 1, Title: "The Wire"},
+		Season: SeasonInfo{Number: 2},
+	}
+	if err := ops.DeleteSeasons(context.Background(), []SeasonMatch{match}); err != nil {
+		t.Fatalf("DeleteSeasons() error = %v", err)
+	}
+
+	if len(api.deletedFiles) != 2 || api.deletedFiles[0] != 11 || api.deletedFiles[1] != 12 {
+		t.Errorf("expected files 11 and 12 to be deleted, got %v", api.deletedFiles)
+	}
+	if len(api.deletedSeries) != 0 {
+		t.Errorf("expected the series to be kept, got deletions %v", api.deletedSeries)
+	}
+
+	// The season is unmonitored so Sonarr doesn't download it again
+	if len(api.updated) != 1 {
+		t.Fatalf("expected 1 series update, got %d", len(api.updated))
+	}
+	for _, season := range api.updated[0].Seasons {
+		if monitored := season.SeasonNumber != 2; season.Monitored != monitored {
+			t.Errorf("season %d: expected monitored = %v", season.SeasonNumber, monitored)
+		}
+	}
+
+	// Files are kept when the season can't be unmonitored, or Sonarr would grab it again
+	api.deletedFiles = nil
+	api.updateErr = errors.New("sonarr is down")
+	if err := ops.DeleteSeasons(context.Background(), []SeasonMatch{match}); err == nil {
+		t.Error("expected an error when unmonitoring fails")
+	}
+	if len(api.deletedFiles) != 0 {
+		t.Errorf("expected no files deleted, got %v", api.deletedFiles)
+	}
+}
+
+func TestSeriesActions(t *testing.T) {
+	api := &mockSonarrAPI{series: newTestSeries(), tags: []*starr.Tag{{ID: 1, Label: "keep"}}}
+	ops := NewOperations(NewClientWithAPI(api, zerolog.Nop()), zerolog.Nop())
+	ctx := context.Background()
+	series := []SeriesInfo{{ID: 1, Title: "The Wire"}}
+
+	if err := ops.TagSeries(ctx, series, "leaving-soon"); err != nil {
+		t.Fatalf("TagSeries() error = %v", err)
+	}
+	if len(api.tags) != 2 || len(api.updated) != 1 || len(api.updated[0].Tags) != 2 || api.updated[0].Tags[1] != 2 {
+		t.Errorf("expected the new tag to be created and added, got tags %v", api.updated[0].Tags)
+	}
+
+	if err := ops.DeleteSeries(ctx, series, DeleteOptions{}); err != nil {
+		t.Fatalf("DeleteSeries() error = %v", err)
+	}
+	if len(api.deletedSeries) != 1 || api.deletedSeries[0] != 1 {
+		t.Errorf("expected series 1 to be deleted, got %v", api.deletedSeries)
+	}
+}
@@ -0,0 +1,159 @@
+package sonarr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s0up4200/arrbiter/overseerr"
+	"github.com/s0up4200/arrbiter/tautulli"
+)
+
+// tautulliEnricher implements SeriesEnricher for Tautulli integration
+type tautulliEnricher struct {
+	operations *Operations
+}
+
+// EnrichSeries adds episode watch status from Tautulli, per series and per season
+func (e *tautulliEnricher) EnrichSeries(ctx context.Context, series []SeriesInfo) error {
+	if e.operations.tautulliClient == nil {
+		return nil
+	}
+
+	identifiers := make([]tautulli.ShowIdentifier, 0, len(series))
+	for _, s := range series {
+		identifiers = append(identifiers, tautulli.ShowIdentifier{
+			TVDbID: s.TVDBID,
+			Title:  s.Title,
+		})
+	}
+
+	statuses, err := e.operations.tautulliClient.BatchGetShowWatchStatus(
+		ctx, identifiers, e.operations.watchThreshold)
+	if err != nil {
+		return fmt.Errorf("failed to fetch episode watch status: %w", err)
+	}
+
+	for i := range series {
+		status, ok := statuses[series[i].TVDBID]
+		if !ok {
+			continue
+		}
+
+		s := &series[i]
+		s.WatchCount = status.WatchCount
+		s.LastWatched = status.LastWatched
+		s.EpisodesWatched = status.EpisodesWatched
+		s.Watched = allWatched(status.EpisodesWatched, s.EpisodeFileCount)
+		s.UserWatchData = userWatchData(status.UserData, s.EpisodeFileCount)
+
+		for j := range s.Seasons {
+			season := &s.Seasons[j]
+			seasonStatus, ok := status.Seasons[season.Number]
+			if !ok {
+				continue
+			}
+			season.WatchCount = seasonStatus.WatchCount
+			season.LastWatched = seasonStatus.LastWatched
+			season.EpisodesWatched = seasonStatus.EpisodesWatched
+			season.Watched = allWatched(seasonStatus.EpisodesWatched, season.EpisodeFileCount)
+			season.UserWatchData = userWatchData(seasonStatus.UserData, season.EpisodeFileCount)
+		}
+	}
+
+	return nil
+}
+
+// allWatched reports whether as many episodes were watched as there are files.
+// Nothing on disk counts as not watched.
+func allWatched(episodesWatched, episodeFileCount int) bool {
+	return episodeFileCount > 0 && episodesWatched >= episodeFileCount
+}
+
+// userWatchData converts Tautulli's per-user episode data
+func userWatchData(data map[string]*tautulli.UserEpisodeWatchData, episodeFileCount int) map[string]*UserWatchInfo {
+	result := make(map[string]*UserWatchInfo, len(data))
+	for username, userData := range data {
+		result[username] = &UserWatchInfo{
+			Username:        userData.Username,
+			Watched:         allWatched(userData.EpisodesWatched, episodeFileCount),
+			WatchCount:      userData.WatchCount,
+			LastWatched:     userData.LastWatched,
+			EpisodesWatched: userData.EpisodesWatched,
+		}
+	}
+	return result
+}
+
+// overseerrEnricher implements SeriesEnricher for Overseerr integration
+type overseerrEnricher struct {
+	operations *Operations
+}
+
+// EnrichSeries adds TV request information from Overseerr
+func (e *overseerrEnricher) EnrichSeries(ctx context.Context, series []SeriesInfo) error {
+	if e.operations.overseerrClient == nil {
+		return nil
+	}
+
+	requests, err := e.operations.overseerrClient.GetTVRequests(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch TV requests: %w", err)
+	}
+
+	// Series are matched on TVDB ID, which is what Sonarr knows them by
+	requestsByTVDB := make(map[int64][]overseerr.MediaRequest)
+	for _, req := range requests {
+		tvdbID := int64(req.Media.TvdbID)
+		requestsByTVDB[tvdbID] = append(requestsByTVDB[tvdbID], req)
+	}
+
+	for i := range series {
+		requests, ok := requestsByTVDB[series[i].TVDBID]
+		if !ok || len(requests) == 0 {
+			continue
+		}
+
+		// Every request counts towards which seasons were asked for, the
+		// most recent one decides who requested the series
+		requestedSeasons := make(map[int]bool)
+		var latestRequest overseerr.MediaRequest
+		for _, req := range requests {
+			for _, number := range req.SeasonNumbers() {
+				requestedSeasons[number] = true
+			}
+			if latestRequest.ID == 0 || req.CreatedAt.After(latestRequest.CreatedAt) {
+				latestRequest = req
+			}
+		}
+
+		requestData := latestRequest.ToMovieRequest()
+		series[i].RequestedBy = requestData.RequestedBy
+		series[i].RequestedByEmail = requestData.RequestedByEmail
+		series[i].RequestDate = requestData.RequestDate
+		series[i].RequestStatus = requestData.RequestStatus
+		series[i].ApprovedBy = requestData.ApprovedBy
+		series[i].IsAutoRequest = requestData.IsAutoRequest
+		series[i].IsRequested = true
+
+		for j := range series[i].Seasons {
+			series[i].Seasons[j].IsRequested = requestedSeasons[series[i].Seasons[j].Number]
+		}
+	}
+
+	e.operations.logger.Debug().
+		Int("total_requests", len(requests)).
+		Int("matched_series", len(requestsByTVDB)).
+		Msg("Enriched series with Overseerr request data")
+
+	return nil
+}
+
+// addEnricher adds an enricher to the operations if not already present
+func (o *Operations) addEnricher(enricher SeriesEnricher) {
+	for _, existing := range o.enrichers {
+		if fmt.Sprintf("%T", existing) == fmt.Sprintf("%T", enricher) {
+			return
+		}
+	}
+	o.enrichers = append(o.enrichers, enricher)
+}
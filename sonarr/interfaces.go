@@ -0,0 +1,36 @@
+package sonarr
+
+import (
+	"context"
+
+	"golift.io/starr"
+	"golift.io/starr/sonarr"
+)
+
+// SonarrAPI defines the interface for Sonarr API operations
+type SonarrAPI interface {
+	// Series operations
+	GetAllSeriesContext(ctx context.Context) ([]*sonarr.Series, error)
+	GetSeriesByIDContext(ctx context.Context, seriesID int64) (*sonarr.Series, error)
+	UpdateSeriesContext(ctx context.Context, series *sonarr.AddSeriesInput, moveFiles bool) (*sonarr.Series, error)
+	DeleteSeriesContext(ctx context.Context, seriesID int, deleteFiles bool, importExclude bool) error
+
+	// Episode file operations
+	GetSeriesEpisodeFilesContext(ctx context.Context, seriesID int64) ([]*sonarr.EpisodeFile, error)
+	DeleteEpisodeFileContext(ctx context.Context, episodeFileID int64) error
+
+	// Tag operations
+	GetTagsContext(ctx context.Context) ([]*starr.Tag, error)
+	AddTagContext(ctx context.Context, tag *starr.Tag) (*starr.Tag, error)
+
+	// Quality profile operations
+	GetQualityProfilesContext(ctx context.Context) ([]*sonarr.QualityProfile, error)
+
+	// Health check
+	Ping() error
+}
+
+// SeriesEnricher defines the interface for enriching series data
+type SeriesEnricher interface {
+	EnrichSeries(ctx context.Context, series []SeriesInfo) error
+}
@@ -0,0 +1,280 @@
+package sonarr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+	"golift.io/starr/sonarr"
+
+	"github.com/s0up4200/arrbiter/overseerr"
+	"github.com/s0up4200/arrbiter/tautulli"
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// DeleteOptions contains options for deleting series
+type DeleteOptions struct {
+	KeepFiles          bool // Leave episode files on disk and only remove the Sonarr entry
+	AddImportExclusion bool // Prevent lists from re-adding the series
+}
+
+// SeasonMatch is a single season of a series selected by a season-level filter
+type SeasonMatch struct {
+	Series SeriesInfo
+	Season SeasonInfo
+}
+
+// Operations handles series search, delete and update operations
+type Operations struct {
+	client          *Client
+	tautulliClient  *tautulli.Client
+	overseerrClient *overseerr.Client
+	logger          zerolog.Logger
+	watchThreshold  watch.Threshold
+	enrichers       []SeriesEnricher
+}
+
+// NewOperations creates a new Operations instance
+func NewOperations(client *Client, logger zerolog.Logger) *Operations {
+	return &Operations{
+		client:         client,
+		logger:         logger,
+		watchThreshold: watch.Threshold{MinPercent: watch.DefaultMinPercent},
+		enrichers:      make([]SeriesEnricher, 0),
+	}
+}
+
+// SetTautulliClient sets the Tautulli client for episode watch status lookups
+func (o *Operations) SetTautulliClient(client *tautulli.Client) {
+	o.tautulliClient = client
+	o.addEnricher(&tautulliEnricher{operations: o})
+}
+
+// SetMinWatchPercent sets the minimum watch percentage for considering an episode watched
+func (o *Operations) SetMinWatchPercent(percent float64) {
+	o.watchThreshold.MinPercent = percent
+}
+
+// SetWatchThreshold sets the watch threshold, including per-user overrides
+func (o *Operations) SetWatchThreshold(threshold watch.Threshold) {
+	o.watchThreshold = threshold
+}
+
+// SetOverseerrClient sets the Overseerr client for TV request lookups
+func (o *Operations) SetOverseerrClient(client *overseerr.Client) {
+	o.overseerrClient = client
+	o.addEnricher(&overseerrEnricher{operations: o})
+}
+
+// GetAllSeries returns all series that have episode files, with enriched data
+func (o *Operations) GetAllSeries(ctx context.Context) ([]SeriesInfo, error) {
+	series, err := o.client.GetAllSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := o.client.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only series with files on disk are worth cleaning up
+	var results []SeriesInfo
+	for _, s := range series {
+		info := o.client.GetSeriesInfo(s, tags)
+		if info.EpisodeFileCount > 0 {
+			results = append(results, info)
+		}
+	}
+
+	// Resolve quality profile names, which the series list only has IDs for
+	if profiles, err := o.client.GetQualityProfiles(ctx); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to get quality profiles, profile names will be empty")
+	} else {
+		names := make(map[int64]string, len(profiles))
+		for _, profile := range profiles {
+			names[profile.ID] = profile.Name
+		}
+		for i := range results {
+			results[i].QualityProfile = names[results[i].QualityProfileID]
+		}
+	}
+
+	// Enrich series from all configured sources concurrently
+	if len(results) > 0 && len(o.enrichers) > 0 {
+		g, gctx := errgroup.WithContext(ctx)
+		for _, enricher := range o.enrichers {
+			g.Go(func() error {
+				if err := enricher.EnrichSeries(gctx, results); err != nil {
+					o.logger.Warn().Err(err).Type("enricher", enricher).Msg("Failed to enrich series")
+				}
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			o.logger.Warn().Err(err).Msg("Failed to enrich series from all sources")
+		}
+	}
+
+	return results, nil
+}
+
+// DeleteSeries deletes whole series from Sonarr
+func (o *Operations) DeleteSeries(ctx context.Context, series []SeriesInfo, opts DeleteOptions) error {
+	var failed int
+	for _, s := range series {
+		if err := o.client.DeleteSeries(ctx, s.ID, !opts.KeepFiles, opts.AddImportExclusion); err != nil {
+			failed++
+			o.logger.Error().Err(err).Int64("id", s.ID).Str("title", s.Title).Msg("Failed to delete series")
+		}
+	}
+
+	o.logger.Info().
+		Int("deleted", len(series)-failed).
+		Int("failed", failed).
+		Msg("Series deletion complete")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d series", failed)
+	}
+	return nil
+}
+
+// DeleteSeasons unmonitors seasons and deletes their episode files. The series
+// themselves are kept.
+func (o *Operations) DeleteSeasons(ctx context.Context, seasons []SeasonMatch) error {
+	var failed int
+	for _, match := range seasons {
+		// Unmonitor first so Sonarr doesn't download the season again once the files are gone
+		if err := o.setSeasonMonitored(ctx, match, false); err != nil {
+			failed++
+			o.logger.Error().Err(err).Int64("id", match.Series.ID).Str("title", match.Series.Title).
+				Int("season", match.Season.Number).Msg("Failed to unmonitor season, skipping deletion")
+			continue
+		}
+		if _, err := o.client.DeleteSeasonFiles(ctx, match.Series.ID, match.Season.Number); err != nil {
+			failed++
+			o.logger.Error().Err(err).Int64("id", match.Series.ID).Str("title", match.Series.Title).
+				Int("season", match.Season.Number).Msg("Unmonitored season but failed to delete its files")
+		}
+	}
+
+	o.logger.Info().
+		Int("deleted", len(seasons)-failed).
+		Int("failed", failed).
+		Msg("Season deletion complete")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d seasons", failed)
+	}
+	return nil
+}
+
+// UnmonitorSeries marks series as unmonitored so Sonarr stops searching for episodes
+func (o *Operations) UnmonitorSeries(ctx context.Context, series []SeriesInfo) error {
+	return o.updateSeries(ctx, series, "unmonitor", func(s *sonarr.Series) {
+		s.Monitored = false
+	})
+}
+
+// UnmonitorSeasons marks single seasons as unmonitored
+func (o *Operations) UnmonitorSeasons(ctx context.Context, seasons []SeasonMatch) error {
+	var failed int
+	for _, match := range seasons {
+		if err := o.setSeasonMonitored(ctx, match, false); err != nil {
+			failed++
+			o.logger.Error().Err(err).Int64("id", match.Series.ID).Str("title", match.Series.Title).
+				Int("season", match.Season.Number).Msg("Failed to unmonitor season")
+		}
+	}
+
+	o.logger.Info().
+		Int("updated", len(seasons)-failed).
+		Int("failed", failed).
+		Msg("Finished: unmonitor seasons")
+
+	if failed > 0 {
+		return fmt.Errorf("failed to unmonitor %d seasons", failed)
+	}
+	return nil
+}
+
+// TagSeries adds a tag to series, creating the tag in Sonarr if needed
+func (o *Operations) TagSeries(ctx context.Context, series []SeriesInfo, tagName string) error {
+	tag, err := o.client.GetOrCreateTag(ctx, tagName)
+	if err != nil {
+		return err
+	}
+
+	return o.updateSeries(ctx, series, "tag", func(s *sonarr.Series) {
+		if !slices.Contains(s.Tags, tag.ID) {
+			s.Tags = append(s.Tags, tag.ID)
+		}
+	})
+}
+
+// ChangeQualityProfile moves series to the named quality profile
+func (o *Operations) ChangeQualityProfile(ctx context.Context, series []SeriesInfo, profileName string) error {
+	profile, err := o.client.GetQualityProfileByName(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	return o.updateSeries(ctx, series, "change quality profile of", func(s *sonarr.Series) {
+		s.QualityProfileID = profile.ID
+	})
+}
+
+// setSeasonMonitored changes the monitored flag of one season
+func (o *Operations) setSeasonMonitored(ctx context.Context, match SeasonMatch, monitored bool) error {
+	return o.updateOne(ctx, match.Series.ID, func(s *sonarr.Series) {
+		for _, season := range s.Seasons {
+			if season.SeasonNumber == match.Season.Number {
+				season.Monitored = monitored
+			}
+		}
+	})
+}
+
+// updateSeries fetches each series, applies the change and saves it back to Sonarr
+func (o *Operations) updateSeries(ctx context.Context, series []SeriesInfo, action string, apply func(*sonarr.Series)) error {
+	var failed int
+	for _, info := range series {
+		if err := o.updateOne(ctx, info.ID, apply); err != nil {
+			failed++
+			o.logger.Error().
+				Err(err).
+				Int64("id", info.ID).
+				Str("title", info.Title).
+				Msgf("Failed to %s series", action)
+		}
+	}
+
+	o.logger.Info().
+		Int("updated", len(series)-failed).
+		Int("failed", failed).
+		Msgf("Finished: %s", action)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d series", action, failed)
+	}
+
+	return nil
+}
+
+// updateOne applies a change to a single series
+func (o *Operations) updateOne(ctx context.Context, seriesID int64, apply func(*sonarr.Series)) error {
+	series, err := o.client.GetSeriesByID(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+	if series == nil {
+		return fmt.Errorf("series ID %d not found", seriesID)
+	}
+
+	apply(series)
+	_, err = o.client.UpdateSeries(ctx, series)
+	return err
+}
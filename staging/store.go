@@ -0,0 +1,160 @@
+package staging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+// Entry records when a movie was first marked as leaving soon
+type Entry struct {
+	MovieID  int64     `json:"movie_id"`
+	Instance string    `json:"instance,omitempty"` // Radarr instance the movie is in
+	Title    string    `json:"title"`
+	Year     int       `json:"year"`
+	StagedAt time.Time `json:"staged_at"`
+}
+
+// Key returns the identity of the staged movie
+func (e Entry) Key() radarr.MovieKey {
+	return radarr.MovieKey{Instance: e.Instance, ID: e.MovieID}
+}
+
+// Plan splits deletion candidates into the phases of the grace period
+type Plan struct {
+	Stage   []radarr.MovieInfo // Newly matched, tag them and start the clock
+	Waiting []radarr.MovieInfo // Staged but still inside the grace period
+	Ready   []radarr.MovieInfo // Staged for at least the grace period, delete now
+	Release []Entry            // Staged but no longer matching, remove the tag
+}
+
+// Store keeps track of staged movies in a JSON file
+type Store struct {
+	path    string
+	entries map[radarr.MovieKey]Entry
+}
+
+// Open loads the store at path. A missing file is treated as an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		entries: make(map[radarr.MovieKey]Entry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staging state: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse staging state: %w", err)
+	}
+	for _, entry := range entries {
+		s.entries[entry.Key()] = entry
+	}
+
+	return s, nil
+}
+
+// AssignInstance moves entries staged before Radarr instances were tracked
+// to the given instance
+func (s *Store) AssignInstance(instance string) {
+	for key, entry := range s.entries {
+		if entry.Instance != "" {
+			continue
+		}
+		delete(s.entries, key)
+		entry.Instance = instance
+		s.entries[entry.Key()] = entry
+	}
+}
+
+// Get returns the staging entry for a movie
+func (s *Store) Get(key radarr.MovieKey) (Entry, bool) {
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+// Plan decides what should happen to each deletion candidate given the grace period
+func (s *Store) Plan(candidates []radarr.MovieInfo, grace time.Duration, now time.Time) Plan {
+	var plan Plan
+	matching := make(map[radarr.MovieKey]bool, len(candidates))
+
+	for _, movie := range candidates {
+		matching[movie.Key()] = true
+
+		entry, ok := s.entries[movie.Key()]
+		switch {
+		case !ok:
+			plan.Stage = append(plan.Stage, movie)
+		case now.Sub(entry.StagedAt) >= grace:
+			plan.Ready = append(plan.Ready, movie)
+		default:
+			plan.Waiting = append(plan.Waiting, movie)
+		}
+	}
+
+	for key, entry := range s.entries {
+		if !matching[key] {
+			plan.Release = append(plan.Release, entry)
+		}
+	}
+
+	return plan
+}
+
+// Add records movies as staged at the given time
+func (s *Store) Add(movies []radarr.MovieInfo, now time.Time) {
+	for _, movie := range movies {
+		s.entries[movie.Key()] = Entry{
+			MovieID:  movie.ID,
+			Instance: movie.Instance,
+			Title:    movie.Title,
+			Year:     movie.Year,
+			StagedAt: now,
+		}
+	}
+}
+
+// Remove forgets staged movies
+func (s *Store) Remove(entries []Entry) {
+	for _, entry := range entries {
+		delete(s.entries, entry.Key())
+	}
+}
+
+// Save writes the store back to disk atomically
+func (s *Store) Save() error {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode staging state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("unable to create data directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write staging state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write staging state: %w", err)
+	}
+
+	return nil
+}
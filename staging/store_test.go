@@ -0,0 +1,87 @@
+package staging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s0up4200/arrbiter/radarr"
+)
+
+func TestPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staging.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	now := time.Now()
+	grace := 7 * 24 * time.Hour
+
+	old := radarr.MovieInfo{ID: 1, Title: "Staged long ago"}
+	recent := radarr.MovieInfo{ID: 2, Title: "Staged yesterday"}
+	watched := radarr.MovieInfo{ID: 3, Title: "Watched since"}
+	fresh := radarr.MovieInfo{ID: 4, Title: "New match"}
+
+	store.Add([]radarr.MovieInfo{old, watched}, now.Add(-10*24*time.Hour))
+	store.Add([]radarr.MovieInfo{recent}, now.Add(-24*time.Hour))
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save store: %v", err)
+	}
+
+	// Reload to make sure timestamps survive a round trip
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+
+	plan := store.Plan([]radarr.MovieInfo{old, recent, fresh}, grace, now)
+
+	if len(plan.Ready) != 1 || plan.Ready[0].ID != old.ID {
+		t.Errorf("expected movie 1 ready, got %+v", plan.Ready)
+	}
+	if len(plan.Waiting) != 1 || plan.Waiting[0].ID != recent.ID {
+		t.Errorf("expected movie 2 waiting, got %+v", plan.Waiting)
+	}
+	if len(plan.Stage) != 1 || plan.Stage[0].ID != fresh.ID {
+		t.Errorf("expected movie 4 staged, got %+v", plan.Stage)
+	}
+	if len(plan.Release) != 1 || plan.Release[0].MovieID != watched.ID {
+		t.Errorf("expected movie 3 released, got %+v", plan.Release)
+	}
+
+	store.Remove(plan.Release)
+	if _, ok := store.Get(watched.Key()); ok {
+		t.Error("expected released movie to be forgotten")
+	}
+}
+
+func TestInstances(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "staging.json"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	now := time.Now()
+	grace := 7 * 24 * time.Hour
+
+	// Movie IDs overlap between instances, staging one copy leaves the other alone
+	hd := radarr.MovieInfo{ID: 1, Instance: "hd", Title: "Dune"}
+	uhd := radarr.MovieInfo{ID: 1, Instance: "4k", Title: "Dune"}
+	legacy := radarr.MovieInfo{ID: 2, Title: "Staged before instances"}
+
+	store.Add([]radarr.MovieInfo{hd, legacy}, now.Add(-10*24*time.Hour))
+	store.AssignInstance("hd")
+	legacy.Instance = "hd"
+
+	plan := store.Plan([]radarr.MovieInfo{hd, uhd, legacy}, grace, now)
+	if len(plan.Ready) != 2 {
+		t.Errorf("expected the hd copy and the legacy entry ready, got %+v", plan.Ready)
+	}
+	if len(plan.Stage) != 1 || plan.Stage[0].Instance != "4k" {
+		t.Errorf("expected only the 4k copy staged, got %+v", plan.Stage)
+	}
+	if len(plan.Release) != 0 {
+		t.Errorf("expected nothing released, got %+v", plan.Release)
+	}
+}
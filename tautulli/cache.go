@@ -0,0 +1,217 @@
+package tautulli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HistoryCache keeps Tautulli history records on disk, keyed by row ID, so a
+// run only fetches the plays recorded since the last one. History deleted in
+// Tautulli stays in the cache until it is reset.
+type HistoryCache struct {
+	path    string
+	baseURL string
+
+	mu      sync.Mutex
+	history map[string]*cachedHistory // By media type
+}
+
+// cachedHistory is the synced history of one media type
+type cachedHistory struct {
+	LastRowID int64                   `json:"last_row_id"`
+	SyncedAt  time.Time               `json:"synced_at"`
+	Records   map[int64]HistoryRecord `json:"records"`
+}
+
+// cacheFile is the on-disk format of the cache
+type cacheFile struct {
+	BaseURL string                    `json:"base_url"`
+	History map[string]*cachedHistory `json:"history"`
+}
+
+// OpenHistoryCache loads the cache at path for the Tautulli server at baseURL.
+// A missing file, or one written for another server, is treated as empty.
+func OpenHistoryCache(path, baseURL string) (*HistoryCache, error) {
+	c := &HistoryCache{
+		path:    path,
+		baseURL: baseURL,
+		history: make(map[string]*cachedHistory),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history cache: %w", err)
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse history cache: %w", err)
+	}
+	if file.BaseURL == baseURL && file.History != nil {
+		c.history = file.History
+	}
+
+	return c, nil
+}
+
+// Reset forgets every cached record, so the next sync fetches the whole history
+func (c *HistoryCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = make(map[string]*cachedHistory)
+}
+
+// lastRowID returns the newest row ID synced for a media type, 0 when nothing is cached
+func (c *HistoryCache) lastRowID(mediaType string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if history, ok := c.history[mediaType]; ok {
+		return history.LastRowID
+	}
+	return 0
+}
+
+// add stores newly fetched records of a media type. Records already cached
+// are replaced, as Tautulli updates a play while it is grouped with others.
+func (c *HistoryCache) add(mediaType string, records []HistoryRecord, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	history, ok := c.history[mediaType]
+	if !ok {
+		history = &cachedHistory{Records: make(map[int64]HistoryRecord)}
+		c.history[mediaType] = history
+	}
+	for _, record := range records {
+		history.Records[record.RowID] = record
+		history.LastRowID = max(history.LastRowID, record.RowID)
+	}
+	history.SyncedAt = now
+}
+
+// records returns the cached records of a media type, newest first
+func (c *HistoryCache) records(mediaType string) []HistoryRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	history, ok := c.history[mediaType]
+	if !ok {
+		return nil
+	}
+	return slices.SortedFunc(maps.Values(history.Records), func(a, b HistoryRecord) int {
+		return cmp.Compare(b.RowID, a.RowID)
+	})
+}
+
+// Save writes the cache back to disk atomically
+func (c *HistoryCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(cacheFile{BaseURL: c.baseURL, History: c.history})
+	if err != nil {
+		return fmt.Errorf("failed to encode history cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("unable to create data directory: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write history cache: %w", err)
+	}
+
+	return nil
+}
+
+// SetHistoryCache makes the client read history through an on-disk cache
+func (c *Client) SetHistoryCache(cache *HistoryCache) {
+	c.cache = cache
+}
+
+// historyRecords returns every history record of a media type, from the
+// cache when there is one
+func (c *Client) historyRecords(ctx context.Context, mediaType string) ([]HistoryRecord, error) {
+	if c.cache == nil {
+		history, err := c.getAllHistory(ctx, mediaType)
+		if err != nil {
+			return nil, err
+		}
+		return history.Response.Data.Data, nil
+	}
+
+	fresh, live, err := c.getHistorySince(ctx, mediaType, c.cache.lastRowID(mediaType))
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.add(mediaType, fresh, time.Now())
+	if err := c.cache.Save(); err != nil {
+		// The records are still good for this run, the next one fetches them again
+		c.logger.Warn().Err(err).Msg("Failed to save Tautulli history cache")
+	}
+
+	records := c.cache.records(mediaType)
+
+	c.logger.Debug().
+		Int("fetched", len(fresh)).
+		Int("cached", len(records)).
+		Str("media_type", mediaType).
+		Msg("Synced Tautulli history cache")
+
+	// Plays still in progress have no row yet, use them without caching them
+	return append(live, records...), nil
+}
+
+// getHistorySince fetches the history records newer than lastRowID, newest
+// first. It stops after the first page that reaches records already seen,
+// returning that page's older records too so recent plays Tautulli has
+// updated since are refreshed. Plays still in progress are returned as live.
+func (c *Client) getHistorySince(ctx context.Context, mediaType string, lastRowID int64) ([]HistoryRecord, []HistoryRecord, error) {
+	pageSize := defaultHistoryLimit
+	var fresh, live []HistoryRecord
+
+	for start := 0; ; start += pageSize {
+		page, err := c.getHistory(ctx, historyOptions{
+			mediaType: mediaType,
+			limit:     pageSize,
+			start:     start,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		records := page.Response.Data.Data
+		done := len(records) < pageSize
+		for _, record := range records {
+			if record.RowID == 0 {
+				live = append(live, record)
+				continue
+			}
+			fresh = append(fresh, record)
+			if lastRowID > 0 && record.RowID <= lastRowID {
+				done = true
+			}
+		}
+
+		if done {
+			return fresh, live, nil
+		}
+	}
+}
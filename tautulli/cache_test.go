@@ -0,0 +1,111 @@
+package tautulli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyServer serves get_history from records, newest first, and counts
+// the history pages requested
+type historyServer struct {
+	records []HistoryRecord
+	pages   int
+}
+
+func (s *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var data any = map[string]any{}
+	if r.URL.Query().Get("cmd") == "get_history" {
+		s.pages++
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("length"))
+		end := min(start+length, len(s.records))
+		data = HistoryData{Data: s.records[min(start, end):end]}
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"response": map[string]any{"result": "success", "data": data},
+	})
+}
+
+func TestHistoryCache(t *testing.T) {
+	history := &historyServer{records: []HistoryRecord{
+		{RowID: 2, User: "alice", Title: "Heat", PercentComplete: 95},
+		{RowID: 1, User: "bob", Title: "Alien", PercentComplete: 40},
+	}}
+	server := httptest.NewServer(history)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "key", zerolog.Nop())
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "tautulli-history.json")
+	cache, err := OpenHistoryCache(path, server.URL)
+	require.NoError(t, err)
+	client.SetHistoryCache(cache)
+
+	records, err := client.historyRecords(context.Background(), mediaTypeMovie)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// A later run only adds what is new, plus plays in progress
+	history.records = append([]HistoryRecord{
+		{User: "carol", Title: "Heat", PercentComplete: 10},
+		{RowID: 3, User: "bob", Title: "Heat", PercentComplete: 100},
+	}, history.records...)
+	cache, err = OpenHistoryCache(path, server.URL)
+	require.NoError(t, err)
+	client.SetHistoryCache(cache)
+
+	records, err = client.historyRecords(context.Background(), mediaTypeMovie)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, "carol", records[0].User, "plays in progress come first")
+	assert.Equal(t, []int64{3, 2, 1}, []int64{records[1].RowID, records[2].RowID, records[3].RowID})
+
+	reopened, err := OpenHistoryCache(path, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), reopened.lastRowID(mediaTypeMovie))
+	assert.Len(t, reopened.records(mediaTypeMovie), 3, "plays in progress are not cached")
+
+	// A cache written for another server starts empty
+	other, err := OpenHistoryCache(path, "http://other:8181")
+	require.NoError(t, err)
+	assert.Empty(t, other.records(mediaTypeMovie))
+
+	reopened.Reset()
+	assert.Zero(t, reopened.lastRowID(mediaTypeMovie))
+}
+
+func TestGetHistorySince(t *testing.T) {
+	records := make([]HistoryRecord, 0, defaultHistoryLimit+10)
+	for rowID := int64(defaultHistoryLimit + 10); rowID > 0; rowID-- {
+		records = append(records, HistoryRecord{RowID: rowID})
+	}
+	history := &historyServer{records: records}
+	server := httptest.NewServer(history)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "key", zerolog.Nop())
+	require.NoError(t, err)
+
+	fresh, live, err := client.getHistorySince(context.Background(), mediaTypeMovie, 0)
+	require.NoError(t, err)
+	assert.Len(t, fresh, defaultHistoryLimit+10)
+	assert.Empty(t, live)
+	assert.Equal(t, 2, history.pages)
+
+	// Everything after the last sync is on the first page
+	history.pages = 0
+	fresh, _, err = client.getHistorySince(context.Background(), mediaTypeMovie, defaultHistoryLimit)
+	require.NoError(t, err)
+	assert.Len(t, fresh, defaultHistoryLimit, "the rest of the page reaching known records is refreshed")
+	assert.Equal(t, 1, history.pages)
+}
@@ -12,6 +12,8 @@ import (
 	"unicode"
 
 	"github.com/rs/zerolog"
+
+	"github.com/s0up4200/arrbiter/watch"
 )
 
 const (
@@ -23,15 +25,20 @@ const (
 
 	// imdbGUIDPrefix is the prefix for IMDB GUIDs in Plex.
 	imdbGUIDPrefix = "com.plexapp.agents.imdb://"
+
+	// Media types understood by get_history.
+	mediaTypeMovie   = "movie"
+	mediaTypeEpisode = "episode"
 )
 
 // historyOptions contains parameters for history queries.
 type historyOptions struct {
-	guid   string
-	search string
-	user   string
-	limit  int
-	start  int
+	mediaType string // "movie" when empty
+	guid      string
+	search    string
+	user      string
+	limit     int
+	start     int
 }
 
 // Client provides access to the Tautulli API for retrieving Plex watch history.
@@ -40,6 +47,7 @@ type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	logger     zerolog.Logger
+	cache      *HistoryCache // Optional, history is fetched in full without it
 }
 
 // NewClient creates a new Tautulli client and validates the connection.
@@ -138,7 +146,7 @@ func (c *Client) GetMovieWatchStatus(ctx context.Context, imdbID, tmdbID, title
 		return status, fmt.Errorf("finding movie records: %w", err)
 	}
 
-	c.processHistoryRecords(records, status, minWatchPercent)
+	c.processHistoryRecords(records, status, watch.Threshold{MinPercent: minWatchPercent})
 	return status, nil
 }
 
@@ -190,8 +198,13 @@ func (c *Client) getHistory(ctx context.Context, opts historyOptions) (*HistoryR
 		limit = defaultHistoryLimit
 	}
 
+	mediaType := opts.mediaType
+	if mediaType == "" {
+		mediaType = mediaTypeMovie
+	}
+
 	params := url.Values{
-		"media_type": {"movie"},
+		"media_type": {mediaType},
 		"length":     {strconv.Itoa(limit)},
 	}
 
@@ -226,19 +239,20 @@ func (c *Client) getHistory(ctx context.Context, opts historyOptions) (*HistoryR
 	return &history, nil
 }
 
-// processHistoryRecords processes history records to determine watch status
-func (c *Client) processHistoryRecords(records []HistoryRecord, status *MovieWatchStatus, minWatchPercent float64) {
+// processHistoryRecords processes history records to determine watch status.
+// The movie is watched once any user watched it past their threshold.
+func (c *Client) processHistoryRecords(records []HistoryRecord, status *MovieWatchStatus, threshold watch.Threshold) {
 	for _, record := range records {
 		// Update watch count
 		status.WatchCount++
 
 		// Check if watched
-		if record.IsWatched(minWatchPercent) {
+		progress := float64(record.PercentComplete)
+		if threshold.Watched(record.User, progress) {
 			status.Watched = true
 		}
 
 		// Update max progress
-		progress := float64(record.PercentComplete)
 		if progress > status.MaxProgress {
 			status.MaxProgress = progress
 		}
@@ -274,12 +288,13 @@ func (c *Client) GetMovieWatchStatusWithUsers(ctx context.Context, imdbID, tmdbI
 		return status, fmt.Errorf("finding movie records: %w", err)
 	}
 
-	c.processUserWatchData(records, status, minWatchPercent)
+	c.processUserWatchData(records, status, watch.Threshold{MinPercent: minWatchPercent})
 	return status, nil
 }
 
-// processUserWatchData processes history records and populates per-user watch data.
-func (c *Client) processUserWatchData(records []HistoryRecord, status *MovieWatchStatusWithUsers, minWatchPercent float64) {
+// processUserWatchData processes history records and populates per-user watch
+// data, judging each user against their own threshold.
+func (c *Client) processUserWatchData(records []HistoryRecord, status *MovieWatchStatusWithUsers, threshold watch.Threshold) {
 	for _, record := range records {
 		username := record.User
 		if username == "" {
@@ -297,12 +312,12 @@ func (c *Client) processUserWatchData(records []HistoryRecord, status *MovieWatc
 		userData.WatchCount++
 
 		// Check if watched
-		if record.IsWatched(minWatchPercent) {
+		progress := float64(record.PercentComplete)
+		if threshold.Watched(username, progress) {
 			userData.Watched = true
 		}
 
 		// Update max progress
-		progress := float64(record.PercentComplete)
 		if progress > userData.MaxProgress {
 			userData.MaxProgress = progress
 		}
@@ -315,13 +330,13 @@ func (c *Client) processUserWatchData(records []HistoryRecord, status *MovieWatc
 	}
 
 	// Update aggregate status
-	c.processHistoryRecords(records, &status.MovieWatchStatus, minWatchPercent)
+	c.processHistoryRecords(records, &status.MovieWatchStatus, threshold)
 }
 
 // BatchGetMovieWatchStatus gets watch status for multiple movies efficiently.
 // Deprecated: Use BatchGetMovieWatchStatusWithUsers for per-user data.
 func (c *Client) BatchGetMovieWatchStatus(ctx context.Context, movies []MovieIdentifier, minWatchPercent float64) (map[string]*MovieWatchStatus, error) {
-	allHistory, err := c.getAllHistory(ctx)
+	allHistory, err := c.getAllHistory(ctx, mediaTypeMovie)
 	if err != nil {
 		return nil, fmt.Errorf("getting all history: %w", err)
 	}
@@ -337,7 +352,7 @@ func (c *Client) BatchGetMovieWatchStatus(ctx context.Context, movies []MovieIde
 		}
 
 		records := c.findRecordsInIndices(movie, indices)
-		c.processHistoryRecords(records, status, minWatchPercent)
+		c.processHistoryRecords(records, status, watch.Threshold{MinPercent: minWatchPercent})
 
 		results[movie.IMDbID] = status
 	}
@@ -347,17 +362,32 @@ func (c *Client) BatchGetMovieWatchStatus(ctx context.Context, movies []MovieIde
 
 // BatchGetMovieWatchStatusWithUsers gets detailed watch status with per-user data for multiple movies.
 func (c *Client) BatchGetMovieWatchStatusWithUsers(ctx context.Context, movies []MovieIdentifier, minWatchPercent float64) (map[string]*MovieWatchStatusWithUsers, error) {
-	allHistory, err := c.getAllHistory(ctx)
+	statuses, err := c.movieWatchStatuses(ctx, movies, watch.Threshold{MinPercent: minWatchPercent})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*MovieWatchStatusWithUsers, len(movies))
+	for i, movie := range movies {
+		results[movie.IMDbID] = statuses[i]
+	}
+
+	return results, nil
+}
+
+// movieWatchStatuses gets the watch status with per-user data of each movie, in the order given.
+func (c *Client) movieWatchStatuses(ctx context.Context, movies []MovieIdentifier, threshold watch.Threshold) ([]*MovieWatchStatusWithUsers, error) {
+	allHistory, err := c.historyRecords(ctx, mediaTypeMovie)
 	if err != nil {
 		return nil, fmt.Errorf("getting all history: %w", err)
 	}
 
-	c.logger.Debug().Int("record_count", len(allHistory.Response.Data.Data)).Msg("Retrieved history records from Tautulli")
+	c.logger.Debug().Int("record_count", len(allHistory)).Msg("Retrieved history records from Tautulli")
 
 	// Build lookup indices
-	indices := c.buildHistoryIndices(allHistory.Response.Data.Data)
+	indices := c.buildHistoryIndices(allHistory)
 
-	results := make(map[string]*MovieWatchStatusWithUsers, len(movies))
+	results := make([]*MovieWatchStatusWithUsers, 0, len(movies))
 	for _, movie := range movies {
 		status := &MovieWatchStatusWithUsers{
 			MovieWatchStatus: MovieWatchStatus{
@@ -368,9 +398,9 @@ func (c *Client) BatchGetMovieWatchStatusWithUsers(ctx context.Context, movies [
 		}
 
 		records := c.findRecordsInIndices(movie, indices)
-		c.processUserWatchData(records, status, minWatchPercent)
+		c.processUserWatchData(records, status, threshold)
 
-		results[movie.IMDbID] = status
+		results = append(results, status)
 	}
 
 	return results, nil
@@ -386,8 +416,8 @@ type historyIndices struct {
 	byNormalizedNoDigits map[string][]HistoryRecord
 }
 
-// getAllHistory fetches all movie history records.
-func (c *Client) getAllHistory(ctx context.Context) (*HistoryResponse, error) {
+// getAllHistory fetches all history records of a media type.
+func (c *Client) getAllHistory(ctx context.Context, mediaType string) (*HistoryResponse, error) {
 	pageSize := defaultHistoryLimit
 	if pageSize <= 0 {
 		pageSize = 1000
@@ -402,8 +432,9 @@ func (c *Client) getAllHistory(ctx context.Context) (*HistoryResponse, error) {
 
 	for {
 		page, err := c.getHistory(ctx, historyOptions{
-			limit: pageSize,
-			start: start,
+			mediaType: mediaType,
+			limit:     pageSize,
+			start:     start,
 		})
 		if err != nil {
 			return nil, err
@@ -430,6 +461,7 @@ func (c *Client) getAllHistory(ctx context.Context) (*HistoryResponse, error) {
 
 	c.logger.Debug().
 		Int("records", len(allRecords)).
+		Str("media_type", mediaType).
 		Msg("Aggregated Tautulli history records")
 
 	if result == "" {
@@ -0,0 +1,54 @@
+package tautulli
+
+import (
+	"context"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// Name identifies Tautulli as a watch history provider.
+func (c *Client) Name() string {
+	return "tautulli"
+}
+
+// MovieWatchStatus implements watch.Provider using Plex history from Tautulli.
+func (c *Client) MovieWatchStatus(ctx context.Context, movies []watch.MovieIdentifier, threshold watch.Threshold) (map[int64]*watch.MovieStatus, error) {
+	identifiers := make([]MovieIdentifier, 0, len(movies))
+	for _, movie := range movies {
+		identifiers = append(identifiers, MovieIdentifier{
+			IMDbID: movie.IMDbID,
+			TMDbID: movie.TMDbID,
+			Title:  movie.Title,
+		})
+	}
+
+	statuses, err := c.movieWatchStatuses(ctx, identifiers, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int64]*watch.MovieStatus, len(movies))
+	for i, movie := range movies {
+		status := statuses[i]
+
+		result := &watch.MovieStatus{
+			Watched:     status.Watched,
+			WatchCount:  status.WatchCount,
+			LastWatched: status.LastWatched,
+			MaxProgress: status.MaxProgress,
+			UserData:    make(map[string]*watch.UserStatus, len(status.UserData)),
+		}
+		for username, userData := range status.UserData {
+			result.UserData[username] = &watch.UserStatus{
+				Username:    userData.Username,
+				Watched:     userData.Watched,
+				WatchCount:  userData.WatchCount,
+				LastWatched: userData.LastWatched,
+				MaxProgress: userData.MaxProgress,
+			}
+		}
+		results[movie.TMDbID] = result
+	}
+
+	return results, nil
+}
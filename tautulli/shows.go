@@ -0,0 +1,162 @@
+package tautulli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s0up4200/arrbiter/watch"
+)
+
+// tvdbGUIDPrefix is the prefix for TheTVDB episode GUIDs in Plex, followed by
+// "<series id>/<season>/<episode>".
+const tvdbGUIDPrefix = "com.plexapp.agents.thetvdb://"
+
+// ShowIdentifier contains the identifiers used to look up a show in Tautulli.
+type ShowIdentifier struct {
+	TVDbID int64  // TheTVDB series ID
+	Title  string // Show title for fallback matching
+}
+
+// EpisodeWatchStatus aggregates episode history for a show or one of its seasons.
+type EpisodeWatchStatus struct {
+	WatchCount      int                              `json:"watch_count"`
+	LastWatched     time.Time                        `json:"last_watched"`
+	EpisodesWatched int                              `json:"episodes_watched"` // Distinct episodes watched past the threshold
+	UserData        map[string]*UserEpisodeWatchData `json:"user_data"`
+}
+
+// UserEpisodeWatchData contains episode watch information for a specific user.
+type UserEpisodeWatchData struct {
+	Username        string    `json:"username"`
+	WatchCount      int       `json:"watch_count"`
+	LastWatched     time.Time `json:"last_watched"`
+	EpisodesWatched int       `json:"episodes_watched"`
+}
+
+// ShowWatchStatus contains watch information for a show and each of its seasons.
+type ShowWatchStatus struct {
+	EpisodeWatchStatus
+	Seasons map[int]*EpisodeWatchStatus `json:"seasons"`
+}
+
+// BatchGetShowWatchStatus gets episode watch status for multiple shows, keyed by TVDB ID.
+// An episode counts as watched once a play of it reaches the threshold of the user watching.
+func (c *Client) BatchGetShowWatchStatus(ctx context.Context, shows []ShowIdentifier, threshold watch.Threshold) (map[int64]*ShowWatchStatus, error) {
+	allHistory, err := c.historyRecords(ctx, mediaTypeEpisode)
+	if err != nil {
+		return nil, fmt.Errorf("getting episode history: %w", err)
+	}
+
+	c.logger.Debug().Int("record_count", len(allHistory)).Msg("Retrieved episode history records from Tautulli")
+
+	// Index episode records by TVDB series ID and by normalized show title
+	byTVDB := make(map[int64][]HistoryRecord)
+	byTitle := make(map[string][]HistoryRecord)
+	for _, record := range allHistory {
+		if tvdbID := tvdbSeriesID(record.GUID); tvdbID != 0 {
+			byTVDB[tvdbID] = append(byTVDB[tvdbID], record)
+		}
+		if title := normalizeTitleWithDigits(record.GrandparentTitle, false); title != "" {
+			byTitle[title] = append(byTitle[title], record)
+		}
+	}
+
+	results := make(map[int64]*ShowWatchStatus, len(shows))
+	for _, show := range shows {
+		records, ok := byTVDB[show.TVDbID]
+		if !ok {
+			records = byTitle[normalizeTitleWithDigits(show.Title, false)]
+		}
+		results[show.TVDbID] = processEpisodeRecords(records, threshold)
+	}
+
+	return results, nil
+}
+
+// processEpisodeRecords aggregates episode history per show, season and user.
+func processEpisodeRecords(records []HistoryRecord, threshold watch.Threshold) *ShowWatchStatus {
+	status := &ShowWatchStatus{
+		EpisodeWatchStatus: newEpisodeWatchStatus(),
+		Seasons:            make(map[int]*EpisodeWatchStatus),
+	}
+
+	// Episodes already counted as watched, per status and user
+	type episodeKey struct {
+		status *EpisodeWatchStatus
+		user   string
+		season int
+		number int
+	}
+	counted := make(map[episodeKey]bool)
+
+	for _, record := range records {
+		seasonNumber := record.GetSeasonNumber()
+		season, ok := status.Seasons[seasonNumber]
+		if !ok {
+			season = new(EpisodeWatchStatus)
+			*season = newEpisodeWatchStatus()
+			status.Seasons[seasonNumber] = season
+		}
+
+		watched := threshold.Watched(record.User, float64(record.PercentComplete))
+		episodeNumber := record.GetEpisodeNumber()
+		watchTime := record.GetWatchedTime()
+
+		for _, s := range []*EpisodeWatchStatus{&status.EpisodeWatchStatus, season} {
+			s.WatchCount++
+			if watchTime.After(s.LastWatched) {
+				s.LastWatched = watchTime
+			}
+
+			var userData *UserEpisodeWatchData
+			if record.User != "" {
+				userData, ok = s.UserData[record.User]
+				if !ok {
+					userData = &UserEpisodeWatchData{Username: record.User}
+					s.UserData[record.User] = userData
+				}
+				userData.WatchCount++
+				if watchTime.After(userData.LastWatched) {
+					userData.LastWatched = watchTime
+				}
+			}
+
+			if !watched || episodeNumber < 0 {
+				continue
+			}
+			if key := (episodeKey{s, "", seasonNumber, episodeNumber}); !counted[key] {
+				counted[key] = true
+				s.EpisodesWatched++
+			}
+			if userData != nil {
+				if key := (episodeKey{s, record.User, seasonNumber, episodeNumber}); !counted[key] {
+					counted[key] = true
+					userData.EpisodesWatched++
+				}
+			}
+		}
+	}
+
+	return status
+}
+
+func newEpisodeWatchStatus() EpisodeWatchStatus {
+	return EpisodeWatchStatus{UserData: make(map[string]*UserEpisodeWatchData)}
+}
+
+// tvdbSeriesID extracts the TVDB series ID from a legacy TheTVDB agent GUID, or 0.
+func tvdbSeriesID(guid string) int64 {
+	rest, ok := strings.CutPrefix(guid, tvdbGUIDPrefix)
+	if !ok {
+		return 0
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	tvdbID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return tvdbID
+}
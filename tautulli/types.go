@@ -33,6 +33,7 @@ type apiResponse struct {
 
 // HistoryRecord represents a single history entry from Tautulli.
 type HistoryRecord struct {
+	RowID           int64           `json:"row_id"` // 0 while the play is still in progress
 	UserID          int             `json:"user_id"`
 	User            string          `json:"user"`
 	RatingKey       json.RawMessage `json:"rating_key"` // Can be string or number
@@ -50,6 +51,10 @@ type HistoryRecord struct {
 	ViewOffset      int             `json:"view_offset"`      // Seconds watched
 	IMDbID          string          `json:"imdb_id"`
 	TMDbID          string          `json:"tmdb_id"`
+	// Episode fields, empty for movies
+	GrandparentTitle string          `json:"grandparent_title"`  // Show title
+	ParentMediaIndex json.RawMessage `json:"parent_media_index"` // Season number, string or number
+	MediaIndex       json.RawMessage `json:"media_index"`        // Episode number, string or number
 }
 
 // GetWatchedTime returns the time when the item was watched.
@@ -86,6 +91,37 @@ func (h *HistoryRecord) GetRatingKey() string {
 	return ""
 }
 
+// GetSeasonNumber returns the season number of an episode record, or -1 if unknown.
+func (h *HistoryRecord) GetSeasonNumber() int {
+	return parseIndex(h.ParentMediaIndex)
+}
+
+// GetEpisodeNumber returns the episode number of an episode record, or -1 if unknown.
+func (h *HistoryRecord) GetEpisodeNumber() int {
+	return parseIndex(h.MediaIndex)
+}
+
+// parseIndex parses a media index that Tautulli sends as a number, a string or "".
+func parseIndex(raw json.RawMessage) int {
+	if len(raw) == 0 {
+		return -1
+	}
+
+	var num int
+	if err := json.Unmarshal(raw, &num); err == nil {
+		return num
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		if num, err := strconv.Atoi(str); err == nil {
+			return num
+		}
+	}
+
+	return -1
+}
+
 // MovieWatchStatus contains aggregated watch information for a movie.
 type MovieWatchStatus struct {
 	Watched     bool      `json:"watched"`
@@ -0,0 +1,35 @@
+package tautulli
+
+import (
+	"context"
+	"fmt"
+)
+
+// User is a Plex account known to Tautulli. History records name users by
+// their friendly name, which Tautulli lets the admin change.
+type User struct {
+	UserID       int64  `json:"user_id"` // Plex account ID
+	Username     string `json:"username"`
+	FriendlyName string `json:"friendly_name"`
+	Email        string `json:"email"`
+}
+
+// GetUsers returns every user Tautulli knows about.
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	var result struct {
+		Response struct {
+			Result  string `json:"result"`
+			Message string `json:"message"`
+			Data    []User `json:"data"`
+		} `json:"response"`
+	}
+
+	if err := c.doAPIRequest(ctx, "get_users", nil, &result); err != nil {
+		return nil, fmt.Errorf("get_users API call: %w", err)
+	}
+	if result.Response.Result != "success" {
+		return nil, fmt.Errorf("%w: %s", ErrAPIFailure, result.Response.Message)
+	}
+
+	return result.Response.Data, nil
+}
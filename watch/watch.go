@@ -0,0 +1,175 @@
+// Package watch defines the watch history providers movies are enriched from,
+// such as Tautulli for Plex or Jellyfin and Emby, and combines their results.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MovieIdentifier contains the identifiers used to look up a movie's watch history
+type MovieIdentifier struct {
+	IMDbID string // IMDB ID (e.g., "tt1234567")
+	TMDbID int64  // The Movie Database ID
+	Title  string // Movie title for fallback matching
+}
+
+// MovieStatus is the watch history of one movie, aggregated across users
+type MovieStatus struct {
+	Watched     bool
+	WatchCount  int
+	LastWatched time.Time
+	MaxProgress float64 // Highest percentage watched (0-100)
+	UserData    map[string]*UserStatus
+}
+
+// UserStatus is the watch history of one movie for one user
+type UserStatus struct {
+	Username    string
+	Watched     bool
+	WatchCount  int
+	LastWatched time.Time
+	MaxProgress float64 // Highest percentage watched (0-100)
+}
+
+// DefaultMinPercent is how much of a movie must be watched for it to count as
+// watched when nothing is configured
+const DefaultMinPercent = 85.0
+
+// Threshold decides how much of a movie a user must watch for it to count as
+// watched. Every provider and every filter helper uses the same threshold.
+type Threshold struct {
+	MinPercent float64            // Default for every user
+	Users      map[string]float64 // Per-user overrides, keyed by username
+}
+
+// For returns the threshold of a user. Usernames are compared case-insensitively.
+func (t Threshold) For(username string) float64 {
+	if percent, ok := t.Users[username]; ok {
+		return percent
+	}
+	for name, percent := range t.Users {
+		if strings.EqualFold(name, username) {
+			return percent
+		}
+	}
+	return t.MinPercent
+}
+
+// Watched reports whether a user's progress counts as watched
+func (t Threshold) Watched(username string, progress float64) bool {
+	return progress > 0 && progress >= t.For(username)
+}
+
+// Provider looks up the watch history of movies
+type Provider interface {
+	// Name identifies the provider in logs and errors
+	Name() string
+
+	// MovieWatchStatus returns the watch status of each movie keyed by TMDB ID.
+	// A movie counts as watched by a user once a play reaches their threshold.
+	MovieWatchStatus(ctx context.Context, movies []MovieIdentifier, threshold Threshold) (map[int64]*MovieStatus, error)
+}
+
+// multiProvider merges the watch history of several providers
+type multiProvider struct {
+	providers []Provider
+}
+
+// Combine returns a provider that asks every provider and merges their results,
+// so users on different media servers all count. A single provider is returned as is.
+func Combine(providers ...Provider) Provider {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return &multiProvider{providers: providers}
+}
+
+// Name returns the names of the combined providers
+func (m *multiProvider) Name() string {
+	names := make([]string, 0, len(m.providers))
+	for _, provider := range m.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, "+")
+}
+
+// MovieWatchStatus merges the results of every provider. When some providers
+// fail, the merged results of the others are returned along with their errors.
+func (m *multiProvider) MovieWatchStatus(ctx context.Context, movies []MovieIdentifier, threshold Threshold) (map[int64]*MovieStatus, error) {
+	merged := make(map[int64]*MovieStatus)
+	var errs []error
+
+	for _, provider := range m.providers {
+		statuses, err := provider.MovieWatchStatus(ctx, movies, threshold)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		for tmdbID, status := range statuses {
+			merged[tmdbID] = Merge(merged[tmdbID], status)
+		}
+	}
+
+	if len(errs) == len(m.providers) {
+		return nil, errors.Join(errs...)
+	}
+	return merged, errors.Join(errs...)
+}
+
+// Merge combines the watch status of a movie from two providers. Either may be nil.
+func Merge(a, b *MovieStatus) *MovieStatus {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	merged := &MovieStatus{
+		Watched:     a.Watched || b.Watched,
+		WatchCount:  a.WatchCount + b.WatchCount,
+		LastWatched: latest(a.LastWatched, b.LastWatched),
+		MaxProgress: max(a.MaxProgress, b.MaxProgress),
+		UserData:    make(map[string]*UserStatus, len(a.UserData)+len(b.UserData)),
+	}
+	for _, data := range []map[string]*UserStatus{a.UserData, b.UserData} {
+		for username, user := range data {
+			merged.UserData[username] = MergeUser(merged.UserData[username], user)
+		}
+	}
+
+	return merged
+}
+
+// MergeUser combines two watch statuses of one user for the same movie into
+// a new status. Either may be nil.
+func MergeUser(a, b *UserStatus) *UserStatus {
+	if a == nil {
+		a, b = b, a
+	}
+	if a == nil {
+		return nil
+	}
+	merged := *a
+	if b == nil {
+		return &merged
+	}
+
+	merged.Watched = a.Watched || b.Watched
+	merged.WatchCount += b.WatchCount
+	merged.LastWatched = latest(a.LastWatched, b.LastWatched)
+	merged.MaxProgress = max(a.MaxProgress, b.MaxProgress)
+	return &merged
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,83 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticProvider struct {
+	name     string
+	statuses map[int64]*MovieStatus
+	err      error
+}
+
+func (p *staticProvider) Name() string { return p.name }
+
+func (p *staticProvider) MovieWatchStatus(context.Context, []MovieIdentifier, Threshold) (map[int64]*MovieStatus, error) {
+	return p.statuses, p.err
+}
+
+func TestCombine(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	plex := &staticProvider{name: "tautulli", statuses: map[int64]*MovieStatus{
+		1: {Watched: true, WatchCount: 1, LastWatched: early, MaxProgress: 95, UserData: map[string]*UserStatus{
+			"alice": {Username: "alice", Watched: true, WatchCount: 1, LastWatched: early, MaxProgress: 95},
+		}},
+		2: {UserData: map[string]*UserStatus{}},
+	}}
+	jellyfin := &staticProvider{name: "jellyfin", statuses: map[int64]*MovieStatus{
+		1: {WatchCount: 1, LastWatched: late, MaxProgress: 30, UserData: map[string]*UserStatus{
+			"alice": {Username: "alice", WatchCount: 1, LastWatched: late, MaxProgress: 30},
+			"bob":   {Username: "bob", MaxProgress: 10},
+		}},
+	}}
+
+	combined := Combine(plex, jellyfin)
+	assert.Equal(t, "tautulli+jellyfin", combined.Name())
+
+	statuses, err := combined.MovieWatchStatus(context.Background(), nil, Threshold{MinPercent: 85})
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	movie := statuses[1]
+	assert.True(t, movie.Watched)
+	assert.Equal(t, 2, movie.WatchCount)
+	assert.Equal(t, late, movie.LastWatched)
+	assert.Equal(t, float64(95), movie.MaxProgress)
+	require.Len(t, movie.UserData, 2)
+	assert.True(t, movie.UserData["alice"].Watched)
+	assert.Equal(t, 2, movie.UserData["alice"].WatchCount)
+	assert.Equal(t, late, movie.UserData["alice"].LastWatched)
+
+	// The providers' own data is left untouched
+	assert.Equal(t, 1, plex.statuses[1].UserData["alice"].WatchCount)
+
+	t.Run("partial failure", func(t *testing.T) {
+		broken := &staticProvider{name: "emby", err: errors.New("unreachable")}
+		statuses, err := Combine(plex, broken).MovieWatchStatus(context.Background(), nil, Threshold{MinPercent: 85})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "emby")
+		assert.Len(t, statuses, 2)
+
+		statuses, err = Combine(broken, broken).MovieWatchStatus(context.Background(), nil, Threshold{MinPercent: 85})
+		require.Error(t, err)
+		assert.Nil(t, statuses)
+	})
+}
+
+func TestThreshold(t *testing.T) {
+	threshold := Threshold{MinPercent: 85, Users: map[string]float64{"grandma": 60}}
+
+	assert.Equal(t, float64(85), threshold.For("alice"))
+	assert.Equal(t, float64(60), threshold.For("Grandma"))
+	assert.True(t, threshold.Watched("grandma", 70))
+	assert.False(t, threshold.Watched("alice", 70))
+	assert.False(t, Threshold{}.Watched("alice", 0), "no progress is never watched")
+}